@@ -0,0 +1,207 @@
+// Command hptools-wm runs the window operations of hptools on sway and i3.
+// The backend comes from -backend, the linux.backend config setting or the
+// environment, in that order.
+//
+//	hptools-wm monitors
+//	hptools-wm desktops
+//	hptools-wm info <pid>
+//	hptools-wm size <pid> <width> <height>
+//	hptools-wm position <pid> <x> <y> <width> <height>
+//	hptools-wm place <pid> <placement>
+//	hptools-wm desktop <pid> <index>
+//	hptools-wm thumbnail <pid> <maxWidth> <maxHeight> > preview.png
+//	hptools-wm layout <name>
+//	hptools-wm watch
+//
+// watch runs until interrupted and applies the displayLayouts of the config
+// when the X server reports a monitor change.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"hptools/internal/config"
+	"hptools/internal/logging"
+	"hptools/internal/models"
+	"hptools/internal/sway"
+	"hptools/internal/thumbnail"
+	"hptools/internal/x11"
+)
+
+func main() {
+	backend := flag.String("backend", "", `window manager backend, "sway" or "i3"; overrides linux.backend`)
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: hptools-wm [-backend sway|i3] monitors|desktops|info|size|position|place|desktop|thumbnail|layout|watch [args]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(config.GetConfigPath())
+	if err != nil {
+		log.Printf("Warning: Failed to load config, using defaults: %v", err)
+		cfg = config.Default()
+	}
+	// Results go to stdout, so logs go to stderr
+	logger := logging.NewLoggerTo(cfg, os.Stderr)
+
+	if *backend == "" {
+		*backend = cfg.Linux.Backend
+	}
+	wm, closeConn, err := sway.Connect(sway.Backend(*backend), logging.WithComponent(logger, "sway"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeConn()
+	if err := wm.SetBoundsPolicy(cfg.Placement.BoundsPolicy); err != nil {
+		log.Printf("Warning: Invalid bounds policy, rejecting offscreen rects: %v", err)
+	}
+
+	if err := run(wm, cfg, logger, flag.Arg(0), flag.Args()[1:]); err != nil {
+		closeConn()
+		log.Fatal(err)
+	}
+}
+
+// run executes one command and prints its result as JSON
+func run(wm *sway.WindowManager, cfg *config.Config, logger *slog.Logger, command string, args []string) error {
+	ints, err := intArgs(command, args)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "monitors":
+		monitors, err := wm.GetMonitors()
+		if err != nil {
+			return err
+		}
+		return printJSON(monitors)
+	case "desktops":
+		desktops, err := wm.GetVirtualDesktops()
+		if err != nil {
+			return err
+		}
+		return printJSON(desktops)
+	case "info":
+		info, err := wm.GetWindowInfo(ints[0])
+		if err != nil {
+			return err
+		}
+		return printJSON(info)
+	case "size":
+		return wm.SetWindowSize(ints[0], ints[1], ints[2])
+	case "position":
+		return wm.SetWindowPosition(ints[0], ints[1], ints[2], ints[3], ints[4])
+	case "place":
+		return wm.ApplyPlacement(ints[0], models.Placement(args[1]))
+	case "desktop":
+		return wm.MoveWindowToDesktop(ints[0], ints[1])
+	case "thumbnail":
+		return writeThumbnail(wm, ints[0], ints[1], ints[2])
+	case "layout":
+		for _, layout := range cfg.Layouts {
+			if layout.Name == args[0] {
+				return wm.ApplyLayout(layout)
+			}
+		}
+		return fmt.Errorf("layout %q not found", args[0])
+	case "watch":
+		return watchDisplays(wm, cfg, logger)
+	}
+	return fmt.Errorf("unknown command %q", command)
+}
+
+// commandArgs is the number of arguments of each command and how many of
+// them, from the start, are integers
+var commandArgs = map[string]struct{ count, ints int }{
+	"monitors":  {0, 0},
+	"desktops":  {0, 0},
+	"info":      {1, 1},
+	"size":      {3, 3},
+	"position":  {5, 5},
+	"place":     {2, 1},
+	"desktop":   {2, 2},
+	"thumbnail": {3, 3},
+	"layout":    {1, 0},
+	"watch":     {0, 0},
+}
+
+// intArgs checks the argument count of a command and parses its integers
+func intArgs(command string, args []string) ([]int, error) {
+	spec, ok := commandArgs[command]
+	if !ok {
+		return nil, fmt.Errorf("unknown command %q", command)
+	}
+	if len(args) != spec.count {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", command, spec.count, len(args))
+	}
+
+	ints := make([]int, spec.ints)
+	for i := range ints {
+		n, err := strconv.Atoi(args[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: argument %d: %w", command, i+1, err)
+		}
+		ints[i] = n
+	}
+	return ints, nil
+}
+
+// writeThumbnail captures the main window of a process from the X server and
+// writes it to stdout as a PNG scaled to fit maxWidth x maxHeight
+func writeThumbnail(wm *sway.WindowManager, pid, maxWidth, maxHeight int) error {
+	if maxWidth <= 0 || maxHeight <= 0 {
+		return fmt.Errorf("invalid thumbnail size %dx%d", maxWidth, maxHeight)
+	}
+	window, err := wm.X11Window(pid)
+	if err != nil {
+		return err
+	}
+
+	conn, err := x11.Open()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	img, err := conn.CaptureWindow(window)
+	if err != nil {
+		return fmt.Errorf("capturing window %#x: %w", window, err)
+	}
+	return png.Encode(os.Stdout, thumbnail.Downscale(img, maxWidth, maxHeight))
+}
+
+// watchDisplays applies display layouts on monitor changes until the process
+// is interrupted
+func watchDisplays(wm *sway.WindowManager, cfg *config.Config, logger *slog.Logger) error {
+	watcher := sway.NewDisplayWatcher(wm, cfg.Layouts, cfg.DisplayLayouts, logging.WithComponent(logger, "display"))
+	if err := watcher.Start(); err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	return nil
+}
+
+// printJSON writes a result to stdout
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
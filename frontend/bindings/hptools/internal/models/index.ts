@@ -2,6 +2,31 @@
 // This file is automatically generated. DO NOT EDIT
 
 export {
+    AdjustmentKind,
+    Anchor,
+    LaunchRequest,
+    LaunchResult,
+    LaunchTarget,
+    MetricKey,
+    MonitorInfo,
+    Placement,
+    PriorityClass,
+    ProcessAction,
+    ProcessActionResult,
+    ProcessDetails,
     ProcessInfo,
-    WindowInfo
+    ProcessMetrics,
+    Rect,
+    TriggerCondition,
+    TriggerNotification,
+    VirtualDesktop,
+    WindowAdjustment,
+    WindowEntry,
+    WindowInfo,
+    WindowMatch,
+    Workspace,
+    WorkspaceApp,
+    WorkspaceAppState,
+    WorkspaceAppStatus,
+    WorkspaceResult
 } from "./models.js";
@@ -5,6 +5,433 @@
 // @ts-ignore: Unused imports
 import { Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as time$0 from "../../../time/models.js";
+
+/**
+ * AdjustmentKind names a relative window operation
+ */
+export enum AdjustmentKind {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    /**
+     * AdjustMoveBy moves the window by DX, DY
+     */
+    AdjustMoveBy = "move-by",
+
+    /**
+     * AdjustResizeBy grows the window by DWidth, DHeight around Anchor
+     */
+    AdjustResizeBy = "resize-by",
+
+    /**
+     * AdjustScale sets the size to Percent of the current size around Anchor
+     */
+    AdjustScale = "scale",
+
+    /**
+     * AdjustFitAspect makes the window as large as fits in Width x Height
+     * while keeping its aspect ratio. A zero Width or Height is unbounded.
+     */
+    AdjustFitAspect = "fit-aspect",
+};
+
+/**
+ * Anchor names the point of a window that stays fixed while it is resized
+ */
+export enum Anchor {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    AnchorTopLeft = "top-left",
+
+    AnchorTop = "top",
+
+    AnchorTopRight = "top-right",
+
+    AnchorLeft = "left",
+
+    AnchorCenter = "center",
+
+    AnchorRight = "right",
+
+    AnchorBottomLeft = "bottom-left",
+
+    AnchorBottom = "bottom",
+
+    AnchorBottomRight = "bottom-right",
+};
+
+/**
+ * LaunchRequest starts an application and places its main window once it
+ * appears. TimeoutMS limits the wait for the window; 0 uses the default.
+ */
+export class LaunchRequest {
+    "path": string;
+    "args"?: string;
+    "workingDir"?: string;
+    "placement"?: Placement;
+    "rect"?: Rect | null;
+    "monitor"?: number | null;
+    "timeoutMs"?: number;
+
+    /** Creates a new LaunchRequest instance. */
+    constructor($$source: Partial<LaunchRequest> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LaunchRequest instance from a string or object.
+     */
+    static createFrom($$source: any = {}): LaunchRequest {
+        const $$createField4_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("rect" in $$parsedSource) {
+            $$parsedSource["rect"] = $$createField4_0($$parsedSource["rect"]);
+        }
+        return new LaunchRequest($$parsedSource as Partial<LaunchRequest>);
+    }
+}
+
+/**
+ * LaunchResult identifies the window a launch produced. PID is the process
+ * that owns the window, which may be a child of the started process.
+ */
+export class LaunchResult {
+    "pid": number;
+    "handle": number;
+    "title": string;
+
+    /** Creates a new LaunchResult instance. */
+    constructor($$source: Partial<LaunchResult> = {}) {
+        if (!("pid" in $$source)) {
+            this["pid"] = 0;
+        }
+        if (!("handle" in $$source)) {
+            this["handle"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LaunchResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): LaunchResult {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new LaunchResult($$parsedSource as Partial<LaunchResult>);
+    }
+}
+
+/**
+ * LaunchTarget is what to start: an executable, a shortcut (.lnk) or a
+ * shell: URI, with optional arguments and working directory
+ */
+export class LaunchTarget {
+    "path": string;
+    "args"?: string;
+    "workingDir"?: string;
+
+    /** Creates a new LaunchTarget instance. */
+    constructor($$source: Partial<LaunchTarget> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LaunchTarget instance from a string or object.
+     */
+    static createFrom($$source: any = {}): LaunchTarget {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new LaunchTarget($$parsedSource as Partial<LaunchTarget>);
+    }
+}
+
+/**
+ * MetricKey names a ProcessMetrics field processes can be ranked by
+ */
+export enum MetricKey {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    MetricCPU = "cpu",
+
+    MetricWorkingSet = "workingSet",
+
+    MetricPrivateBytes = "privateBytes",
+
+    MetricHandles = "handles",
+
+    MetricGDIObjects = "gdiObjects",
+
+    MetricUserObjects = "userObjects",
+};
+
+/**
+ * MonitorInfo represents a connected display
+ */
+export class MonitorInfo {
+    "handle": number;
+    "name": string;
+    "primary": boolean;
+    "bounds": Rect;
+    "workArea": Rect;
+
+    /** Creates a new MonitorInfo instance. */
+    constructor($$source: Partial<MonitorInfo> = {}) {
+        if (!("handle" in $$source)) {
+            this["handle"] = 0;
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("primary" in $$source)) {
+            this["primary"] = false;
+        }
+        if (!("bounds" in $$source)) {
+            this["bounds"] = (new Rect());
+        }
+        if (!("workArea" in $$source)) {
+            this["workArea"] = (new Rect());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MonitorInfo instance from a string or object.
+     */
+    static createFrom($$source: any = {}): MonitorInfo {
+        const $$createField3_0 = $$createType0;
+        const $$createField4_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("bounds" in $$parsedSource) {
+            $$parsedSource["bounds"] = $$createField3_0($$parsedSource["bounds"]);
+        }
+        if ("workArea" in $$parsedSource) {
+            $$parsedSource["workArea"] = $$createField4_0($$parsedSource["workArea"]);
+        }
+        return new MonitorInfo($$parsedSource as Partial<MonitorInfo>);
+    }
+}
+
+/**
+ * Placement names a predefined window placement relative to a monitor
+ */
+export enum Placement {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    /**
+     * PlacementLeftHalf fills the left half of the monitor work area
+     */
+    PlacementLeftHalf = "left-half",
+
+    /**
+     * PlacementRightHalf fills the right half of the monitor work area
+     */
+    PlacementRightHalf = "right-half",
+
+    /**
+     * PlacementCenter centers the window in the work area, keeping its size
+     */
+    PlacementCenter = "center",
+
+    /**
+     * PlacementLeftThird fills the left third of the monitor work area
+     */
+    PlacementLeftThird = "left-third",
+
+    /**
+     * PlacementLeftTwoThirds fills the left two thirds of the monitor work area
+     */
+    PlacementLeftTwoThirds = "left-two-thirds",
+
+    /**
+     * PlacementRightThird fills the right third of the monitor work area
+     */
+    PlacementRightThird = "right-third",
+
+    /**
+     * PlacementRightTwoThirds fills the right two thirds of the monitor work area
+     */
+    PlacementRightTwoThirds = "right-two-thirds",
+
+    /**
+     * PlacementNextMonitor moves the window to the next monitor
+     */
+    PlacementNextMonitor = "next-monitor",
+
+    /**
+     * PlacementPreviousMonitor moves the window to the previous monitor
+     */
+    PlacementPreviousMonitor = "previous-monitor",
+};
+
+/**
+ * PriorityClass is the scheduling priority class of a process
+ */
+export enum PriorityClass {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    PriorityIdle = "idle",
+
+    PriorityBelowNormal = "below-normal",
+
+    PriorityNormal = "normal",
+
+    PriorityAboveNormal = "above-normal",
+
+    PriorityHigh = "high",
+
+    PriorityRealtime = "realtime",
+};
+
+/**
+ * ProcessAction names an action that changes a running process
+ */
+export enum ProcessAction {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    ProcessActionEnd = "end",
+
+    ProcessActionSuspend = "suspend",
+
+    ProcessActionResume = "resume",
+
+    ProcessActionPriority = "priority",
+
+    ProcessActionAffinity = "affinity",
+};
+
+/**
+ * ProcessActionResult describes a process action. With DryRun set nothing
+ * was changed, and the result tells the user what the action would do.
+ * Before and After hold the priority class or affinity mask for those actions.
+ */
+export class ProcessActionResult {
+    "action": ProcessAction;
+    "pid": number;
+    "imageName": string;
+    "dryRun": boolean;
+    "summary": string;
+    "windows"?: WindowEntry[];
+    "before"?: string;
+    "after"?: string;
+    /**
+     * Forced is set when a process did not exit after its windows were closed and was terminated
+     */
+    "forced"?: boolean;
+
+    /** Creates a new ProcessActionResult instance. */
+    constructor($$source: Partial<ProcessActionResult> = {}) {
+        if (!("action" in $$source)) {
+            this["action"] = ProcessAction.$zero;
+        }
+        if (!("pid" in $$source)) {
+            this["pid"] = 0;
+        }
+        if (!("imageName" in $$source)) {
+            this["imageName"] = "";
+        }
+        if (!("dryRun" in $$source)) {
+            this["dryRun"] = false;
+        }
+        if (!("summary" in $$source)) {
+            this["summary"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ProcessActionResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ProcessActionResult {
+        const $$createField5_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("windows" in $$parsedSource) {
+            $$parsedSource["windows"] = $$createField5_0($$parsedSource["windows"]);
+        }
+        return new ProcessActionResult($$parsedSource as Partial<ProcessActionResult>);
+    }
+}
+
+/**
+ * ProcessDetails contains metadata that is not available from tasklist
+ */
+export class ProcessDetails {
+    "exePath": string;
+    "commandLine": string;
+    "parentPid": number;
+    "startTime": time$0.Time;
+    "user": string;
+    "architecture": string;
+    "elevated": boolean;
+    "appId"?: string;
+
+    /** Creates a new ProcessDetails instance. */
+    constructor($$source: Partial<ProcessDetails> = {}) {
+        if (!("exePath" in $$source)) {
+            this["exePath"] = "";
+        }
+        if (!("commandLine" in $$source)) {
+            this["commandLine"] = "";
+        }
+        if (!("parentPid" in $$source)) {
+            this["parentPid"] = 0;
+        }
+        if (!("startTime" in $$source)) {
+            this["startTime"] = null;
+        }
+        if (!("user" in $$source)) {
+            this["user"] = "";
+        }
+        if (!("architecture" in $$source)) {
+            this["architecture"] = "";
+        }
+        if (!("elevated" in $$source)) {
+            this["elevated"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ProcessDetails instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ProcessDetails {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ProcessDetails($$parsedSource as Partial<ProcessDetails>);
+    }
+}
+
 /**
  * ProcessInfo represents information about a running process
  */
@@ -16,8 +443,15 @@ export class ProcessInfo {
     "memUsageB": number;
     "memUsageStr": string;
     "windowTitle": string;
+    "windowClass": string;
     "hasWindow": boolean;
     "windowCount": number;
+    "appId"?: string;
+    /**
+     * Windows lists every top-level window of the entry, main window first
+     */
+    "windows"?: WindowEntry[];
+    "details"?: ProcessDetails | null;
 
     /** Creates a new ProcessInfo instance. */
     constructor($$source: Partial<ProcessInfo> = {}) {
@@ -42,6 +476,9 @@ export class ProcessInfo {
         if (!("windowTitle" in $$source)) {
             this["windowTitle"] = "";
         }
+        if (!("windowClass" in $$source)) {
+            this["windowClass"] = "";
+        }
         if (!("hasWindow" in $$source)) {
             this["hasWindow"] = false;
         }
@@ -56,11 +493,289 @@ export class ProcessInfo {
      * Creates a new ProcessInfo instance from a string or object.
      */
     static createFrom($$source: any = {}): ProcessInfo {
+        const $$createField11_0 = $$createType3;
+        const $$createField12_0 = $$createType5;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("windows" in $$parsedSource) {
+            $$parsedSource["windows"] = $$createField11_0($$parsedSource["windows"]);
+        }
+        if ("details" in $$parsedSource) {
+            $$parsedSource["details"] = $$createField12_0($$parsedSource["details"]);
+        }
         return new ProcessInfo($$parsedSource as Partial<ProcessInfo>);
     }
 }
 
+/**
+ * ProcessMetrics is one resource sample of a process.
+ * GDIObjects and UserObjects are only reported on Windows.
+ */
+export class ProcessMetrics {
+    "pid": number;
+    "imageName": string;
+    "sampledAt": time$0.Time;
+    "cpuPercent": number;
+    "workingSet": number;
+    "privateBytes": number;
+    "handles": number;
+    "gdiObjects": number;
+    "userObjects": number;
+
+    /** Creates a new ProcessMetrics instance. */
+    constructor($$source: Partial<ProcessMetrics> = {}) {
+        if (!("pid" in $$source)) {
+            this["pid"] = 0;
+        }
+        if (!("imageName" in $$source)) {
+            this["imageName"] = "";
+        }
+        if (!("sampledAt" in $$source)) {
+            this["sampledAt"] = null;
+        }
+        if (!("cpuPercent" in $$source)) {
+            this["cpuPercent"] = 0;
+        }
+        if (!("workingSet" in $$source)) {
+            this["workingSet"] = 0;
+        }
+        if (!("privateBytes" in $$source)) {
+            this["privateBytes"] = 0;
+        }
+        if (!("handles" in $$source)) {
+            this["handles"] = 0;
+        }
+        if (!("gdiObjects" in $$source)) {
+            this["gdiObjects"] = 0;
+        }
+        if (!("userObjects" in $$source)) {
+            this["userObjects"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ProcessMetrics instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ProcessMetrics {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ProcessMetrics($$parsedSource as Partial<ProcessMetrics>);
+    }
+}
+
+/**
+ * Rect represents a rectangle in screen coordinates
+ */
+export class Rect {
+    "x": number;
+    "y": number;
+    "width": number;
+    "height": number;
+
+    /** Creates a new Rect instance. */
+    constructor($$source: Partial<Rect> = {}) {
+        if (!("x" in $$source)) {
+            this["x"] = 0;
+        }
+        if (!("y" in $$source)) {
+            this["y"] = 0;
+        }
+        if (!("width" in $$source)) {
+            this["width"] = 0;
+        }
+        if (!("height" in $$source)) {
+            this["height"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Rect instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Rect {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Rect($$parsedSource as Partial<Rect>);
+    }
+}
+
+/**
+ * TriggerCondition is the change to a matching window that fires a trigger
+ */
+export enum TriggerCondition {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    /**
+     * TriggerAppears fires when a window starts matching: it is shown, or its
+     * title or class changes so that it matches
+     */
+    TriggerAppears = "appears",
+
+    /**
+     * TriggerChanges fires when the title of a matching window changes and it
+     * still matches
+     */
+    TriggerChanges = "changes",
+
+    /**
+     * TriggerDisappears fires when a matching window is hidden, destroyed or
+     * stops matching
+     */
+    TriggerDisappears = "disappears",
+};
+
+/**
+ * TriggerNotification is posted by a "notify" action
+ */
+export class TriggerNotification {
+    "trigger": string;
+    "condition": TriggerCondition;
+    "message": string;
+    "title": string;
+    "handle": number;
+
+    /** Creates a new TriggerNotification instance. */
+    constructor($$source: Partial<TriggerNotification> = {}) {
+        if (!("trigger" in $$source)) {
+            this["trigger"] = "";
+        }
+        if (!("condition" in $$source)) {
+            this["condition"] = TriggerCondition.$zero;
+        }
+        if (!("message" in $$source)) {
+            this["message"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("handle" in $$source)) {
+            this["handle"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TriggerNotification instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TriggerNotification {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TriggerNotification($$parsedSource as Partial<TriggerNotification>);
+    }
+}
+
+/**
+ * VirtualDesktop represents a Windows virtual desktop
+ */
+export class VirtualDesktop {
+    "id": string;
+    "index": number;
+    "name": string;
+    "current": boolean;
+
+    /** Creates a new VirtualDesktop instance. */
+    constructor($$source: Partial<VirtualDesktop> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("index" in $$source)) {
+            this["index"] = 0;
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("current" in $$source)) {
+            this["current"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new VirtualDesktop instance from a string or object.
+     */
+    static createFrom($$source: any = {}): VirtualDesktop {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new VirtualDesktop($$parsedSource as Partial<VirtualDesktop>);
+    }
+}
+
+/**
+ * WindowAdjustment is a move or resize relative to the current window rect.
+ * The result is clamped to the work area of the window's monitor.
+ */
+export class WindowAdjustment {
+    "kind": AdjustmentKind;
+    "dx"?: number;
+    "dy"?: number;
+    "dWidth"?: number;
+    "dHeight"?: number;
+    "percent"?: number;
+    "width"?: number;
+    "height"?: number;
+    /**
+     * Anchor defaults to the top-left corner
+     */
+    "anchor"?: Anchor;
+
+    /** Creates a new WindowAdjustment instance. */
+    constructor($$source: Partial<WindowAdjustment> = {}) {
+        if (!("kind" in $$source)) {
+            this["kind"] = AdjustmentKind.$zero;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WindowAdjustment instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WindowAdjustment {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new WindowAdjustment($$parsedSource as Partial<WindowAdjustment>);
+    }
+}
+
+/**
+ * WindowEntry describes a top-level window of a process
+ */
+export class WindowEntry {
+    "handle": number;
+    "pid": number;
+    "title": string;
+    "class": string;
+    "appId"?: string;
+
+    /** Creates a new WindowEntry instance. */
+    constructor($$source: Partial<WindowEntry> = {}) {
+        if (!("handle" in $$source)) {
+            this["handle"] = 0;
+        }
+        if (!("pid" in $$source)) {
+            this["pid"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("class" in $$source)) {
+            this["class"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WindowEntry instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WindowEntry {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new WindowEntry($$parsedSource as Partial<WindowEntry>);
+    }
+}
+
 /**
  * WindowInfo represents window position and size information
  */
@@ -69,6 +784,7 @@ export class WindowInfo {
     "y": number;
     "width": number;
     "height": number;
+    "desktopId"?: string;
 
     /** Creates a new WindowInfo instance. */
     constructor($$source: Partial<WindowInfo> = {}) {
@@ -96,3 +812,217 @@ export class WindowInfo {
         return new WindowInfo($$parsedSource as Partial<WindowInfo>);
     }
 }
+
+/**
+ * WindowMatch finds the running process of a workspace app.
+ * Empty criteria are ignored; all set criteria must match.
+ */
+export class WindowMatch {
+    "imageGlob"?: string;
+    "windowClass"?: string;
+    "titleRegex"?: string;
+
+    /** Creates a new WindowMatch instance. */
+    constructor($$source: Partial<WindowMatch> = {}) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WindowMatch instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WindowMatch {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new WindowMatch($$parsedSource as Partial<WindowMatch>);
+    }
+}
+
+/**
+ * Workspace is a named set of applications that are launched if missing and
+ * arranged together
+ */
+export class Workspace {
+    "name": string;
+    "apps": WorkspaceApp[];
+
+    /** Creates a new Workspace instance. */
+    constructor($$source: Partial<Workspace> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("apps" in $$source)) {
+            this["apps"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Workspace instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Workspace {
+        const $$createField1_0 = $$createType7;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("apps" in $$parsedSource) {
+            $$parsedSource["apps"] = $$createField1_0($$parsedSource["apps"]);
+        }
+        return new Workspace($$parsedSource as Partial<Workspace>);
+    }
+}
+
+/**
+ * WorkspaceApp is one application of a workspace. Apps are handled in order;
+ * DelayMS waits after the app before the next one is handled.
+ */
+export class WorkspaceApp {
+    "name": string;
+    "match": WindowMatch;
+    /**
+     * Launch starts the app when no running process matches
+     */
+    "launch"?: LaunchTarget | null;
+    "placement"?: Placement;
+    "rect"?: Rect | null;
+    "monitor"?: number | null;
+    "delayMs"?: number;
+    "timeoutMs"?: number;
+
+    /** Creates a new WorkspaceApp instance. */
+    constructor($$source: Partial<WorkspaceApp> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("match" in $$source)) {
+            this["match"] = (new WindowMatch());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WorkspaceApp instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WorkspaceApp {
+        const $$createField1_0 = $$createType8;
+        const $$createField2_0 = $$createType10;
+        const $$createField4_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("match" in $$parsedSource) {
+            $$parsedSource["match"] = $$createField1_0($$parsedSource["match"]);
+        }
+        if ("launch" in $$parsedSource) {
+            $$parsedSource["launch"] = $$createField2_0($$parsedSource["launch"]);
+        }
+        if ("rect" in $$parsedSource) {
+            $$parsedSource["rect"] = $$createField4_0($$parsedSource["rect"]);
+        }
+        return new WorkspaceApp($$parsedSource as Partial<WorkspaceApp>);
+    }
+}
+
+/**
+ * WorkspaceAppState is the outcome for one app of a workspace run
+ */
+export enum WorkspaceAppState {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    /**
+     * WorkspaceAppReused means a running process was arranged
+     */
+    WorkspaceAppReused = "reused",
+
+    /**
+     * WorkspaceAppLaunched means the app was started and arranged
+     */
+    WorkspaceAppLaunched = "launched",
+
+    /**
+     * WorkspaceAppSkipped means the app was not running and has no launch target
+     */
+    WorkspaceAppSkipped = "skipped",
+
+    /**
+     * WorkspaceAppFailed means matching, launching or arranging failed
+     */
+    WorkspaceAppFailed = "failed",
+};
+
+/**
+ * WorkspaceAppStatus reports what a workspace run did with one app
+ */
+export class WorkspaceAppStatus {
+    "name": string;
+    "state": WorkspaceAppState;
+    "pid"?: number;
+    "error"?: string;
+
+    /** Creates a new WorkspaceAppStatus instance. */
+    constructor($$source: Partial<WorkspaceAppStatus> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("state" in $$source)) {
+            this["state"] = WorkspaceAppState.$zero;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WorkspaceAppStatus instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WorkspaceAppStatus {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new WorkspaceAppStatus($$parsedSource as Partial<WorkspaceAppStatus>);
+    }
+}
+
+/**
+ * WorkspaceResult reports the outcome of a workspace run, one status per app
+ */
+export class WorkspaceResult {
+    "workspace": string;
+    "apps": WorkspaceAppStatus[];
+
+    /** Creates a new WorkspaceResult instance. */
+    constructor($$source: Partial<WorkspaceResult> = {}) {
+        if (!("workspace" in $$source)) {
+            this["workspace"] = "";
+        }
+        if (!("apps" in $$source)) {
+            this["apps"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WorkspaceResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WorkspaceResult {
+        const $$createField1_0 = $$createType12;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("apps" in $$parsedSource) {
+            $$parsedSource["apps"] = $$createField1_0($$parsedSource["apps"]);
+        }
+        return new WorkspaceResult($$parsedSource as Partial<WorkspaceResult>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = Rect.createFrom;
+const $$createType1 = $Create.Nullable($$createType0);
+const $$createType2 = WindowEntry.createFrom;
+const $$createType3 = $Create.Array($$createType2);
+const $$createType4 = ProcessDetails.createFrom;
+const $$createType5 = $Create.Nullable($$createType4);
+const $$createType6 = WorkspaceApp.createFrom;
+const $$createType7 = $Create.Array($$createType6);
+const $$createType8 = WindowMatch.createFrom;
+const $$createType9 = LaunchTarget.createFrom;
+const $$createType10 = $Create.Nullable($$createType9);
+const $$createType11 = WorkspaceAppStatus.createFrom;
+const $$createType12 = $Create.Array($$createType11);
@@ -14,12 +14,56 @@ import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Cr
 // @ts-ignore: Unused imports
 import * as models$0 from "../models/models.js";
 
+/**
+ * AdjustWindow moves or resizes a window relative to its current rect
+ */
+export function AdjustWindow(pid: number, adjust: models$0.WindowAdjustment): $CancellablePromise<void> {
+    return $Call.ByID(3441192305, pid, adjust);
+}
+
+/**
+ * AdjustWindowByHandle moves or resizes a specific window relative to its current rect
+ */
+export function AdjustWindowByHandle(handle: number, adjust: models$0.WindowAdjustment): $CancellablePromise<void> {
+    return $Call.ByID(1664438326, handle, adjust);
+}
+
+/**
+ * ApplyPlacement moves a window to a predefined placement on its monitor
+ */
+export function ApplyPlacement(pid: number, placement: models$0.Placement): $CancellablePromise<void> {
+    return $Call.ByID(2298193357, pid, placement);
+}
+
+/**
+ * ApplyPlacementByHandle moves a specific window to a predefined placement on its monitor
+ */
+export function ApplyPlacementByHandle(handle: number, placement: models$0.Placement): $CancellablePromise<void> {
+    return $Call.ByID(4061271362, handle, placement);
+}
+
+/**
+ * CyclePlacement applies the next placement of a cycle on repeated calls
+ */
+export function CyclePlacement(pid: number, cycle: models$0.Placement[]): $CancellablePromise<void> {
+    return $Call.ByID(1606501579, pid, cycle);
+}
+
+/**
+ * EndProcess closes the windows of a process and terminates it if it does not exit
+ */
+export function EndProcess(pid: number, dryRun: boolean): $CancellablePromise<models$0.ProcessActionResult | null> {
+    return $Call.ByID(2659666540, pid, dryRun).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
 /**
  * GetAllProcessesWithWindows returns all processes that have visible windows (for debugging)
  */
 export function GetAllProcessesWithWindows(): $CancellablePromise<models$0.ProcessInfo[]> {
     return $Call.ByID(2996448723).then(($result: any) => {
-        return $$createType1($result);
+        return $$createType3($result);
     });
 }
 
@@ -28,7 +72,86 @@ export function GetAllProcessesWithWindows(): $CancellablePromise<models$0.Proce
  */
 export function GetApplicationProcesses(): $CancellablePromise<models$0.ProcessInfo[]> {
     return $Call.ByID(300866837).then(($result: any) => {
-        return $$createType1($result);
+        return $$createType3($result);
+    });
+}
+
+/**
+ * GetDisplayFingerprint returns the fingerprint of the current monitor setup,
+ * as used to associate layouts with monitor setups
+ */
+export function GetDisplayFingerprint(): $CancellablePromise<string> {
+    return $Call.ByID(2270129544);
+}
+
+/**
+ * GetMonitors returns all connected monitors
+ */
+export function GetMonitors(): $CancellablePromise<models$0.MonitorInfo[]> {
+    return $Call.ByID(2305097385).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * GetProcessDetails returns executable path, command line, parent, user and architecture for a process
+ */
+export function GetProcessDetails(pid: number): $CancellablePromise<models$0.ProcessDetails | null> {
+    return $Call.ByID(3801978051, pid).then(($result: any) => {
+        return $$createType7($result);
+    });
+}
+
+/**
+ * GetProcessIcon returns the PNG-encoded icon of a process's executable
+ */
+export function GetProcessIcon(pid: number): $CancellablePromise<string> {
+    return $Call.ByID(4136479986, pid);
+}
+
+/**
+ * GetProcessMetricsHistory returns the recent resource samples of a process, oldest first
+ */
+export function GetProcessMetricsHistory(pid: number): $CancellablePromise<models$0.ProcessMetrics[]> {
+    return $Call.ByID(2513878500, pid).then(($result: any) => {
+        return $$createType9($result);
+    });
+}
+
+/**
+ * GetProcessesForProfile returns processes with visible windows matching the named filter profile
+ */
+export function GetProcessesForProfile(profile: string): $CancellablePromise<models$0.ProcessInfo[]> {
+    return $Call.ByID(1674083243, profile).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+/**
+ * GetTopProcesses returns the n processes using the most of a resource
+ */
+export function GetTopProcesses(key: models$0.MetricKey, n: number): $CancellablePromise<models$0.ProcessMetrics[]> {
+    return $Call.ByID(588711790, key, n).then(($result: any) => {
+        return $$createType9($result);
+    });
+}
+
+/**
+ * GetTriggerNotifications returns the recent notifications of window trigger
+ * "notify" actions, oldest first; new ones arrive as window-trigger events
+ */
+export function GetTriggerNotifications(): $CancellablePromise<models$0.TriggerNotification[]> {
+    return $Call.ByID(3421009722).then(($result: any) => {
+        return $$createType11($result);
+    });
+}
+
+/**
+ * GetVirtualDesktops returns the virtual desktops in task view order
+ */
+export function GetVirtualDesktops(): $CancellablePromise<models$0.VirtualDesktop[]> {
+    return $Call.ByID(3162580360).then(($result: any) => {
+        return $$createType13($result);
     });
 }
 
@@ -37,7 +160,119 @@ export function GetApplicationProcesses(): $CancellablePromise<models$0.ProcessI
  */
 export function GetWindowInfo(pid: number): $CancellablePromise<models$0.WindowInfo | null> {
     return $Call.ByID(1957271386, pid).then(($result: any) => {
-        return $$createType3($result);
+        return $$createType15($result);
+    });
+}
+
+/**
+ * GetWindowInfoByHandle gets the current size and position of a specific window
+ */
+export function GetWindowInfoByHandle(handle: number): $CancellablePromise<models$0.WindowInfo | null> {
+    return $Call.ByID(175525637, handle).then(($result: any) => {
+        return $$createType15($result);
+    });
+}
+
+/**
+ * GetWindowThumbnail returns a PNG preview of a window scaled to fit maxWidth x maxHeight
+ */
+export function GetWindowThumbnail(handle: number, maxWidth: number, maxHeight: number): $CancellablePromise<string> {
+    return $Call.ByID(880219882, handle, maxWidth, maxHeight);
+}
+
+/**
+ * HighlightWindow briefly draws a border around the window actions by PID target
+ */
+export function HighlightWindow(pid: number): $CancellablePromise<void> {
+    return $Call.ByID(829641650, pid);
+}
+
+/**
+ * HighlightWindowByHandle briefly draws a border around a specific window
+ */
+export function HighlightWindowByHandle(handle: number): $CancellablePromise<void> {
+    return $Call.ByID(1162782557, handle);
+}
+
+/**
+ * LaunchApplication starts an application and places its main window once it appears
+ */
+export function LaunchApplication(request: models$0.LaunchRequest): $CancellablePromise<models$0.LaunchResult | null> {
+    return $Call.ByID(1337618757, request).then(($result: any) => {
+        return $$createType17($result);
+    });
+}
+
+/**
+ * ListFilterProfiles returns the names of all process filter profiles
+ */
+export function ListFilterProfiles(): $CancellablePromise<string[]> {
+    return $Call.ByID(2376192346).then(($result: any) => {
+        return $$createType18($result);
+    });
+}
+
+/**
+ * ListWorkspaces returns all configured workspaces
+ */
+export function ListWorkspaces(): $CancellablePromise<models$0.Workspace[]> {
+    return $Call.ByID(921506532).then(($result: any) => {
+        return $$createType20($result);
+    });
+}
+
+/**
+ * PickWindow waits for the user to click a window and returns it
+ */
+export function PickWindow(): $CancellablePromise<models$0.WindowEntry | null> {
+    return $Call.ByID(183832251).then(($result: any) => {
+        return $$createType22($result);
+    });
+}
+
+/**
+ * RescueOffscreenWindows moves every unreachable window onto the primary monitor
+ */
+export function RescueOffscreenWindows(): $CancellablePromise<models$0.WindowEntry[]> {
+    return $Call.ByID(2422563637).then(($result: any) => {
+        return $$createType23($result);
+    });
+}
+
+/**
+ * ResumeProcess resumes a suspended process
+ */
+export function ResumeProcess(pid: number, dryRun: boolean): $CancellablePromise<models$0.ProcessActionResult | null> {
+    return $Call.ByID(1090515346, pid, dryRun).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * RunWorkspace launches the missing apps of a workspace, arranges all of them
+ * and reports the outcome per app
+ */
+export function RunWorkspace(name: string): $CancellablePromise<models$0.WorkspaceResult | null> {
+    return $Call.ByID(3655509752, name).then(($result: any) => {
+        return $$createType25($result);
+    });
+}
+
+/**
+ * SetProcessAffinity restricts a process to the CPUs set in mask
+ */
+export function SetProcessAffinity(pid: number, mask: number, dryRun: boolean): $CancellablePromise<models$0.ProcessActionResult | null> {
+    return $Call.ByID(3022196063, pid, mask, dryRun).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * SetProcessPriority sets the priority class of a process
+ */
+export function SetProcessPriority(pid: number, priority: models$0.PriorityClass, dryRun: boolean): $CancellablePromise<models$0.ProcessActionResult | null> {
+    return $Call.ByID(1652349719, pid, priority, dryRun).then(($result: any) => {
+        return $$createType1($result);
     });
 }
 
@@ -48,6 +283,13 @@ export function SetWindowPosition(pid: number, x: number, y: number, width: numb
     return $Call.ByID(2290525609, pid, x, y, width, height);
 }
 
+/**
+ * SetWindowPositionByHandle sets position and size of a specific window
+ */
+export function SetWindowPositionByHandle(handle: number, x: number, y: number, width: number, height: number): $CancellablePromise<void> {
+    return $Call.ByID(1477993518, handle, x, y, width, height);
+}
+
 /**
  * SetWindowSize sets the size of a window by process PID, keeping current position
  */
@@ -55,8 +297,53 @@ export function SetWindowSize(pid: number, width: number, height: number): $Canc
     return $Call.ByID(1467875311, pid, width, height);
 }
 
+/**
+ * SetWindowSizeByHandle sets the size of a specific window, keeping current position
+ */
+export function SetWindowSizeByHandle(handle: number, width: number, height: number): $CancellablePromise<void> {
+    return $Call.ByID(1108679316, handle, width, height);
+}
+
+/**
+ * SuspendProcess suspends every thread of a process
+ */
+export function SuspendProcess(pid: number, dryRun: boolean): $CancellablePromise<models$0.ProcessActionResult | null> {
+    return $Call.ByID(2204602995, pid, dryRun).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * SwapWindows exchanges the rects of the main windows of two processes
+ */
+export function SwapWindows(pidA: number, pidB: number): $CancellablePromise<void> {
+    return $Call.ByID(3194999882, pidA, pidB);
+}
+
 // Private type creation functions
-const $$createType0 = models$0.ProcessInfo.createFrom;
-const $$createType1 = $Create.Array($$createType0);
-const $$createType2 = models$0.WindowInfo.createFrom;
-const $$createType3 = $Create.Nullable($$createType2);
+const $$createType0 = models$0.ProcessActionResult.createFrom;
+const $$createType1 = $Create.Nullable($$createType0);
+const $$createType2 = models$0.ProcessInfo.createFrom;
+const $$createType3 = $Create.Array($$createType2);
+const $$createType4 = models$0.MonitorInfo.createFrom;
+const $$createType5 = $Create.Array($$createType4);
+const $$createType6 = models$0.ProcessDetails.createFrom;
+const $$createType7 = $Create.Nullable($$createType6);
+const $$createType8 = models$0.ProcessMetrics.createFrom;
+const $$createType9 = $Create.Array($$createType8);
+const $$createType10 = models$0.TriggerNotification.createFrom;
+const $$createType11 = $Create.Array($$createType10);
+const $$createType12 = models$0.VirtualDesktop.createFrom;
+const $$createType13 = $Create.Array($$createType12);
+const $$createType14 = models$0.WindowInfo.createFrom;
+const $$createType15 = $Create.Nullable($$createType14);
+const $$createType16 = models$0.LaunchResult.createFrom;
+const $$createType17 = $Create.Nullable($$createType16);
+const $$createType18 = $Create.Array($Create.Any);
+const $$createType19 = models$0.Workspace.createFrom;
+const $$createType20 = $Create.Array($$createType19);
+const $$createType21 = models$0.WindowEntry.createFrom;
+const $$createType22 = $Create.Nullable($$createType21);
+const $$createType23 = $Create.Array($$createType21);
+const $$createType24 = models$0.WorkspaceResult.createFrom;
+const $$createType25 = $Create.Nullable($$createType24);
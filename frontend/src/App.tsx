@@ -1,8 +1,9 @@
-import { useStatus, useProcesses, useWindowControl } from './hooks';
+import { useStatus, useProcesses, useTriggerNotifications, useWindowControl } from './hooks';
 import { ProcessSelector, WindowControls, StatusDisplay } from './components';
 
 function App() {
   const { status, setStatus } = useStatus();
+  useTriggerNotifications(setStatus);
   const {
     processes,
     selectedProcess,
@@ -4,8 +4,6 @@ interface NumberInputProps {
   label: string;
   value: number;
   onChange: (value: number) => void;
-  min: number;
-  max: number;
   defaultValue: number;
 }
 
@@ -13,12 +11,13 @@ export const NumberInput: React.FC<NumberInputProps> = ({
   label,
   value,
   onChange,
-  min,
-  max,
   defaultValue,
 }) => {
   const handleChange = (e: React.ChangeEvent<HTMLInputElement>) => {
-    onChange(parseInt(e.target.value) || defaultValue);
+    // Negative and zero positions are valid on multi-monitor setups; the
+    // backend rejects rects that end up unreachable
+    const parsed = parseInt(e.target.value, 10);
+    onChange(Number.isNaN(parsed) ? defaultValue : parsed);
   };
 
   return (
@@ -30,8 +29,6 @@ export const NumberInput: React.FC<NumberInputProps> = ({
         type="number"
         value={value}
         onChange={handleChange}
-        min={min}
-        max={max}
         className="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500"
       />
     </div>
@@ -1,5 +1,9 @@
-import React from 'react';
+import React, { useEffect, useState } from 'react';
 import { ProcessInfo } from '../../bindings/hptools/internal/models';
+import { WailsWindowService } from '../../bindings/hptools/internal/services';
+
+const PREVIEW_WIDTH = 320;
+const PREVIEW_HEIGHT = 180;
 
 interface ProcessSelectorProps {
   processes: ProcessInfo[];
@@ -20,10 +24,34 @@ export const ProcessSelector: React.FC<ProcessSelectorProps> = ({
   onRefresh,
   onDebugModeChange,
 }) => {
+  const [preview, setPreview] = useState<string | null>(null);
+  const previewHandle = selectedProcess?.windows?.[0]?.handle;
+
+  useEffect(() => {
+    setPreview(null);
+    if (!previewHandle) {
+      return;
+    }
+
+    // The binding returns the PNG bytes base64 encoded
+    const request = WailsWindowService.GetWindowThumbnail(previewHandle, PREVIEW_WIDTH, PREVIEW_HEIGHT);
+    request
+      .then((png) => setPreview(`data:image/png;base64,${png}`))
+      .catch((error) => console.error('Error capturing window preview:', error));
+    return () => request.cancel();
+  }, [previewHandle]);
+
   const handleProcessChange = (e: React.ChangeEvent<HTMLSelectElement>) => {
     const pid = parseInt(e.target.value);
     const process = processes.find(p => p.pid === pid);
     onProcessSelect(process || null);
+
+    // Show which window the controls will act on
+    const handle = process?.windows?.[0]?.handle;
+    if (handle) {
+      WailsWindowService.HighlightWindowByHandle(handle)
+        .catch((error) => console.error('Error highlighting window:', error));
+    }
   };
 
   return (
@@ -53,6 +81,17 @@ export const ProcessSelector: React.FC<ProcessSelectorProps> = ({
         </button>
       </div>
 
+      {preview && (
+        <div className="mb-4">
+          <img
+            src={preview}
+            alt={`Preview of ${selectedProcess?.windowTitle}`}
+            className="max-w-full border border-gray-200 rounded-md"
+            style={{ maxWidth: PREVIEW_WIDTH, maxHeight: PREVIEW_HEIGHT }}
+          />
+        </div>
+      )}
+
       <div className="flex items-center gap-4 mb-4">
         <label className="flex items-center gap-2">
           <input
@@ -1,7 +1,7 @@
 import React from 'react';
 import { ProcessInfo, WindowInfo } from '../../bindings/hptools/internal/models';
 import { WindowDimensions } from '../types/window';
-import { INPUT_LIMITS, SIZE_PRESETS, DEFAULT_DIMENSIONS } from '../constants/window';
+import { SIZE_PRESETS, DEFAULT_DIMENSIONS } from '../constants/window';
 import { NumberInput } from './NumberInput';
 import { SizePresets } from './SizePresets';
 
@@ -60,8 +60,6 @@ export const WindowControls: React.FC<WindowControlsProps> = ({
           label="Width"
           value={dimensions.width}
           onChange={handleDimensionChange('width')}
-          min={INPUT_LIMITS.width.min}
-          max={INPUT_LIMITS.width.max}
           defaultValue={DEFAULT_DIMENSIONS.width}
         />
         
@@ -69,8 +67,6 @@ export const WindowControls: React.FC<WindowControlsProps> = ({
           label="Height"
           value={dimensions.height}
           onChange={handleDimensionChange('height')}
-          min={INPUT_LIMITS.height.min}
-          max={INPUT_LIMITS.height.max}
           defaultValue={DEFAULT_DIMENSIONS.height}
         />
         
@@ -78,8 +74,6 @@ export const WindowControls: React.FC<WindowControlsProps> = ({
           label="X Position"
           value={dimensions.x}
           onChange={handleDimensionChange('x')}
-          min={INPUT_LIMITS.position.min}
-          max={INPUT_LIMITS.position.max}
           defaultValue={DEFAULT_DIMENSIONS.x}
         />
         
@@ -87,8 +81,6 @@ export const WindowControls: React.FC<WindowControlsProps> = ({
           label="Y Position"
           value={dimensions.y}
           onChange={handleDimensionChange('y')}
-          min={INPUT_LIMITS.position.min}
-          max={INPUT_LIMITS.position.max}
           defaultValue={DEFAULT_DIMENSIONS.y}
         />
       </div>
@@ -7,12 +7,15 @@ export const DEFAULT_DIMENSIONS = {
   y: 100,
 } as const;
 
-export const INPUT_LIMITS = {
-  width: { min: 100, max: 3840 },
-  height: { min: 100, max: 2160 },
-  position: { min: 0, max: 5000 },
+// Process filter profiles defined in the backend configuration
+export const FILTER_PROFILES = {
+  DEFAULT: 'applications',
+  DEBUG: 'debug',
 } as const;
 
+// Event emitted by window trigger "notify" actions
+export const TRIGGER_EVENT = 'window-trigger';
+
 export const SIZE_PRESETS: SizePreset[] = [
   { name: "HD", w: 1280, h: 720 },
   { name: "FHD", w: 1920, h: 1080 },
@@ -34,5 +37,7 @@ export const STATUS_MESSAGES = {
     `✅ Set window position to (${x}, ${y}) and size to ${width}x${height} for ${imageName}`,
   WINDOW_INFO: (width: number, height: number, x: number, y: number) => 
     `📏 Current window: ${width}x${height} at position (${x}, ${y})`,
+  TRIGGER_FIRED: (trigger: string, message: string) =>
+    `🔔 ${trigger}: ${message}`,
   ERROR: (error: unknown) => `❌ Error: ${error}`,
 } as const;
\ No newline at end of file
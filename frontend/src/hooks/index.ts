@@ -1,3 +1,4 @@
 export { useStatus } from './useStatus';
 export { useProcesses } from './useProcesses';
-export { useWindowControl } from './useWindowControl';
\ No newline at end of file
+export { useWindowControl } from './useWindowControl';
+export { useTriggerNotifications } from './useTriggerNotifications';
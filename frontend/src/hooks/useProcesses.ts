@@ -2,7 +2,7 @@ import { useState, useEffect } from 'react';
 import {  ProcessInfo } from '../../bindings/hptools/internal/models';
 import { WailsWindowService } from '../../bindings/hptools/internal/services';
 import { UseProcessesReturn } from '../types/window';
-import { STATUS_MESSAGES } from '../constants/window';
+import { FILTER_PROFILES, STATUS_MESSAGES } from '../constants/window';
 
 export const useProcesses = (
   setStatus: (status: string) => void
@@ -15,8 +15,8 @@ export const useProcesses = (
   const fetchProcesses = async () => {
     try {
       setLoading(true);
-      const apps = debugMode 
-        ? await WailsWindowService.GetAllProcessesWithWindows()
+      const apps = debugMode
+        ? await WailsWindowService.GetProcessesForProfile(FILTER_PROFILES.DEBUG)
         : await WailsWindowService.GetApplicationProcesses();
       setProcesses(apps);
       setStatus(STATUS_MESSAGES.PROCESSES_FOUND(apps.length, debugMode));
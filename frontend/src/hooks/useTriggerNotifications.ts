@@ -0,0 +1,24 @@
+import { useEffect } from 'react';
+import { Events } from '@wailsio/runtime';
+import { TriggerNotification } from '../../bindings/hptools/internal/models';
+import { WailsWindowService } from '../../bindings/hptools/internal/services';
+import { STATUS_MESSAGES, TRIGGER_EVENT } from '../constants/window';
+
+// Shows the messages of window trigger "notify" actions in the status line,
+// starting with the last one posted before the window opened
+export const useTriggerNotifications = (setStatus: (status: string) => void) => {
+  useEffect(() => {
+    const show = (n: TriggerNotification) => setStatus(STATUS_MESSAGES.TRIGGER_FIRED(n.trigger, n.message));
+
+    WailsWindowService.GetTriggerNotifications()
+      .then((notifications) => {
+        const last = notifications[notifications.length - 1];
+        if (last) {
+          show(last);
+        }
+      })
+      .catch((error) => console.error('Error fetching trigger notifications:', error));
+
+    return Events.On(TRIGGER_EVENT, (event: { data: TriggerNotification }) => show(event.data));
+  }, [setStatus]);
+};
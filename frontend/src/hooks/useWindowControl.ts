@@ -4,6 +4,11 @@ import { WailsWindowService } from '../../bindings/hptools/internal/services';
 import { UseWindowControlReturn, WindowDimensions } from '../types/window';
 import { DEFAULT_DIMENSIONS, STATUS_MESSAGES } from '../constants/window';
 
+// Entries split by AppUserModelID share a PID with other entries, so actions
+// target the entry's own main window when the backend reported one
+const windowHandle = (process: ProcessInfo): number | undefined =>
+  process.windows?.[0]?.handle;
+
 export const useWindowControl = (
   setStatus: (status: string) => void
 ): UseWindowControlReturn => {
@@ -27,7 +32,12 @@ export const useWindowControl = (
 
     try {
       setLoading(true);
-      await WailsWindowService.SetWindowSize(process.pid, dimensions.width, dimensions.height);
+      const handle = windowHandle(process);
+      if (handle) {
+        await WailsWindowService.SetWindowSizeByHandle(handle, dimensions.width, dimensions.height);
+      } else {
+        await WailsWindowService.SetWindowSize(process.pid, dimensions.width, dimensions.height);
+      }
       setStatus(STATUS_MESSAGES.WINDOW_RESIZED(dimensions.width, dimensions.height, process.imageName));
       // Refresh window info after resize
       await getWindowInfo(process);
@@ -47,7 +57,12 @@ export const useWindowControl = (
 
     try {
       setLoading(true);
-      await WailsWindowService.SetWindowPosition(process.pid, dimensions.x, dimensions.y, dimensions.width, dimensions.height);
+      const handle = windowHandle(process);
+      if (handle) {
+        await WailsWindowService.SetWindowPositionByHandle(handle, dimensions.x, dimensions.y, dimensions.width, dimensions.height);
+      } else {
+        await WailsWindowService.SetWindowPosition(process.pid, dimensions.x, dimensions.y, dimensions.width, dimensions.height);
+      }
       setStatus(STATUS_MESSAGES.WINDOW_MOVED(dimensions.x, dimensions.y, dimensions.width, dimensions.height, process.imageName));
       // Refresh window info after move/resize
       await getWindowInfo(process);
@@ -67,7 +82,10 @@ export const useWindowControl = (
 
     try {
       setLoading(true);
-      const info = await WailsWindowService.GetWindowInfo(process.pid);
+      const handle = windowHandle(process);
+      const info = handle
+        ? await WailsWindowService.GetWindowInfoByHandle(handle)
+        : await WailsWindowService.GetWindowInfo(process.pid);
       if (info) {
         setCurrentWindowInfo(info);
         setStatus(STATUS_MESSAGES.WINDOW_INFO(info.width, info.height, info.x, info.y));
@@ -40,4 +40,4 @@ export interface UseStatusReturn {
   status: string;
   setStatus: (status: string) => void;
   clearStatus: () => void;
-}
\ No newline at end of file
+}
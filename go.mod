@@ -4,7 +4,12 @@ go 1.24.0
 
 toolchain go1.24.3
 
-require github.com/wailsapp/wails/v3 v3.0.0-dev
+require (
+	github.com/go-toast/toast v0.0.0-20190211030409-01e6764cf0a4
+	github.com/jezek/xgb v1.1.1
+	github.com/wailsapp/wails/v3 v3.0.0-dev
+	go.starlark.net v0.0.0-20231121155337-90ade8b19d09
+)
 
 require (
 	dario.cat/mergo v1.0.1 // indirect
@@ -31,6 +36,7 @@ require (
 	github.com/lmittmann/tint v1.0.7 // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/nu7hatch/gouuid v0.0.0-20131221200532-179d4d0c4d8d // indirect
 	github.com/pjbgf/sha1cd v0.3.2 // indirect
 	github.com/pkg/browser v0.0.0-20240102092130-5ac0b6a4141c // indirect
 	github.com/pkg/errors v0.9.1 // indirect
@@ -7,14 +7,42 @@ import (
 	"path/filepath"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
+
+	"hptools/internal/filter"
+	"hptools/internal/models"
 )
 
+// scriptsDirName is the directory next to the config that holds user scripts
+const scriptsDirName = "scripts"
+
 // Config holds the application configuration
 type Config struct {
-	App     AppConfig     `json:"app"`
-	Window  WindowConfig  `json:"window"`
-	Log     LogConfig     `json:"log"`
-	Systray SystrayConfig `json:"systray"`
+	App       AppConfig       `json:"app"`
+	Window    WindowConfig    `json:"window"`
+	Log       LogConfig       `json:"log"`
+	Systray   SystrayConfig   `json:"systray"`
+	Layouts   []models.Layout `json:"layouts"`
+	Filters   FilterConfig    `json:"filters"`
+	Placement PlacementConfig `json:"placement"`
+	// DisplayLayouts maps monitor setup fingerprints to the layout applied
+	// when that setup becomes active
+	DisplayLayouts []models.DisplayLayout `json:"displayLayouts"`
+	Metrics        MetricsConfig          `json:"metrics"`
+	Workspaces     []models.Workspace     `json:"workspaces"`
+	Triggers       []models.Trigger       `json:"triggers"`
+	// FocusFollowsMouse focuses the window under the cursor when enabled
+	FocusFollowsMouse models.FocusFollowOptions `json:"focusFollowsMouse"`
+	// WindowDrag moves and resizes windows with Alt+drag when enabled
+	WindowDrag models.DragOptions `json:"windowDrag"`
+	// Tiling tiles the windows of one monitor automatically when enabled
+	Tiling models.TilingOptions `json:"tiling"`
+	// Scripts runs the Starlark scripts of the scripts directory when enabled
+	Scripts ScriptsConfig `json:"scripts"`
+	// Linux holds the settings of the Linux command line tool
+	Linux LinuxConfig `json:"linux"`
+	// PauseRules suspends triggers, focus follows mouse, window dragging,
+	// tiling and script callbacks without changing their settings
+	PauseRules bool `json:"pauseRules"`
 }
 
 // AppConfig holds general application settings
@@ -56,6 +84,40 @@ type SystrayConfig struct {
 	DebounceMS   int    `json:"debounceMs"`
 }
 
+// FilterConfig holds process filter profiles.
+// Profiles named like a built-in profile replace it.
+type FilterConfig struct {
+	DefaultProfile string                 `json:"defaultProfile"`
+	Profiles       []models.FilterProfile `json:"profiles"`
+}
+
+// PlacementConfig holds window placement settings.
+// BoundsPolicy is "reject", "clamp" or "allow" for rects that would be off screen.
+type PlacementConfig struct {
+	BoundsPolicy models.BoundsPolicy `json:"boundsPolicy"`
+	// Snap aligns requested rects and relative moves with nearby edges
+	Snap models.SnapOptions `json:"snap"`
+}
+
+// LinuxConfig holds the settings of the Linux command line tool.
+// Backend is "sway", "i3" or empty to detect it from $SWAYSOCK and $I3SOCK.
+type LinuxConfig struct {
+	Backend string `json:"backend"`
+}
+
+// ScriptsConfig holds user script settings. Scripts are loaded from the
+// scripts directory next to the config file; TimeoutMS limits each run.
+type ScriptsConfig struct {
+	Enabled   bool `json:"enabled"`
+	TimeoutMS int  `json:"timeoutMs"`
+}
+
+// MetricsConfig holds process metrics sampling settings.
+// An interval of 0 disables sampling.
+type MetricsConfig struct {
+	IntervalMS int `json:"intervalMs"`
+}
+
 // Default returns a configuration with sensible defaults
 func Default() *Config {
 	return &Config{
@@ -87,6 +149,33 @@ func Default() *Config {
 			WindowOffset: 10,
 			DebounceMS:   200,
 		},
+		Layouts:        []models.Layout{},
+		DisplayLayouts: []models.DisplayLayout{},
+		Filters: FilterConfig{
+			DefaultProfile: filter.ProfileApplications,
+			Profiles:       filter.DefaultProfiles(),
+		},
+		Placement: PlacementConfig{
+			BoundsPolicy: models.BoundsReject,
+		},
+		Metrics: MetricsConfig{
+			IntervalMS: 2000,
+		},
+		FocusFollowsMouse: models.FocusFollowOptions{
+			DelayMS:      150,
+			RaiseDelayMS: 500,
+		},
+		WindowDrag: models.DragOptions{
+			SnapThreshold: 10,
+		},
+		Tiling: models.TilingOptions{
+			Layout:      models.TilingMasterStack,
+			MasterRatio: 0.55,
+			Gap:         8,
+		},
+		Scripts: ScriptsConfig{
+			TimeoutMS: 2000,
+		},
 	}
 }
 
@@ -145,3 +234,8 @@ func GetConfigPath() string {
 	}
 	return filepath.Join(home, ".config", "hptools", "config.json")
 }
+
+// GetScriptsDir returns the scripts directory that belongs to a config file
+func GetScriptsDir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), scriptsDirName)
+}
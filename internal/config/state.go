@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"hptools/internal/models"
+)
+
+// stateFileName is the file next to the config that holds runtime state
+const stateFileName = "state.json"
+
+// WindowState is the last known placement of the main window.
+// MonitorBounds identifies the monitor the window was on; monitor names are
+// not kept because Windows renumbers them when displays are reconnected.
+type WindowState struct {
+	X             int         `json:"x"`
+	Y             int         `json:"y"`
+	Width         int         `json:"width"`
+	Height        int         `json:"height"`
+	MonitorBounds models.Rect `json:"monitorBounds"`
+	Hidden        bool        `json:"hidden"`
+}
+
+// GetStatePath returns the state file path that belongs to a config file
+func GetStatePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), stateFileName)
+}
+
+// LoadWindowState loads the saved window state. It returns nil without an
+// error if nothing has been saved yet.
+func LoadWindowState(statePath string) (*WindowState, error) {
+	data, err := os.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+
+	var state WindowState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing state file: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveWindowState saves the window state, replacing the file atomically so
+// a crash while writing never leaves a truncated state behind
+func SaveWindowState(state WindowState, statePath string) error {
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling state: %w", err)
+	}
+
+	tmp := statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	if err := os.Rename(tmp, statePath); err != nil {
+		return fmt.Errorf("replacing state file: %w", err)
+	}
+	return nil
+}
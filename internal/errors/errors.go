@@ -14,6 +14,10 @@ const (
 	ErrorTypeAPI ErrorType = "api"
 	// ErrorTypeConfig represents configuration errors
 	ErrorTypeConfig ErrorType = "config"
+	// ErrorTypePermission represents actions the user lacks the rights for
+	ErrorTypePermission ErrorType = "permission"
+	// ErrorTypeBounds represents window rects outside the monitor topology
+	ErrorTypeBounds ErrorType = "bounds"
 )
 
 // AppError represents a structured application error
@@ -71,3 +75,21 @@ func NewConfigError(message string, cause error) *AppError {
 		Cause:   cause,
 	}
 }
+
+// NewBoundsError creates a new error for a rect outside the monitors
+func NewBoundsError(message string, cause error) *AppError {
+	return &AppError{
+		Type:    ErrorTypeBounds,
+		Message: message,
+		Cause:   cause,
+	}
+}
+
+// NewPermissionError creates a new error for an action that was denied
+func NewPermissionError(message string, cause error) *AppError {
+	return &AppError{
+		Type:    ErrorTypePermission,
+		Message: message,
+		Cause:   cause,
+	}
+}
@@ -0,0 +1,45 @@
+package events
+
+import "sync"
+
+// Feed delivers values of type T to a set of subscribers
+type Feed[T any] struct {
+	mu        sync.Mutex
+	nextID    int
+	listeners map[int]func(T)
+}
+
+// NewFeed creates a feed without subscribers
+func NewFeed[T any]() *Feed[T] {
+	return &Feed[T]{listeners: make(map[int]func(T))}
+}
+
+// Subscribe registers fn and returns a function that removes it again
+func (f *Feed[T]) Subscribe(fn func(T)) func() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.nextID
+	f.nextID++
+	f.listeners[id] = fn
+
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.listeners, id)
+	}
+}
+
+// Send calls every subscriber with v outside the lock
+func (f *Feed[T]) Send(v T) {
+	f.mu.Lock()
+	listeners := make([]func(T), 0, len(f.listeners))
+	for _, fn := range f.listeners {
+		listeners = append(listeners, fn)
+	}
+	f.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(v)
+	}
+}
@@ -0,0 +1,22 @@
+package events
+
+// Notifier keeps a set of change listeners and calls them on Notify. It is a
+// Feed whose values carry no data.
+type Notifier struct {
+	feed *Feed[struct{}]
+}
+
+// NewNotifier creates a notifier without listeners
+func NewNotifier() *Notifier {
+	return &Notifier{feed: NewFeed[struct{}]()}
+}
+
+// Subscribe registers fn and returns a function that removes it again
+func (n *Notifier) Subscribe(fn func()) func() {
+	return n.feed.Subscribe(func(struct{}) { fn() })
+}
+
+// Notify calls every registered listener outside the lock
+func (n *Notifier) Notify() {
+	n.feed.Send(struct{}{})
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"sync"
+
+	"hptools/internal/models"
+)
+
+// RecentWindows is a most recently used list of managed windows that
+// notifies its listeners when the list changes
+type RecentWindows struct {
+	mu      sync.Mutex
+	limit   int
+	windows []models.RecentWindow
+	changed *Notifier
+}
+
+// NewRecentWindows creates an empty list that keeps at most limit windows
+func NewRecentWindows(limit int) *RecentWindows {
+	return &RecentWindows{limit: limit, changed: NewNotifier()}
+}
+
+// Record moves the window to the front of the list, dropping older entries
+// of the same process
+func (r *RecentWindows) Record(entry models.RecentWindow) {
+	r.mu.Lock()
+	if len(r.windows) > 0 && r.windows[0] == entry {
+		r.mu.Unlock()
+		return
+	}
+	windows := []models.RecentWindow{entry}
+	for _, w := range r.windows {
+		if w.PID != entry.PID && len(windows) < r.limit {
+			windows = append(windows, w)
+		}
+	}
+	r.windows = windows
+	r.mu.Unlock()
+
+	r.changed.Notify()
+}
+
+// List returns the recent windows, newest first
+func (r *RecentWindows) List() []models.RecentWindow {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.RecentWindow(nil), r.windows...)
+}
+
+// Subscribe registers fn to be called when the list changes and returns a
+// function that removes it again
+func (r *RecentWindows) Subscribe(fn func()) func() {
+	return r.changed.Subscribe(fn)
+}
@@ -0,0 +1,38 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+
+	"hptools/internal/models"
+)
+
+func TestRecentWindows(t *testing.T) {
+	recent := NewRecentWindows(3)
+	notified := 0
+	recent.Subscribe(func() { notified++ })
+
+	for _, w := range []models.RecentWindow{
+		{PID: 1, Title: "one"},
+		{PID: 2, Title: "two"},
+		{PID: 3, Title: "three"},
+		{PID: 4, Title: "four"},
+		{PID: 2, Title: "two, renamed"},
+		{PID: 2, Title: "two, renamed"},
+	} {
+		recent.Record(w)
+	}
+
+	want := []models.RecentWindow{
+		{PID: 2, Title: "two, renamed"},
+		{PID: 4, Title: "four"},
+		{PID: 3, Title: "three"},
+	}
+	if got := recent.List(); !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+	// Recording the window that is already first does not notify
+	if notified != 5 {
+		t.Errorf("notified %d times, want 5", notified)
+	}
+}
@@ -0,0 +1,48 @@
+package filter
+
+import "hptools/internal/models"
+
+const (
+	// ProfileApplications is the default profile listing user applications
+	ProfileApplications = "applications"
+	// ProfileDebug lists every process with a visible window
+	ProfileDebug = "debug"
+)
+
+// systemProcesses are image names hidden by the applications profile
+var systemProcesses = []string{
+	"system", "smss.exe", "csrss.exe", "wininit.exe", "winlogon.exe",
+	"services.exe", "lsass.exe", "svchost.exe", "spoolsv.exe",
+	"dwm.exe", "audiodg.exe", "conhost.exe", "taskmgr.exe",
+	"cmd.exe", "powershell.exe", "wuauclt.exe", "mmc.exe",
+	"rundll32.exe", "dllhost.exe", "sihost.exe", "fontdrvhost.exe",
+	"winrt.exe", "backgroundtaskhost.exe", "runtimebroker.exe",
+}
+
+// DefaultProfiles returns the built-in filter profiles
+func DefaultProfiles() []models.FilterProfile {
+	sessionZero := 0
+
+	rules := make([]models.FilterRule, 0, len(systemProcesses)+2)
+	for _, name := range systemProcesses {
+		rules = append(rules, models.FilterRule{Action: models.FilterExclude, ImageGlob: name})
+	}
+	// Applications run in user sessions (not session 0) and have an .exe extension
+	rules = append(rules,
+		models.FilterRule{Action: models.FilterExclude, Session: &sessionZero},
+		models.FilterRule{Action: models.FilterInclude, ImageGlob: "*.exe"},
+	)
+
+	return []models.FilterProfile{
+		{
+			Name:    ProfileApplications,
+			Default: models.FilterExclude,
+			Group:   true,
+			Rules:   rules,
+		},
+		{
+			Name:    ProfileDebug,
+			Default: models.FilterInclude,
+		},
+	}
+}
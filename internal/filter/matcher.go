@@ -0,0 +1,184 @@
+package filter
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"hptools/internal/models"
+)
+
+// Matcher evaluates a compiled filter profile against processes
+type Matcher struct {
+	name           string
+	group          bool
+	defaultInclude bool
+	rules          []rule
+}
+
+type rule struct {
+	include     bool
+	imageGlob   string
+	pathPrefix  string
+	windowClass string
+	title       *regexp.Regexp
+	session     *int
+	elevated    *bool
+}
+
+// Compile validates a filter profile and prepares it for matching
+func Compile(profile models.FilterProfile) (*Matcher, error) {
+	defaultInclude, err := isInclude(profile.Default, models.FilterInclude)
+	if err != nil {
+		return nil, fmt.Errorf("profile %q default: %w", profile.Name, err)
+	}
+
+	m := &Matcher{
+		name:           profile.Name,
+		group:          profile.Group,
+		defaultInclude: defaultInclude,
+		rules:          make([]rule, 0, len(profile.Rules)),
+	}
+
+	for i, r := range profile.Rules {
+		include, err := isInclude(r.Action, "")
+		if err != nil {
+			return nil, fmt.Errorf("profile %q rule %d: %w", profile.Name, i, err)
+		}
+
+		compiled := rule{
+			include:     include,
+			imageGlob:   strings.ToLower(r.ImageGlob),
+			pathPrefix:  normalizePath(r.PathPrefix),
+			windowClass: strings.ToLower(r.WindowClass),
+			session:     r.Session,
+			elevated:    r.Elevated,
+		}
+
+		if compiled.imageGlob != "" {
+			if _, err := path.Match(compiled.imageGlob, ""); err != nil {
+				return nil, fmt.Errorf("profile %q rule %d: invalid image glob: %w", profile.Name, i, err)
+			}
+		}
+		if compiled.windowClass != "" {
+			if _, err := path.Match(compiled.windowClass, ""); err != nil {
+				return nil, fmt.Errorf("profile %q rule %d: invalid window class glob: %w", profile.Name, i, err)
+			}
+		}
+		if r.TitleRegex != "" {
+			if compiled.title, err = regexp.Compile(r.TitleRegex); err != nil {
+				return nil, fmt.Errorf("profile %q rule %d: invalid title regex: %w", profile.Name, i, err)
+			}
+		}
+
+		m.rules = append(m.rules, compiled)
+	}
+
+	return m, nil
+}
+
+// Name returns the profile name
+func (m *Matcher) Name() string {
+	return m.name
+}
+
+// Group reports whether processes of the same application should be merged
+func (m *Matcher) Group() bool {
+	return m.group
+}
+
+// Match reports whether a process should be shown
+func (m *Matcher) Match(proc models.ProcessInfo) bool {
+	for _, r := range m.rules {
+		if r.matches(proc) {
+			return r.include
+		}
+	}
+	return m.defaultInclude
+}
+
+// MayMatch reports whether a process could be shown once its windows and
+// details are known. It only looks at image name and session, so callers can
+// skip window enumeration and enrichment for processes it rejects.
+func (m *Matcher) MayMatch(proc models.ProcessInfo) bool {
+	for _, r := range m.rules {
+		if !r.matchesCheap(proc) {
+			continue
+		}
+		if !r.needsDetails() {
+			return r.include
+		}
+		// The rule may or may not match; an include rule could show the
+		// process, an exclude rule falls through to the rules after it
+		if r.include {
+			return true
+		}
+	}
+	return m.defaultInclude
+}
+
+// matchesCheap reports whether the image and session criteria match
+func (r *rule) matchesCheap(proc models.ProcessInfo) bool {
+	if r.imageGlob != "" {
+		if ok, _ := path.Match(r.imageGlob, strings.ToLower(proc.ImageName)); !ok {
+			return false
+		}
+	}
+	return r.session == nil || proc.SessionNum == *r.session
+}
+
+// needsDetails reports whether the rule has criteria that depend on windows or details
+func (r *rule) needsDetails() bool {
+	return r.pathPrefix != "" || r.windowClass != "" || r.title != nil || r.elevated != nil
+}
+
+// matches reports whether all criteria set on the rule match the process
+func (r *rule) matches(proc models.ProcessInfo) bool {
+	if r.imageGlob != "" {
+		if ok, _ := path.Match(r.imageGlob, strings.ToLower(proc.ImageName)); !ok {
+			return false
+		}
+	}
+	if r.pathPrefix != "" {
+		if proc.Details == nil || !strings.HasPrefix(normalizePath(proc.Details.ExePath), r.pathPrefix) {
+			return false
+		}
+	}
+	if r.windowClass != "" {
+		if ok, _ := path.Match(r.windowClass, strings.ToLower(proc.WindowClass)); !ok {
+			return false
+		}
+	}
+	if r.title != nil && !r.title.MatchString(proc.WindowTitle) {
+		return false
+	}
+	if r.session != nil && proc.SessionNum != *r.session {
+		return false
+	}
+	if r.elevated != nil {
+		if proc.Details == nil || proc.Details.Elevated != *r.elevated {
+			return false
+		}
+	}
+	return true
+}
+
+// isInclude converts an action to a bool, using fallback when action is empty
+func isInclude(action, fallback models.FilterAction) (bool, error) {
+	if action == "" {
+		action = fallback
+	}
+	switch action {
+	case models.FilterInclude:
+		return true, nil
+	case models.FilterExclude:
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown action %q", action)
+}
+
+// normalizePath lowercases a Windows path and uses forward slashes
+func normalizePath(p string) string {
+	return strings.ToLower(strings.ReplaceAll(p, `\`, "/"))
+}
@@ -0,0 +1,264 @@
+package filter
+
+import (
+	"testing"
+
+	"hptools/internal/models"
+)
+
+func TestMatch(t *testing.T) {
+	session := 0
+	elevated := true
+
+	tests := []struct {
+		name    string
+		profile models.FilterProfile
+		proc    models.ProcessInfo
+		want    bool
+	}{
+		{
+			name: "image glob is case insensitive",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterInclude, ImageGlob: "*.EXE"},
+			}},
+			proc: models.ProcessInfo{ImageName: "Notepad.exe"},
+			want: true,
+		},
+		{
+			name: "image glob does not match",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterInclude, ImageGlob: "code*.exe"},
+			}},
+			proc: models.ProcessInfo{ImageName: "notepad.exe"},
+			want: false,
+		},
+		{
+			name: "title regex",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterInclude, TitleRegex: `- Visual Studio Code$`},
+			}},
+			proc: models.ProcessInfo{ImageName: "code.exe", WindowTitle: "main.go - Visual Studio Code"},
+			want: true,
+		},
+		{
+			name: "title regex does not match",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterInclude, TitleRegex: `^Untitled`},
+			}},
+			proc: models.ProcessInfo{ImageName: "notepad.exe", WindowTitle: "notes.txt - Notepad"},
+			want: false,
+		},
+		{
+			name: "window class glob",
+			profile: models.FilterProfile{Default: models.FilterInclude, Rules: []models.FilterRule{
+				{Action: models.FilterExclude, WindowClass: "ConsoleWindow*"},
+			}},
+			proc: models.ProcessInfo{ImageName: "cmd.exe", WindowClass: "consolewindowclass"},
+			want: false,
+		},
+		{
+			name: "path prefix needs details",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterInclude, PathPrefix: `C:\Program Files\`},
+			}},
+			proc: models.ProcessInfo{ImageName: "app.exe"},
+			want: false,
+		},
+		{
+			name: "path prefix ignores case and separators",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterInclude, PathPrefix: `C:\Program Files\`},
+			}},
+			proc: models.ProcessInfo{ImageName: "app.exe", Details: &models.ProcessDetails{ExePath: "c:/program files/app/app.exe"}},
+			want: true,
+		},
+		{
+			name: "all criteria of a rule must match",
+			profile: models.FilterProfile{Default: models.FilterInclude, Rules: []models.FilterRule{
+				{Action: models.FilterExclude, ImageGlob: "app.exe", Elevated: &elevated},
+			}},
+			proc: models.ProcessInfo{ImageName: "app.exe", Details: &models.ProcessDetails{Elevated: false}},
+			want: true,
+		},
+		{
+			name: "first matching rule wins over a later include",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterExclude, ImageGlob: "svchost.exe"},
+				{Action: models.FilterInclude, ImageGlob: "*.exe"},
+			}},
+			proc: models.ProcessInfo{ImageName: "svchost.exe"},
+			want: false,
+		},
+		{
+			name: "first matching rule wins over a later exclude",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterInclude, ImageGlob: "*.exe"},
+				{Action: models.FilterExclude, Session: &session},
+			}},
+			proc: models.ProcessInfo{ImageName: "svc.exe", SessionNum: 0},
+			want: true,
+		},
+		{
+			name:    "default include",
+			profile: models.FilterProfile{Default: models.FilterInclude},
+			proc:    models.ProcessInfo{ImageName: "anything"},
+			want:    true,
+		},
+		{
+			name:    "default exclude",
+			profile: models.FilterProfile{Default: models.FilterExclude},
+			proc:    models.ProcessInfo{ImageName: "anything"},
+			want:    false,
+		},
+		{
+			name:    "empty default includes",
+			profile: models.FilterProfile{},
+			proc:    models.ProcessInfo{ImageName: "anything"},
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile(tt.profile)
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			if got := m.Match(tt.proc); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile models.FilterProfile
+	}{
+		{
+			name:    "unknown default",
+			profile: models.FilterProfile{Default: "hide"},
+		},
+		{
+			name:    "missing rule action",
+			profile: models.FilterProfile{Rules: []models.FilterRule{{ImageGlob: "*.exe"}}},
+		},
+		{
+			name:    "unknown rule action",
+			profile: models.FilterProfile{Rules: []models.FilterRule{{Action: "show", ImageGlob: "*.exe"}}},
+		},
+		{
+			name:    "invalid image glob",
+			profile: models.FilterProfile{Rules: []models.FilterRule{{Action: models.FilterInclude, ImageGlob: "[a-"}}},
+		},
+		{
+			name:    "invalid window class glob",
+			profile: models.FilterProfile{Rules: []models.FilterRule{{Action: models.FilterInclude, WindowClass: "["}}},
+		},
+		{
+			name:    "invalid title regex",
+			profile: models.FilterProfile{Rules: []models.FilterRule{{Action: models.FilterInclude, TitleRegex: "(unclosed"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(tt.profile); err == nil {
+				t.Error("Compile() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestMayMatch(t *testing.T) {
+	elevated := true
+
+	tests := []struct {
+		name    string
+		profile models.FilterProfile
+		proc    models.ProcessInfo
+		want    bool
+	}{
+		{
+			name: "cheap exclude rule is final",
+			profile: models.FilterProfile{Default: models.FilterInclude, Rules: []models.FilterRule{
+				{Action: models.FilterExclude, ImageGlob: "svchost.exe"},
+			}},
+			proc: models.ProcessInfo{ImageName: "svchost.exe"},
+			want: false,
+		},
+		{
+			name: "include rule needing a window title may match",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterInclude, ImageGlob: "code.exe", TitleRegex: "Code$"},
+			}},
+			proc: models.ProcessInfo{ImageName: "code.exe"},
+			want: true,
+		},
+		{
+			name: "exclude rule needing details falls through",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterExclude, Elevated: &elevated},
+				{Action: models.FilterInclude, ImageGlob: "*.exe"},
+			}},
+			proc: models.ProcessInfo{ImageName: "app.exe"},
+			want: true,
+		},
+		{
+			name: "image mismatch skips include rule",
+			profile: models.FilterProfile{Default: models.FilterExclude, Rules: []models.FilterRule{
+				{Action: models.FilterInclude, ImageGlob: "code.exe", TitleRegex: "Code$"},
+			}},
+			proc: models.ProcessInfo{ImageName: "notepad.exe"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile(tt.profile)
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			if got := m.MayMatch(tt.proc); got != tt.want {
+				t.Errorf("MayMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultProfiles(t *testing.T) {
+	matchers := make(map[string]*Matcher)
+	for _, profile := range DefaultProfiles() {
+		m, err := Compile(profile)
+		if err != nil {
+			t.Fatalf("built-in profile %q: %v", profile.Name, err)
+		}
+		matchers[profile.Name] = m
+	}
+
+	apps, ok := matchers[ProfileApplications]
+	if !ok {
+		t.Fatalf("missing %q profile", ProfileApplications)
+	}
+	if _, ok := matchers[ProfileDebug]; !ok {
+		t.Fatalf("missing %q profile", ProfileDebug)
+	}
+
+	tests := []struct {
+		proc models.ProcessInfo
+		want bool
+	}{
+		{models.ProcessInfo{ImageName: "notepad.exe", SessionNum: 1}, true},
+		{models.ProcessInfo{ImageName: "svchost.exe", SessionNum: 1}, false},
+		{models.ProcessInfo{ImageName: "notepad.exe", SessionNum: 0}, false},
+		{models.ProcessInfo{ImageName: "System", SessionNum: 1}, false},
+		{models.ProcessInfo{ImageName: "script.bat", SessionNum: 1}, false},
+	}
+	for _, tt := range tests {
+		if got := apps.Match(tt.proc); got != tt.want {
+			t.Errorf("%s in session %d: Match() = %v, want %v", tt.proc.ImageName, tt.proc.SessionNum, got, tt.want)
+		}
+	}
+}
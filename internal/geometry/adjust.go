@@ -0,0 +1,114 @@
+package geometry
+
+import (
+	"fmt"
+
+	"hptools/internal/models"
+)
+
+// anchorFactors returns where the anchor sits on each axis in halves of the
+// window size: 0 is the left/top edge, 1 the center and 2 the right/bottom edge
+func anchorFactors(anchor models.Anchor) (int, int, error) {
+	switch anchor {
+	case models.AnchorTopLeft, "":
+		return 0, 0, nil
+	case models.AnchorTop:
+		return 1, 0, nil
+	case models.AnchorTopRight:
+		return 2, 0, nil
+	case models.AnchorLeft:
+		return 0, 1, nil
+	case models.AnchorCenter:
+		return 1, 1, nil
+	case models.AnchorRight:
+		return 2, 1, nil
+	case models.AnchorBottomLeft:
+		return 0, 2, nil
+	case models.AnchorBottom:
+		return 1, 2, nil
+	case models.AnchorBottomRight:
+		return 2, 2, nil
+	}
+	return 0, 0, fmt.Errorf("unknown anchor %q", anchor)
+}
+
+// Resize changes the size of rect while keeping the anchor point in place
+func Resize(rect models.Rect, width, height int, anchor models.Anchor) (models.Rect, error) {
+	fx, fy, err := anchorFactors(anchor)
+	if err != nil {
+		return models.Rect{}, err
+	}
+	width, height = max(width, 1), max(height, 1)
+	return models.Rect{
+		X:      rect.X + (rect.Width-width)*fx/2,
+		Y:      rect.Y + (rect.Height-height)*fy/2,
+		Width:  width,
+		Height: height,
+	}, nil
+}
+
+// Clamp shrinks rect to fit area and then shifts it inside
+func Clamp(rect, area models.Rect) models.Rect {
+	width := min(rect.Width, area.Width)
+	height := min(rect.Height, area.Height)
+	return models.Rect{
+		X:      max(area.X, min(rect.X, area.Right()-width)),
+		Y:      max(area.Y, min(rect.Y, area.Bottom()-height)),
+		Width:  width,
+		Height: height,
+	}
+}
+
+// Adjust applies a relative operation to current and clamps the result to work
+func Adjust(adjust models.WindowAdjustment, current, work models.Rect) (models.Rect, error) {
+	var target models.Rect
+	var err error
+
+	switch adjust.Kind {
+	case models.AdjustMoveBy:
+		target = current
+		target.X += adjust.DX
+		target.Y += adjust.DY
+
+	case models.AdjustResizeBy:
+		target, err = Resize(current, current.Width+adjust.DWidth, current.Height+adjust.DHeight, adjust.Anchor)
+
+	case models.AdjustScale:
+		if adjust.Percent <= 0 {
+			return models.Rect{}, fmt.Errorf("scale percent must be positive, got %d", adjust.Percent)
+		}
+		target, err = Resize(current, current.Width*adjust.Percent/100, current.Height*adjust.Percent/100, adjust.Anchor)
+
+	case models.AdjustFitAspect:
+		width, height := fitAspect(current, adjust.Width, adjust.Height, work)
+		target, err = Resize(current, width, height, adjust.Anchor)
+
+	default:
+		return models.Rect{}, fmt.Errorf("unknown adjustment %q", adjust.Kind)
+	}
+	if err != nil {
+		return models.Rect{}, err
+	}
+
+	return Clamp(target, work), nil
+}
+
+// fitAspect returns the largest size with the aspect ratio of current that
+// fits in width x height and in the work area. Zero bounds are ignored.
+func fitAspect(current models.Rect, width, height int, work models.Rect) (int, int) {
+	if current.Width <= 0 || current.Height <= 0 {
+		return current.Width, current.Height
+	}
+	if width <= 0 || width > work.Width {
+		width = work.Width
+	}
+	if height <= 0 || height > work.Height {
+		height = work.Height
+	}
+
+	// Compare width/current.Width with height/current.Height without dividing
+	if width*current.Height <= height*current.Width {
+		return width, width * current.Height / current.Width
+	}
+	return height * current.Width / current.Height, height
+}
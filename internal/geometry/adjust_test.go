@@ -0,0 +1,280 @@
+package geometry
+
+import (
+	"testing"
+
+	"hptools/internal/models"
+)
+
+func TestResize(t *testing.T) {
+	rect := models.Rect{X: 100, Y: 100, Width: 400, Height: 300}
+
+	tests := []struct {
+		name          string
+		rect          models.Rect
+		width, height int
+		anchor        models.Anchor
+		want          models.Rect
+		wantErr       bool
+	}{
+		{
+			name:   "empty anchor keeps top-left",
+			rect:   rect,
+			width:  200,
+			height: 100,
+			want:   models.Rect{X: 100, Y: 100, Width: 200, Height: 100},
+		},
+		{
+			name:   "top-left",
+			rect:   rect,
+			width:  200,
+			height: 100,
+			anchor: models.AnchorTopLeft,
+			want:   models.Rect{X: 100, Y: 100, Width: 200, Height: 100},
+		},
+		{
+			name:   "top-right",
+			rect:   rect,
+			width:  200,
+			height: 100,
+			anchor: models.AnchorTopRight,
+			want:   models.Rect{X: 300, Y: 100, Width: 200, Height: 100},
+		},
+		{
+			name:   "center",
+			rect:   rect,
+			width:  200,
+			height: 100,
+			anchor: models.AnchorCenter,
+			want:   models.Rect{X: 200, Y: 200, Width: 200, Height: 100},
+		},
+		{
+			name:   "bottom",
+			rect:   rect,
+			width:  200,
+			height: 100,
+			anchor: models.AnchorBottom,
+			want:   models.Rect{X: 200, Y: 300, Width: 200, Height: 100},
+		},
+		{
+			name:   "bottom-right",
+			rect:   rect,
+			width:  200,
+			height: 100,
+			anchor: models.AnchorBottomRight,
+			want:   models.Rect{X: 300, Y: 300, Width: 200, Height: 100},
+		},
+		{
+			name:   "odd shrink around center rounds toward the origin",
+			rect:   models.Rect{X: 0, Y: 0, Width: 101, Height: 101},
+			width:  100,
+			height: 100,
+			anchor: models.AnchorCenter,
+			want:   models.Rect{X: 0, Y: 0, Width: 100, Height: 100},
+		},
+		{
+			name:   "negative-coordinate monitor",
+			rect:   models.Rect{X: -1000, Y: -500, Width: 400, Height: 300},
+			width:  200,
+			height: 100,
+			anchor: models.AnchorBottomRight,
+			want:   models.Rect{X: -800, Y: -300, Width: 200, Height: 100},
+		},
+		{
+			name:   "size is at least 1",
+			rect:   rect,
+			width:  0,
+			height: -5,
+			want:   models.Rect{X: 100, Y: 100, Width: 1, Height: 1},
+		},
+		{
+			name:    "unknown anchor",
+			rect:    rect,
+			width:   200,
+			height:  100,
+			anchor:  "middle",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resize(tt.rect, tt.width, tt.height, tt.anchor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdjust(t *testing.T) {
+	work := models.Rect{X: 0, Y: 0, Width: 1920, Height: 1040}
+	left := models.Rect{X: -1920, Y: -200, Width: 1920, Height: 1080}
+	current := models.Rect{X: 100, Y: 100, Width: 400, Height: 300}
+
+	tests := []struct {
+		name    string
+		adjust  models.WindowAdjustment
+		current models.Rect
+		work    models.Rect
+		want    models.Rect
+		wantErr bool
+	}{
+		{
+			name:    "move by",
+			adjust:  models.WindowAdjustment{Kind: models.AdjustMoveBy, DX: 50, DY: -20},
+			current: current,
+			work:    work,
+			want:    models.Rect{X: 150, Y: 80, Width: 400, Height: 300},
+		},
+		{
+			name:    "move past the edge is clamped",
+			adjust:  models.WindowAdjustment{Kind: models.AdjustMoveBy, DX: -500},
+			current: current,
+			work:    work,
+			want:    models.Rect{X: 0, Y: 100, Width: 400, Height: 300},
+		},
+		{
+			name:    "move on a negative-coordinate monitor",
+			adjust:  models.WindowAdjustment{Kind: models.AdjustMoveBy, DX: -300},
+			current: models.Rect{X: -1800, Y: -100, Width: 400, Height: 300},
+			work:    left,
+			want:    models.Rect{X: -1920, Y: -100, Width: 400, Height: 300},
+		},
+		{
+			name:    "resize by around center",
+			adjust:  models.WindowAdjustment{Kind: models.AdjustResizeBy, DWidth: 100, DHeight: 50, Anchor: models.AnchorCenter},
+			current: current,
+			work:    work,
+			want:    models.Rect{X: 50, Y: 75, Width: 500, Height: 350},
+		},
+		{
+			name:    "resize beyond the work area shrinks to fit",
+			adjust:  models.WindowAdjustment{Kind: models.AdjustResizeBy, DWidth: 400},
+			current: models.Rect{X: 0, Y: 0, Width: 1800, Height: 1000},
+			work:    work,
+			want:    models.Rect{X: 0, Y: 0, Width: 1920, Height: 1000},
+		},
+		{
+			name:    "scale around center",
+			adjust:  models.WindowAdjustment{Kind: models.AdjustScale, Percent: 50, Anchor: models.AnchorCenter},
+			current: current,
+			work:    work,
+			want:    models.Rect{X: 200, Y: 175, Width: 200, Height: 150},
+		},
+		{
+			name:    "scale rounds down",
+			adjust:  models.WindowAdjustment{Kind: models.AdjustScale, Percent: 50},
+			current: models.Rect{X: 0, Y: 0, Width: 333, Height: 333},
+			work:    work,
+			want:    models.Rect{X: 0, Y: 0, Width: 166, Height: 166},
+		},
+		{
+			name:    "fit aspect on a negative-coordinate monitor",
+			adjust:  models.WindowAdjustment{Kind: models.AdjustFitAspect},
+			current: models.Rect{X: -1900, Y: -100, Width: 400, Height: 300},
+			work:    left,
+			want:    models.Rect{X: -1900, Y: -200, Width: 1440, Height: 1080},
+		},
+		{
+			name:    "scale percent must be positive",
+			adjust:  models.WindowAdjustment{Kind: models.AdjustScale},
+			current: current,
+			work:    work,
+			wantErr: true,
+		},
+		{
+			name:    "unknown kind",
+			adjust:  models.WindowAdjustment{Kind: "spin"},
+			current: current,
+			work:    work,
+			wantErr: true,
+		},
+		{
+			name:    "unknown anchor",
+			adjust:  models.WindowAdjustment{Kind: models.AdjustResizeBy, Anchor: "middle"},
+			current: current,
+			work:    work,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Adjust(tt.adjust, tt.current, tt.work)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Adjust() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Adjust() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitAspect(t *testing.T) {
+	work := models.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+
+	tests := []struct {
+		name                  string
+		current               models.Rect
+		width, height         int
+		wantWidth, wantHeight int
+	}{
+		{
+			name:       "unbounded fills the work area height",
+			current:    models.Rect{Width: 400, Height: 300},
+			wantWidth:  1440,
+			wantHeight: 1080,
+		},
+		{
+			name:       "width bound",
+			current:    models.Rect{Width: 400, Height: 300},
+			width:      800,
+			wantWidth:  800,
+			wantHeight: 600,
+		},
+		{
+			name:       "height bound on a portrait window",
+			current:    models.Rect{Width: 300, Height: 600},
+			width:      1000,
+			height:     400,
+			wantWidth:  200,
+			wantHeight: 400,
+		},
+		{
+			name:       "aspect rounds down",
+			current:    models.Rect{Width: 1000, Height: 333},
+			width:      500,
+			wantWidth:  500,
+			wantHeight: 166,
+		},
+		{
+			name:       "bounds beyond the work area are clamped",
+			current:    models.Rect{Width: 100, Height: 100},
+			width:      5000,
+			height:     5000,
+			wantWidth:  1080,
+			wantHeight: 1080,
+		},
+		{
+			name:       "empty window is left alone",
+			current:    models.Rect{Width: 0, Height: 300},
+			width:      800,
+			wantWidth:  0,
+			wantHeight: 300,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := fitAspect(tt.current, tt.width, tt.height, work)
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("fitAspect() = %dx%d, want %dx%d", width, height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
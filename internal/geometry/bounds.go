@@ -0,0 +1,101 @@
+package geometry
+
+import (
+	"errors"
+	"fmt"
+
+	"hptools/internal/models"
+)
+
+// minReachable is how many pixels of a window's title strip must be on a
+// work area for the user to still grab it with the mouse
+const minReachable = 40
+
+// ErrUnreachable is returned by Confine when a rect would leave the window
+// where the user cannot reach it
+var ErrUnreachable = errors.New("rect is not reachable on any monitor")
+
+// Intersect returns the overlap of two rects, which is empty if they do not overlap
+func Intersect(a, b models.Rect) models.Rect {
+	x, y := max(a.X, b.X), max(a.Y, b.Y)
+	right, bottom := min(a.Right(), b.Right()), min(a.Bottom(), b.Bottom())
+	if right <= x || bottom <= y {
+		return models.Rect{}
+	}
+	return models.Rect{X: x, Y: y, Width: right - x, Height: bottom - y}
+}
+
+// Contains reports whether inner lies completely within outer
+func Contains(outer, inner models.Rect) bool {
+	return inner.X >= outer.X && inner.Y >= outer.Y &&
+		inner.Right() <= outer.Right() && inner.Bottom() <= outer.Bottom()
+}
+
+// Reachable reports whether enough of the top strip of rect, where the title
+// bar usually is, lies on a work area to drag the window with the mouse
+func Reachable(rect models.Rect, monitors []models.MonitorInfo) bool {
+	strip := models.Rect{X: rect.X, Y: rect.Y, Width: rect.Width, Height: min(rect.Height, minReachable)}
+	need := min(minReachable, rect.Width)
+
+	for _, m := range monitors {
+		if overlap := Intersect(strip, m.WorkArea); overlap.Width > 0 && overlap.Width >= need {
+			return true
+		}
+	}
+	return false
+}
+
+// Confine checks rect against the monitors according to policy. It returns
+// the rect to apply, or ErrUnreachable if the policy rejects it.
+func Confine(rect models.Rect, monitors []models.MonitorInfo, policy models.BoundsPolicy) (models.Rect, error) {
+	switch policy {
+	case models.BoundsAllow:
+		return rect, nil
+
+	case models.BoundsReject, "":
+		if !Reachable(rect, monitors) {
+			return models.Rect{}, ErrUnreachable
+		}
+		return rect, nil
+
+	case models.BoundsClamp:
+		for _, m := range monitors {
+			if Contains(m.WorkArea, rect) {
+				return rect, nil
+			}
+		}
+		idx := MonitorAt(monitors, rect)
+		if idx < 0 {
+			return models.Rect{}, ErrUnreachable
+		}
+		return Clamp(rect, monitors[idx].WorkArea), nil
+	}
+
+	return models.Rect{}, fmt.Errorf("unknown bounds policy %q", policy)
+}
+
+// CenterIn centers rect in area, shrinking it if it does not fit
+func CenterIn(rect, area models.Rect) models.Rect {
+	width := min(rect.Width, area.Width)
+	height := min(rect.Height, area.Height)
+	return models.Rect{
+		X:      area.X + (area.Width-width)/2,
+		Y:      area.Y + (area.Height-height)/2,
+		Width:  width,
+		Height: height,
+	}
+}
+
+// Primary returns the index of the primary monitor, falling back to the
+// first one. It returns -1 if there are no monitors.
+func Primary(monitors []models.MonitorInfo) int {
+	for i, m := range monitors {
+		if m.Primary {
+			return i
+		}
+	}
+	if len(monitors) == 0 {
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,226 @@
+package geometry
+
+import (
+	"errors"
+	"testing"
+
+	"hptools/internal/models"
+)
+
+var (
+	primaryMonitor = models.MonitorInfo{
+		Handle:   1,
+		Primary:  true,
+		Bounds:   models.Rect{X: 0, Y: 0, Width: 1920, Height: 1080},
+		WorkArea: models.Rect{X: 0, Y: 0, Width: 1920, Height: 1040},
+	}
+	leftMonitor = models.MonitorInfo{
+		Handle:   2,
+		Bounds:   models.Rect{X: -1920, Y: -200, Width: 1920, Height: 1080},
+		WorkArea: models.Rect{X: -1920, Y: -200, Width: 1920, Height: 1080},
+	}
+)
+
+func TestClamp(t *testing.T) {
+	tests := []struct {
+		name string
+		rect models.Rect
+		area models.Rect
+		want models.Rect
+	}{
+		{
+			name: "inside stays",
+			rect: models.Rect{X: 100, Y: 100, Width: 400, Height: 300},
+			area: primaryMonitor.WorkArea,
+			want: models.Rect{X: 100, Y: 100, Width: 400, Height: 300},
+		},
+		{
+			name: "larger than the area shrinks to it",
+			rect: models.Rect{X: -50, Y: -50, Width: 3000, Height: 2000},
+			area: primaryMonitor.WorkArea,
+			want: models.Rect{X: 0, Y: 0, Width: 1920, Height: 1040},
+		},
+		{
+			name: "past the bottom-right corner shifts back",
+			rect: models.Rect{X: 1800, Y: 900, Width: 400, Height: 300},
+			area: primaryMonitor.WorkArea,
+			want: models.Rect{X: 1520, Y: 740, Width: 400, Height: 300},
+		},
+		{
+			name: "negative-coordinate area",
+			rect: models.Rect{X: -100, Y: 900, Width: 400, Height: 300},
+			area: leftMonitor.WorkArea,
+			want: models.Rect{X: -400, Y: 580, Width: 400, Height: 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Clamp(tt.rect, tt.area); got != tt.want {
+				t.Errorf("Clamp() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReachable(t *testing.T) {
+	single := []models.MonitorInfo{primaryMonitor}
+	both := []models.MonitorInfo{primaryMonitor, leftMonitor}
+
+	tests := []struct {
+		name     string
+		rect     models.Rect
+		monitors []models.MonitorInfo
+		want     bool
+	}{
+		{
+			name:     "on screen",
+			rect:     models.Rect{X: 100, Y: 100, Width: 400, Height: 300},
+			monitors: single,
+			want:     true,
+		},
+		{
+			name:     "40 pixels of title bar on screen",
+			rect:     models.Rect{X: -360, Y: 100, Width: 400, Height: 300},
+			monitors: single,
+			want:     true,
+		},
+		{
+			name:     "39 pixels of title bar on screen",
+			rect:     models.Rect{X: -361, Y: 100, Width: 400, Height: 300},
+			monitors: single,
+			want:     false,
+		},
+		{
+			name:     "title bar above the top edge",
+			rect:     models.Rect{X: 100, Y: -50, Width: 400, Height: 300},
+			monitors: single,
+			want:     false,
+		},
+		{
+			name:     "title bar under the taskbar",
+			rect:     models.Rect{X: 100, Y: 1040, Width: 400, Height: 300},
+			monitors: single,
+			want:     false,
+		},
+		{
+			name:     "narrow window fully on screen",
+			rect:     models.Rect{X: 1890, Y: 100, Width: 30, Height: 300},
+			monitors: single,
+			want:     true,
+		},
+		{
+			name:     "narrow window partly off screen",
+			rect:     models.Rect{X: 1900, Y: 100, Width: 30, Height: 300},
+			monitors: single,
+			want:     false,
+		},
+		{
+			name:     "negative-coordinate monitor",
+			rect:     models.Rect{X: -1000, Y: -150, Width: 400, Height: 300},
+			monitors: both,
+			want:     true,
+		},
+		{
+			name: "no monitors",
+			rect: models.Rect{X: 100, Y: 100, Width: 400, Height: 300},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Reachable(tt.rect, tt.monitors); got != tt.want {
+				t.Errorf("Reachable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfine(t *testing.T) {
+	single := []models.MonitorInfo{primaryMonitor}
+	both := []models.MonitorInfo{primaryMonitor, leftMonitor}
+	offscreen := models.Rect{X: 5000, Y: 5000, Width: 400, Height: 300}
+
+	tests := []struct {
+		name     string
+		rect     models.Rect
+		monitors []models.MonitorInfo
+		policy   models.BoundsPolicy
+		want     models.Rect
+		wantErr  error
+	}{
+		{
+			name:     "allow keeps offscreen rects",
+			rect:     offscreen,
+			monitors: single,
+			policy:   models.BoundsAllow,
+			want:     offscreen,
+		},
+		{
+			name:     "reject keeps reachable rects",
+			rect:     models.Rect{X: -360, Y: 100, Width: 400, Height: 300},
+			monitors: single,
+			policy:   models.BoundsReject,
+			want:     models.Rect{X: -360, Y: 100, Width: 400, Height: 300},
+		},
+		{
+			name:     "reject refuses unreachable rects",
+			rect:     offscreen,
+			monitors: single,
+			policy:   models.BoundsReject,
+			wantErr:  ErrUnreachable,
+		},
+		{
+			name:     "empty policy rejects",
+			rect:     offscreen,
+			monitors: single,
+			wantErr:  ErrUnreachable,
+		},
+		{
+			name:     "clamp keeps contained rects",
+			rect:     models.Rect{X: 100, Y: 100, Width: 400, Height: 300},
+			monitors: single,
+			policy:   models.BoundsClamp,
+			want:     models.Rect{X: 100, Y: 100, Width: 400, Height: 300},
+		},
+		{
+			name:     "clamp moves rects into the nearest work area",
+			rect:     offscreen,
+			monitors: single,
+			policy:   models.BoundsClamp,
+			want:     models.Rect{X: 1520, Y: 740, Width: 400, Height: 300},
+		},
+		{
+			name:     "clamp on a negative-coordinate monitor",
+			rect:     models.Rect{X: -2000, Y: -300, Width: 400, Height: 300},
+			monitors: both,
+			policy:   models.BoundsClamp,
+			want:     models.Rect{X: -1920, Y: -200, Width: 400, Height: 300},
+		},
+		{
+			name:    "clamp without monitors",
+			rect:    offscreen,
+			policy:  models.BoundsClamp,
+			wantErr: ErrUnreachable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Confine(tt.rect, tt.monitors, tt.policy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Confine() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Confine() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown policy", func(t *testing.T) {
+		if _, err := Confine(offscreen, single, "wrap"); err == nil || errors.Is(err, ErrUnreachable) {
+			t.Errorf("Confine() error = %v, want an unknown policy error", err)
+		}
+	})
+}
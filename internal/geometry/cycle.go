@@ -0,0 +1,65 @@
+package geometry
+
+import (
+	"slices"
+	"sync"
+
+	"hptools/internal/models"
+)
+
+// cycleTolerance is how far a window may be from the rect the last cycle step
+// gave it and still count as untouched. Window managers adjust requested
+// rects for minimum sizes and borders.
+const cycleTolerance = 16
+
+// CycleTracker remembers the last cycle step so repeated invocations on the
+// same window advance through the cycle. Moving the window by other means,
+// cycling another window or using another cycle starts over.
+type CycleTracker struct {
+	mu     sync.Mutex
+	window uintptr
+	cycle  []models.Placement
+	index  int
+	rect   models.Rect
+}
+
+// NewCycleTracker creates a tracker without history
+func NewCycleTracker() *CycleTracker {
+	return &CycleTracker{}
+}
+
+// Next returns the index in cycle of the placement to apply to a window that
+// is currently at rect
+func (t *CycleTracker) Next(window uintptr, cycle []models.Placement, rect models.Rect) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(cycle) == 0 || t.window != window || !slices.Equal(t.cycle, cycle) || !near(t.rect, rect) {
+		return 0
+	}
+	return (t.index + 1) % len(cycle)
+}
+
+// Record stores the step that was applied to a window and the rect it produced
+func (t *CycleTracker) Record(window uintptr, cycle []models.Placement, index int, rect models.Rect) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.window = window
+	t.cycle = slices.Clone(cycle)
+	t.index = index
+	t.rect = rect
+}
+
+// near reports whether two rects differ by at most cycleTolerance on every edge
+func near(a, b models.Rect) bool {
+	return abs(a.X-b.X) <= cycleTolerance && abs(a.Y-b.Y) <= cycleTolerance &&
+		abs(a.Right()-b.Right()) <= cycleTolerance && abs(a.Bottom()-b.Bottom()) <= cycleTolerance
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,102 @@
+package geometry
+
+import (
+	"testing"
+
+	"hptools/internal/models"
+)
+
+func TestCycleTrackerOrder(t *testing.T) {
+	tracker := NewCycleTracker()
+	cycle := models.CycleLeft
+	rects := []models.Rect{
+		{X: 0, Y: 0, Width: 960, Height: 1040},
+		{X: 0, Y: 0, Width: 640, Height: 1040},
+		{X: 0, Y: 0, Width: 1280, Height: 1040},
+	}
+
+	// The window starts elsewhere, then each step leaves it where it was put
+	rect := models.Rect{X: 300, Y: 200, Width: 800, Height: 600}
+	for _, want := range []int{0, 1, 2, 0, 1} {
+		got := tracker.Next(7, cycle, rect)
+		if got != want {
+			t.Fatalf("Next() = %d, want %d", got, want)
+		}
+		rect = rects[got]
+		tracker.Record(7, cycle, got, rect)
+	}
+}
+
+func TestCycleTrackerReset(t *testing.T) {
+	applied := models.Rect{X: 0, Y: 0, Width: 960, Height: 1040}
+
+	tests := []struct {
+		name   string
+		window uintptr
+		cycle  []models.Placement
+		rect   models.Rect
+		want   int
+	}{
+		{
+			name:   "untouched window advances",
+			window: 7,
+			cycle:  models.CycleLeft,
+			rect:   applied,
+			want:   1,
+		},
+		{
+			name:   "adjusted within tolerance advances",
+			window: 7,
+			cycle:  models.CycleLeft,
+			rect:   models.Rect{X: 16, Y: 0, Width: 944, Height: 1056},
+			want:   1,
+		},
+		{
+			name:   "moved beyond tolerance starts over",
+			window: 7,
+			cycle:  models.CycleLeft,
+			rect:   models.Rect{X: 17, Y: 0, Width: 960, Height: 1040},
+			want:   0,
+		},
+		{
+			name:   "another window starts over",
+			window: 8,
+			cycle:  models.CycleLeft,
+			rect:   applied,
+			want:   0,
+		},
+		{
+			name:   "another cycle starts over",
+			window: 7,
+			cycle:  models.CycleRight,
+			rect:   applied,
+			want:   0,
+		},
+		{
+			name:   "empty cycle",
+			window: 7,
+			rect:   applied,
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewCycleTracker()
+			tracker.Record(7, models.CycleLeft, 0, applied)
+			if got := tracker.Next(tt.window, tt.cycle, tt.rect); got != tt.want {
+				t.Errorf("Next() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("recorded cycle is copied", func(t *testing.T) {
+		cycle := []models.Placement{models.PlacementLeftHalf, models.PlacementRightHalf}
+		tracker := NewCycleTracker()
+		tracker.Record(7, cycle, 0, applied)
+		cycle[1] = models.PlacementCenter
+		if got := tracker.Next(7, cycle, applied); got != 0 {
+			t.Errorf("Next() after changing the cycle = %d, want 0", got)
+		}
+	})
+}
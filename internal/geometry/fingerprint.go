@@ -0,0 +1,34 @@
+package geometry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"hptools/internal/models"
+)
+
+// Fingerprint identifies a monitor setup by the resolution and position of
+// every monitor, e.g. "1920x1080@0,0*|2560x1440@1920,0" where * marks the
+// primary monitor. Monitor names are left out because Windows renumbers
+// them when displays are reconnected.
+func Fingerprint(monitors []models.MonitorInfo) string {
+	sorted := append([]models.MonitorInfo(nil), monitors...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i].Bounds, sorted[j].Bounds
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		return a.Y < b.Y
+	})
+
+	parts := make([]string, len(sorted))
+	for i, m := range sorted {
+		b := m.Bounds
+		parts[i] = fmt.Sprintf("%dx%d@%d,%d", b.Width, b.Height, b.X, b.Y)
+		if m.Primary {
+			parts[i] += "*"
+		}
+	}
+	return strings.Join(parts, "|")
+}
@@ -0,0 +1,116 @@
+// Package geometry contains platform-independent window geometry calculations
+package geometry
+
+import (
+	"fmt"
+
+	"hptools/internal/models"
+)
+
+// FindMonitor returns the index of the monitor with the given handle, or -1
+func FindMonitor(monitors []models.MonitorInfo, handle uintptr) int {
+	for i, m := range monitors {
+		if m.Handle == handle {
+			return i
+		}
+	}
+	return -1
+}
+
+// Place calculates the target rect for a placement.
+// current is the window rect and monitorIdx the index of the monitor it is on.
+func Place(placement models.Placement, current models.Rect, monitors []models.MonitorInfo, monitorIdx int) (models.Rect, error) {
+	if monitorIdx < 0 || monitorIdx >= len(monitors) {
+		return models.Rect{}, fmt.Errorf("monitor index %d out of range", monitorIdx)
+	}
+	work := monitors[monitorIdx].WorkArea
+
+	switch placement {
+	case models.PlacementLeftHalf:
+		return models.Rect{X: work.X, Y: work.Y, Width: work.Width / 2, Height: work.Height}, nil
+
+	case models.PlacementRightHalf:
+		return rightPart(work, work.Width/2), nil
+
+	case models.PlacementLeftThird:
+		return models.Rect{X: work.X, Y: work.Y, Width: work.Width / 3, Height: work.Height}, nil
+
+	case models.PlacementLeftTwoThirds:
+		return models.Rect{X: work.X, Y: work.Y, Width: work.Width * 2 / 3, Height: work.Height}, nil
+
+	case models.PlacementRightThird:
+		return rightPart(work, work.Width*2/3), nil
+
+	case models.PlacementRightTwoThirds:
+		return rightPart(work, work.Width/3), nil
+
+	case models.PlacementCenter:
+		width := min(current.Width, work.Width)
+		height := min(current.Height, work.Height)
+		return models.Rect{
+			X:      work.X + (work.Width-width)/2,
+			Y:      work.Y + (work.Height-height)/2,
+			Width:  width,
+			Height: height,
+		}, nil
+
+	case models.PlacementNextMonitor:
+		target := monitors[(monitorIdx+1)%len(monitors)].WorkArea
+		return Translate(current, work, target), nil
+
+	case models.PlacementPreviousMonitor:
+		target := monitors[(monitorIdx+len(monitors)-1)%len(monitors)].WorkArea
+		return Translate(current, work, target), nil
+	}
+
+	return models.Rect{}, fmt.Errorf("unknown placement %q", placement)
+}
+
+// rightPart returns the part of work to the right of offset
+func rightPart(work models.Rect, offset int) models.Rect {
+	return models.Rect{X: work.X + offset, Y: work.Y, Width: work.Width - offset, Height: work.Height}
+}
+
+// Translate moves rect from one work area to another, keeping its relative
+// offset and its size relative to the work area
+func Translate(rect, from, to models.Rect) models.Rect {
+	width, height := rect.Width, rect.Height
+	if from.Width > 0 && from.Height > 0 {
+		width = rect.Width * to.Width / from.Width
+		height = rect.Height * to.Height / from.Height
+	}
+	width = max(1, min(width, to.Width))
+	height = max(1, min(height, to.Height))
+
+	x := to.X
+	if from.Width > 0 {
+		x += (rect.X - from.X) * to.Width / from.Width
+	}
+	y := to.Y
+	if from.Height > 0 {
+		y += (rect.Y - from.Y) * to.Height / from.Height
+	}
+
+	// Keep the window inside the target work area
+	x = max(to.X, min(x, to.Right()-width))
+	y = max(to.Y, min(y, to.Bottom()-height))
+
+	return models.Rect{X: x, Y: y, Width: width, Height: height}
+}
+
+// MonitorAt returns the index of the monitor containing the center of rect,
+// falling back to the nearest monitor. It returns -1 if there are no monitors.
+func MonitorAt(monitors []models.MonitorInfo, rect models.Rect) int {
+	cx, cy := rect.X+rect.Width/2, rect.Y+rect.Height/2
+
+	best, bestDist := -1, 0
+	for i, m := range monitors {
+		b := m.Bounds
+		dx := max(b.X-cx, 0, cx-b.Right())
+		dy := max(b.Y-cy, 0, cy-b.Bottom())
+		if dist := dx*dx + dy*dy; best < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
@@ -0,0 +1,261 @@
+package geometry
+
+import (
+	"testing"
+
+	"hptools/internal/models"
+)
+
+// testMonitors are a primary monitor, a smaller one to its right and one to
+// its left at negative coordinates, in that order
+var testMonitors = []models.MonitorInfo{
+	{
+		Handle:   1,
+		Primary:  true,
+		Bounds:   models.Rect{X: 0, Y: 0, Width: 1920, Height: 1080},
+		WorkArea: models.Rect{X: 0, Y: 0, Width: 1920, Height: 1040},
+	},
+	{
+		Handle:   2,
+		Bounds:   models.Rect{X: 1920, Y: 0, Width: 1000, Height: 800},
+		WorkArea: models.Rect{X: 1920, Y: 0, Width: 1000, Height: 800},
+	},
+	{
+		Handle:   3,
+		Bounds:   models.Rect{X: -1280, Y: -200, Width: 1280, Height: 1024},
+		WorkArea: models.Rect{X: -1280, Y: -200, Width: 1280, Height: 1024},
+	},
+}
+
+func TestPlace(t *testing.T) {
+	current := models.Rect{X: 2000, Y: 100, Width: 400, Height: 300}
+
+	tests := []struct {
+		name      string
+		placement models.Placement
+		current   models.Rect
+		monitor   int
+		want      models.Rect
+		wantErr   bool
+	}{
+		{
+			name:      "left half",
+			placement: models.PlacementLeftHalf,
+			current:   current,
+			monitor:   1,
+			want:      models.Rect{X: 1920, Y: 0, Width: 500, Height: 800},
+		},
+		{
+			name:      "right half",
+			placement: models.PlacementRightHalf,
+			current:   current,
+			monitor:   1,
+			want:      models.Rect{X: 2420, Y: 0, Width: 500, Height: 800},
+		},
+		{
+			name:      "left third rounds down",
+			placement: models.PlacementLeftThird,
+			current:   current,
+			monitor:   1,
+			want:      models.Rect{X: 1920, Y: 0, Width: 333, Height: 800},
+		},
+		{
+			name:      "left two thirds rounds down",
+			placement: models.PlacementLeftTwoThirds,
+			current:   current,
+			monitor:   1,
+			want:      models.Rect{X: 1920, Y: 0, Width: 666, Height: 800},
+		},
+		{
+			name:      "right third reaches the right edge",
+			placement: models.PlacementRightThird,
+			current:   current,
+			monitor:   1,
+			want:      models.Rect{X: 2586, Y: 0, Width: 334, Height: 800},
+		},
+		{
+			name:      "right two thirds reaches the right edge",
+			placement: models.PlacementRightTwoThirds,
+			current:   current,
+			monitor:   1,
+			want:      models.Rect{X: 2253, Y: 0, Width: 667, Height: 800},
+		},
+		{
+			name:      "center",
+			placement: models.PlacementCenter,
+			current:   current,
+			monitor:   1,
+			want:      models.Rect{X: 2220, Y: 250, Width: 400, Height: 300},
+		},
+		{
+			name:      "center shrinks to the work area",
+			placement: models.PlacementCenter,
+			current:   models.Rect{X: 2000, Y: 100, Width: 2000, Height: 1000},
+			monitor:   1,
+			want:      models.Rect{X: 1920, Y: 0, Width: 1000, Height: 800},
+		},
+		{
+			name:      "left half on a negative-coordinate monitor",
+			placement: models.PlacementLeftHalf,
+			current:   models.Rect{X: -1000, Y: 0, Width: 400, Height: 300},
+			monitor:   2,
+			want:      models.Rect{X: -1280, Y: -200, Width: 640, Height: 1024},
+		},
+		{
+			name:      "next monitor",
+			placement: models.PlacementNextMonitor,
+			current:   models.Rect{X: 0, Y: 0, Width: 960, Height: 520},
+			monitor:   0,
+			want:      models.Rect{X: 1920, Y: 0, Width: 500, Height: 400},
+		},
+		{
+			name:      "next monitor wraps to the first",
+			placement: models.PlacementNextMonitor,
+			current:   models.Rect{X: -1280, Y: -200, Width: 640, Height: 512},
+			monitor:   2,
+			want:      models.Rect{X: 0, Y: 0, Width: 960, Height: 520},
+		},
+		{
+			name:      "previous monitor wraps to the last",
+			placement: models.PlacementPreviousMonitor,
+			current:   models.Rect{X: 960, Y: 520, Width: 960, Height: 520},
+			monitor:   0,
+			want:      models.Rect{X: -640, Y: 312, Width: 640, Height: 512},
+		},
+		{
+			name:      "monitor out of range",
+			placement: models.PlacementLeftHalf,
+			current:   current,
+			monitor:   3,
+			wantErr:   true,
+		},
+		{
+			name:      "unknown placement",
+			placement: "top-half",
+			current:   current,
+			monitor:   0,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Place(tt.placement, tt.current, testMonitors, tt.monitor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Place() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Place() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("next monitor with a single monitor stays", func(t *testing.T) {
+		rect := models.Rect{X: 100, Y: 100, Width: 400, Height: 300}
+		got, err := Place(models.PlacementNextMonitor, rect, testMonitors[:1], 0)
+		if err != nil || got != rect {
+			t.Errorf("Place() = %+v, %v; want %+v", got, err, rect)
+		}
+	})
+}
+
+func TestTranslate(t *testing.T) {
+	primary, right := testMonitors[0].WorkArea, testMonitors[1].WorkArea
+
+	tests := []struct {
+		name     string
+		rect     models.Rect
+		from, to models.Rect
+		want     models.Rect
+	}{
+		{
+			name: "keeps relative position and size",
+			rect: models.Rect{X: 0, Y: 0, Width: 960, Height: 520},
+			from: primary,
+			to:   right,
+			want: models.Rect{X: 1920, Y: 0, Width: 500, Height: 400},
+		},
+		{
+			name: "keeps the window inside the target",
+			rect: models.Rect{X: 1800, Y: 0, Width: 400, Height: 300},
+			from: primary,
+			to:   right,
+			want: models.Rect{X: 2712, Y: 0, Width: 208, Height: 230},
+		},
+		{
+			name: "size is at least 1",
+			rect: models.Rect{X: 0, Y: 0, Width: 1, Height: 1},
+			from: primary,
+			to:   right,
+			want: models.Rect{X: 1920, Y: 0, Width: 1, Height: 1},
+		},
+		{
+			name: "empty source keeps the size",
+			rect: models.Rect{X: 50, Y: 50, Width: 400, Height: 300},
+			from: models.Rect{},
+			to:   primary,
+			want: models.Rect{X: 0, Y: 0, Width: 400, Height: 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Translate(tt.rect, tt.from, tt.to); got != tt.want {
+				t.Errorf("Translate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitorAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		rect     models.Rect
+		monitors []models.MonitorInfo
+		want     int
+	}{
+		{
+			name:     "center on the primary monitor",
+			rect:     models.Rect{X: 100, Y: 100, Width: 400, Height: 300},
+			monitors: testMonitors,
+			want:     0,
+		},
+		{
+			name:     "center on a negative-coordinate monitor",
+			rect:     models.Rect{X: -900, Y: -150, Width: 400, Height: 300},
+			monitors: testMonitors,
+			want:     2,
+		},
+		{
+			name:     "mostly on another monitor",
+			rect:     models.Rect{X: 1800, Y: 100, Width: 400, Height: 300},
+			monitors: testMonitors,
+			want:     1,
+		},
+		{
+			name:     "center off screen goes to the nearest monitor",
+			rect:     models.Rect{X: 2300, Y: 850, Width: 200, Height: 100},
+			monitors: testMonitors,
+			want:     1,
+		},
+		{
+			name:     "center on a shared edge goes to the first monitor",
+			rect:     models.Rect{X: 1820, Y: 100, Width: 200, Height: 100},
+			monitors: testMonitors,
+			want:     0,
+		},
+		{
+			name: "no monitors",
+			rect: models.Rect{X: 100, Y: 100, Width: 400, Height: 300},
+			want: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MonitorAt(tt.monitors, tt.rect); got != tt.want {
+				t.Errorf("MonitorAt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package geometry
+
+import "hptools/internal/models"
+
+// SnapMove shifts rect so that its closest edge within threshold lines up
+// with an edge of a target: the inside edges of the target or the outside
+// edges that place rect flush against it. Each axis snaps independently and
+// only to targets that overlap rect on the other axis.
+func SnapMove(rect models.Rect, targets []models.Rect, threshold int) models.Rect {
+	if threshold <= 0 {
+		return rect
+	}
+
+	dx, dy := threshold+1, threshold+1
+	for _, t := range targets {
+		if spans(rect.Y, rect.Bottom(), t.Y, t.Bottom(), threshold) {
+			for _, x := range []int{t.X, t.Right() - rect.Width, t.Right(), t.X - rect.Width} {
+				if d := x - rect.X; abs(d) < abs(dx) {
+					dx = d
+				}
+			}
+		}
+		if spans(rect.X, rect.Right(), t.X, t.Right(), threshold) {
+			for _, y := range []int{t.Y, t.Bottom() - rect.Height, t.Bottom(), t.Y - rect.Height} {
+				if d := y - rect.Y; abs(d) < abs(dy) {
+					dy = d
+				}
+			}
+		}
+	}
+
+	if abs(dx) <= threshold {
+		rect.X += dx
+	}
+	if abs(dy) <= threshold {
+		rect.Y += dy
+	}
+	return rect
+}
+
+// SnapResize moves the edges of rect that anchor does not hold in place to
+// target edges within threshold. The center anchor holds no edge still, so
+// all four edges snap.
+func SnapResize(rect models.Rect, anchor models.Anchor, targets []models.Rect, threshold int) (models.Rect, error) {
+	fx, fy, err := anchorFactors(anchor)
+	if err != nil {
+		return models.Rect{}, err
+	}
+	if threshold <= 0 {
+		return rect, nil
+	}
+
+	left, top, right, bottom := rect.X, rect.Y, rect.Right(), rect.Bottom()
+	var xs, ys []int
+	for _, t := range targets {
+		if spans(top, bottom, t.Y, t.Bottom(), threshold) {
+			xs = append(xs, t.X, t.Right())
+		}
+		if spans(left, right, t.X, t.Right(), threshold) {
+			ys = append(ys, t.Y, t.Bottom())
+		}
+	}
+
+	if fx != 0 {
+		left = snapValue(left, xs, threshold)
+	}
+	if fx != 2 {
+		right = snapValue(right, xs, threshold)
+	}
+	if fy != 0 {
+		top = snapValue(top, ys, threshold)
+	}
+	if fy != 2 {
+		bottom = snapValue(bottom, ys, threshold)
+	}
+
+	if right-left < 1 || bottom-top < 1 {
+		return rect, nil
+	}
+	return models.Rect{X: left, Y: top, Width: right - left, Height: bottom - top}, nil
+}
+
+// snapValue returns the candidate closest to v within threshold, or v
+func snapValue(v int, candidates []int, threshold int) int {
+	best, bestDist := v, threshold+1
+	for _, c := range candidates {
+		if d := abs(c - v); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// spans reports whether [a1, a2) and [b1, b2) overlap or come within threshold
+func spans(a1, a2, b1, b2, threshold int) bool {
+	return a1 < b2+threshold && b1 < a2+threshold
+}
+
+// SnapEnv holds what a window rect snaps to: the monitors and the visible
+// frames of the other windows
+type SnapEnv struct {
+	Monitors []models.MonitorInfo
+	Windows  []models.Rect
+}
+
+// targets returns every rect whose edges attract: monitor bounds, work areas
+// and windows
+func (e SnapEnv) targets() []models.Rect {
+	targets := make([]models.Rect, 0, 2*len(e.Monitors)+len(e.Windows))
+	for _, m := range e.Monitors {
+		targets = append(targets, m.Bounds, m.WorkArea)
+	}
+	return append(targets, e.Windows...)
+}
+
+// Snap aligns a moved rect with nearby edges and then resolves overlaps with
+// the other windows according to the overlap policy
+func Snap(rect models.Rect, env SnapEnv, options models.SnapOptions) models.Rect {
+	rect = SnapMove(rect, env.targets(), options.Threshold)
+	return ResolveOverlap(rect, env, options.Overlap)
+}
+
+// SnapResized aligns the edges of a rect resized around anchor with nearby
+// edges and then resolves overlaps. Moving a resized rect would undo the
+// resize, so the avoid policy shrinks it instead.
+func SnapResized(rect models.Rect, anchor models.Anchor, env SnapEnv, options models.SnapOptions) (models.Rect, error) {
+	rect, err := SnapResize(rect, anchor, env.targets(), options.Threshold)
+	if err != nil {
+		return models.Rect{}, err
+	}
+	policy := options.Overlap
+	if policy == models.OverlapAvoid {
+		policy = models.OverlapShrink
+	}
+	return ResolveOverlap(rect, env, policy), nil
+}
+
+// ResolveOverlap applies an overlap policy to a rect that may cover some of
+// env.Windows. A rect that cannot be resolved is returned unchanged.
+func ResolveOverlap(rect models.Rect, env SnapEnv, policy models.OverlapPolicy) models.Rect {
+	switch policy {
+	case models.OverlapAvoid:
+		return avoidOverlap(rect, env)
+	case models.OverlapShrink:
+		return shrinkOverlap(rect, env.Windows)
+	}
+	return rect
+}
+
+// avoidOverlap moves rect next to one of the windows, choosing the closest
+// position that overlaps no window and stays within the work area
+func avoidOverlap(rect models.Rect, env SnapEnv) models.Rect {
+	if !overlapsAny(rect, env.Windows) {
+		return rect
+	}
+
+	idx := MonitorAt(env.Monitors, rect)
+	if idx < 0 {
+		return rect
+	}
+	work := env.Monitors[idx].WorkArea
+
+	best, bestDist := rect, -1
+	for _, w := range env.Windows {
+		for _, c := range []models.Rect{
+			{X: w.X - rect.Width, Y: rect.Y, Width: rect.Width, Height: rect.Height},
+			{X: w.Right(), Y: rect.Y, Width: rect.Width, Height: rect.Height},
+			{X: rect.X, Y: w.Y - rect.Height, Width: rect.Width, Height: rect.Height},
+			{X: rect.X, Y: w.Bottom(), Width: rect.Width, Height: rect.Height},
+		} {
+			if !Contains(work, c) || overlapsAny(c, env.Windows) {
+				continue
+			}
+			if d := abs(c.X-rect.X) + abs(c.Y-rect.Y); bestDist < 0 || d < bestDist {
+				best, bestDist = c, d
+			}
+		}
+	}
+	return best
+}
+
+// shrinkOverlap cuts rect back from each window it overlaps, keeping the
+// largest remaining part. Windows that cover rect completely are ignored.
+func shrinkOverlap(rect models.Rect, windows []models.Rect) models.Rect {
+	for _, w := range windows {
+		if Intersect(rect, w).Width == 0 {
+			continue
+		}
+
+		var cuts []models.Rect
+		if w.X > rect.X {
+			cuts = append(cuts, models.Rect{X: rect.X, Y: rect.Y, Width: w.X - rect.X, Height: rect.Height})
+		}
+		if w.Right() < rect.Right() {
+			cuts = append(cuts, models.Rect{X: w.Right(), Y: rect.Y, Width: rect.Right() - w.Right(), Height: rect.Height})
+		}
+		if w.Y > rect.Y {
+			cuts = append(cuts, models.Rect{X: rect.X, Y: rect.Y, Width: rect.Width, Height: w.Y - rect.Y})
+		}
+		if w.Bottom() < rect.Bottom() {
+			cuts = append(cuts, models.Rect{X: rect.X, Y: w.Bottom(), Width: rect.Width, Height: rect.Bottom() - w.Bottom()})
+		}
+
+		best := -1
+		for i, c := range cuts {
+			if best < 0 || c.Width*c.Height > cuts[best].Width*cuts[best].Height {
+				best = i
+			}
+		}
+		if best >= 0 {
+			rect = cuts[best]
+		}
+	}
+	return rect
+}
+
+// overlapsAny reports whether rect overlaps any of the windows
+func overlapsAny(rect models.Rect, windows []models.Rect) bool {
+	for _, w := range windows {
+		if Intersect(rect, w).Width > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package geometry
+
+import (
+	"testing"
+
+	"hptools/internal/models"
+)
+
+func TestSnapMove(t *testing.T) {
+	monitor := models.Rect{X: 0, Y: 0, Width: 1000, Height: 1000}
+	small := models.Rect{X: 0, Y: 0, Width: 100, Height: 100}
+
+	tests := []struct {
+		name      string
+		rect      models.Rect
+		targets   []models.Rect
+		threshold int
+		want      models.Rect
+	}{
+		{
+			name:      "threshold 0 disables snapping",
+			rect:      models.Rect{X: 5, Y: 300, Width: 200, Height: 100},
+			targets:   []models.Rect{monitor},
+			threshold: 0,
+			want:      models.Rect{X: 5, Y: 300, Width: 200, Height: 100},
+		},
+		{
+			name:      "edge at threshold snaps",
+			rect:      models.Rect{X: 10, Y: 300, Width: 200, Height: 100},
+			targets:   []models.Rect{monitor},
+			threshold: 10,
+			want:      models.Rect{X: 0, Y: 300, Width: 200, Height: 100},
+		},
+		{
+			name:      "edge past threshold stays",
+			rect:      models.Rect{X: 11, Y: 300, Width: 200, Height: 100},
+			targets:   []models.Rect{monitor},
+			threshold: 10,
+			want:      models.Rect{X: 11, Y: 300, Width: 200, Height: 100},
+		},
+		{
+			name:      "inside right edge",
+			rect:      models.Rect{X: 807, Y: 300, Width: 200, Height: 100},
+			targets:   []models.Rect{monitor},
+			threshold: 10,
+			want:      models.Rect{X: 800, Y: 300, Width: 200, Height: 100},
+		},
+		{
+			name:      "outside right edge",
+			rect:      models.Rect{X: 1005, Y: 300, Width: 200, Height: 100},
+			targets:   []models.Rect{monitor},
+			threshold: 10,
+			want:      models.Rect{X: 1000, Y: 300, Width: 200, Height: 100},
+		},
+		{
+			name:      "outside top edge",
+			rect:      models.Rect{X: 300, Y: -94, Width: 200, Height: 100},
+			targets:   []models.Rect{monitor},
+			threshold: 10,
+			want:      models.Rect{X: 300, Y: -100, Width: 200, Height: 100},
+		},
+		{
+			name:      "both axes snap",
+			rect:      models.Rect{X: -4, Y: 895, Width: 200, Height: 100},
+			targets:   []models.Rect{monitor},
+			threshold: 10,
+			want:      models.Rect{X: 0, Y: 900, Width: 200, Height: 100},
+		},
+		{
+			name:      "closest target wins",
+			rect:      models.Rect{X: 103, Y: 50, Width: 200, Height: 100},
+			targets:   []models.Rect{monitor, small},
+			threshold: 10,
+			want:      models.Rect{X: 100, Y: 50, Width: 200, Height: 100},
+		},
+		{
+			name:      "x does not snap without vertical overlap",
+			rect:      models.Rect{X: 105, Y: 500, Width: 100, Height: 100},
+			targets:   []models.Rect{small},
+			threshold: 10,
+			want:      models.Rect{X: 105, Y: 500, Width: 100, Height: 100},
+		},
+		{
+			name:      "x snaps with vertical overlap",
+			rect:      models.Rect{X: 105, Y: 50, Width: 100, Height: 100},
+			targets:   []models.Rect{small},
+			threshold: 10,
+			want:      models.Rect{X: 100, Y: 50, Width: 100, Height: 100},
+		},
+		{
+			name:      "y does not snap without horizontal overlap",
+			rect:      models.Rect{X: 500, Y: 105, Width: 100, Height: 100},
+			targets:   []models.Rect{small},
+			threshold: 10,
+			want:      models.Rect{X: 500, Y: 105, Width: 100, Height: 100},
+		},
+		{
+			name:      "gap within threshold counts as overlap",
+			rect:      models.Rect{X: 50, Y: 108, Width: 100, Height: 100},
+			targets:   []models.Rect{small},
+			threshold: 10,
+			want:      models.Rect{X: 50, Y: 100, Width: 100, Height: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SnapMove(tt.rect, tt.targets, tt.threshold); got != tt.want {
+				t.Errorf("SnapMove() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapResize(t *testing.T) {
+	monitor := []models.Rect{{X: 0, Y: 0, Width: 1000, Height: 1000}}
+	rect := models.Rect{X: 5, Y: 5, Width: 990, Height: 990}
+
+	tests := []struct {
+		name      string
+		anchor    models.Anchor
+		threshold int
+		want      models.Rect
+	}{
+		{"threshold 0 disables snapping", models.AnchorCenter, 0, rect},
+		{"top-left keeps left and top", models.AnchorTopLeft, 10, models.Rect{X: 5, Y: 5, Width: 995, Height: 995}},
+		{"default anchor is top-left", "", 10, models.Rect{X: 5, Y: 5, Width: 995, Height: 995}},
+		{"bottom-right keeps right and bottom", models.AnchorBottomRight, 10, models.Rect{X: 0, Y: 0, Width: 995, Height: 995}},
+		{"left keeps only the left edge", models.AnchorLeft, 10, models.Rect{X: 5, Y: 0, Width: 995, Height: 1000}},
+		{"top keeps only the top edge", models.AnchorTop, 10, models.Rect{X: 0, Y: 5, Width: 1000, Height: 995}},
+		{"center snaps every edge", models.AnchorCenter, 10, models.Rect{X: 0, Y: 0, Width: 1000, Height: 1000}},
+		{"edges past threshold stay", models.AnchorCenter, 4, rect},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SnapResize(rect, tt.anchor, monitor, tt.threshold)
+			if err != nil {
+				t.Fatalf("SnapResize: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SnapResize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SnapResize(rect, "middle", monitor, 10); err == nil {
+		t.Error("SnapResize with an unknown anchor succeeded")
+	}
+}
+
+func TestResolveOverlap(t *testing.T) {
+	work := models.Rect{X: 0, Y: 0, Width: 1000, Height: 1000}
+	monitors := []models.MonitorInfo{{Bounds: work, WorkArea: work}}
+
+	tests := []struct {
+		name    string
+		rect    models.Rect
+		windows []models.Rect
+		policy  models.OverlapPolicy
+		want    models.Rect
+	}{
+		{
+			name:    "allow keeps the overlap",
+			rect:    models.Rect{X: 400, Y: 100, Width: 300, Height: 300},
+			windows: []models.Rect{{X: 0, Y: 0, Width: 500, Height: 500}},
+			policy:  models.OverlapAllow,
+			want:    models.Rect{X: 400, Y: 100, Width: 300, Height: 300},
+		},
+		{
+			name:    "avoid moves to the closest free side",
+			rect:    models.Rect{X: 400, Y: 100, Width: 300, Height: 300},
+			windows: []models.Rect{{X: 0, Y: 0, Width: 500, Height: 500}},
+			policy:  models.OverlapAvoid,
+			want:    models.Rect{X: 500, Y: 100, Width: 300, Height: 300},
+		},
+		{
+			name: "avoid without a free slot keeps the rect",
+			rect: models.Rect{X: 100, Y: 100, Width: 300, Height: 300},
+			windows: []models.Rect{
+				{X: 0, Y: 0, Width: 600, Height: 1000},
+				{X: 600, Y: 0, Width: 400, Height: 1000},
+			},
+			policy: models.OverlapAvoid,
+			want:   models.Rect{X: 100, Y: 100, Width: 300, Height: 300},
+		},
+		{
+			name:    "avoid leaves a free rect alone",
+			rect:    models.Rect{X: 600, Y: 600, Width: 300, Height: 300},
+			windows: []models.Rect{{X: 0, Y: 0, Width: 500, Height: 500}},
+			policy:  models.OverlapAvoid,
+			want:    models.Rect{X: 600, Y: 600, Width: 300, Height: 300},
+		},
+		{
+			name:    "shrink keeps the largest part",
+			rect:    models.Rect{X: 0, Y: 0, Width: 400, Height: 400},
+			windows: []models.Rect{{X: 300, Y: 0, Width: 300, Height: 400}},
+			policy:  models.OverlapShrink,
+			want:    models.Rect{X: 0, Y: 0, Width: 300, Height: 400},
+		},
+		{
+			name:    "shrink ignores a window covering the rect",
+			rect:    models.Rect{X: 100, Y: 100, Width: 200, Height: 200},
+			windows: []models.Rect{{X: 0, Y: 0, Width: 1000, Height: 1000}},
+			policy:  models.OverlapShrink,
+			want:    models.Rect{X: 100, Y: 100, Width: 200, Height: 200},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := SnapEnv{Monitors: monitors, Windows: tt.windows}
+			if got := ResolveOverlap(tt.rect, env, tt.policy); got != tt.want {
+				t.Errorf("ResolveOverlap() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapResizedShrinksInsteadOfMoving(t *testing.T) {
+	work := models.Rect{X: 0, Y: 0, Width: 1000, Height: 1000}
+	env := SnapEnv{
+		Monitors: []models.MonitorInfo{{Bounds: work, WorkArea: work}},
+		Windows:  []models.Rect{{X: 300, Y: 0, Width: 300, Height: 400}},
+	}
+	options := models.SnapOptions{Overlap: models.OverlapAvoid}
+
+	got, err := SnapResized(models.Rect{X: 0, Y: 0, Width: 400, Height: 400}, models.AnchorTopLeft, env, options)
+	if err != nil {
+		t.Fatalf("SnapResized: %v", err)
+	}
+	if want := (models.Rect{X: 0, Y: 0, Width: 300, Height: 400}); got != want {
+		t.Errorf("SnapResized() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+
+	"hptools/internal/models"
+)
+
+const (
+	// MinMasterRatio and MaxMasterRatio bound the master window's share
+	MinMasterRatio = 0.1
+	MaxMasterRatio = 0.9
+)
+
+// Tile returns the rects of n windows tiled in area. gap is kept around the
+// edge of area and between tiles. Rects are in window order; in the
+// master-stack layout the first window is the master.
+func Tile(layout models.TilingLayout, area models.Rect, n int, masterRatio float64, gap int) ([]models.Rect, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	inner := models.Rect{X: area.X + gap, Y: area.Y + gap, Width: area.Width - 2*gap, Height: area.Height - 2*gap}
+	if inner.Width < 1 || inner.Height < 1 {
+		return nil, fmt.Errorf("gap %d leaves no room in %dx%d", gap, area.Width, area.Height)
+	}
+
+	switch layout {
+	case models.TilingMasterStack, "":
+		return masterStack(inner, n, masterRatio, gap)
+	case models.TilingColumns:
+		return columns(inner, n, gap)
+	case models.TilingGrid:
+		return grid(inner, n, gap)
+	case models.TilingMonocle:
+		rects := make([]models.Rect, n)
+		for i := range rects {
+			rects[i] = inner
+		}
+		return rects, nil
+	}
+	return nil, fmt.Errorf("unknown tiling layout %q", layout)
+}
+
+// masterStack puts the first window on the left and stacks the rest on the right
+func masterStack(area models.Rect, n int, ratio float64, gap int) ([]models.Rect, error) {
+	if n == 1 {
+		return []models.Rect{area}, nil
+	}
+
+	ratio = min(max(ratio, MinMasterRatio), MaxMasterRatio)
+	masterWidth := int(math.Round(float64(area.Width-gap) * ratio))
+	stack := models.Rect{X: area.X + masterWidth + gap, Y: area.Y, Width: area.Width - masterWidth - gap, Height: area.Height}
+	if masterWidth < 1 || stack.Width < 1 {
+		return nil, fmt.Errorf("width %d leaves no room for master and stack", area.Width)
+	}
+	parts, err := split(stack.Height, n-1, gap)
+	if err != nil {
+		return nil, err
+	}
+
+	rects := []models.Rect{{X: area.X, Y: area.Y, Width: masterWidth, Height: area.Height}}
+	for _, s := range parts {
+		rects = append(rects, models.Rect{X: stack.X, Y: stack.Y + s[0], Width: stack.Width, Height: s[1]})
+	}
+	return rects, nil
+}
+
+// columns splits area into n equal columns
+func columns(area models.Rect, n int, gap int) ([]models.Rect, error) {
+	parts, err := split(area.Width, n, gap)
+	if err != nil {
+		return nil, err
+	}
+
+	rects := make([]models.Rect, 0, n)
+	for _, s := range parts {
+		rects = append(rects, models.Rect{X: area.X + s[0], Y: area.Y, Width: s[1], Height: area.Height})
+	}
+	return rects, nil
+}
+
+// grid arranges n windows in rows of near-square cells. When n is not a
+// multiple of the column count the last row has fewer, wider cells.
+func grid(area models.Rect, n int, gap int) ([]models.Rect, error) {
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	rows := (n + cols - 1) / cols
+
+	rowParts, err := split(area.Height, rows, gap)
+	if err != nil {
+		return nil, err
+	}
+	rects := make([]models.Rect, 0, n)
+	for r, row := range rowParts {
+		inRow := min(cols, n-r*cols)
+		colParts, err := split(area.Width, inRow, gap)
+		if err != nil {
+			return nil, err
+		}
+		for _, col := range colParts {
+			rects = append(rects, models.Rect{X: area.X + col[0], Y: area.Y + row[0], Width: col[1], Height: row[1]})
+		}
+	}
+	return rects, nil
+}
+
+// split divides length into count parts separated by gap and returns the
+// offset and size of each. Leftover pixels go to the first parts so the
+// parts always fill length exactly. It fails when a part would be empty.
+func split(length, count, gap int) ([][2]int, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("cannot split into %d parts", count)
+	}
+	available := length - gap*(count-1)
+	if available/count <= 0 {
+		return nil, fmt.Errorf("%d parts with gap %d do not fit in %d pixels", count, gap, length)
+	}
+	size, extra := available/count, available%count
+
+	parts := make([][2]int, count)
+	offset := 0
+	for i := range parts {
+		s := size
+		if i < extra {
+			s++
+		}
+		parts[i] = [2]int{offset, s}
+		offset += s + gap
+	}
+	return parts, nil
+}
@@ -0,0 +1,238 @@
+package geometry
+
+import (
+	"reflect"
+	"testing"
+
+	"hptools/internal/models"
+)
+
+func TestTile(t *testing.T) {
+	area := models.Rect{X: 0, Y: 0, Width: 1000, Height: 800}
+
+	tests := []struct {
+		name   string
+		layout models.TilingLayout
+		area   models.Rect
+		n      int
+		ratio  float64
+		gap    int
+		want   []models.Rect
+	}{
+		{
+			name:   "no windows",
+			layout: models.TilingMasterStack,
+			area:   area,
+			n:      0,
+			ratio:  0.5,
+			want:   nil,
+		},
+		{
+			name:   "one window fills the area inside the gap",
+			layout: models.TilingMasterStack,
+			area:   area,
+			n:      1,
+			ratio:  0.5,
+			gap:    10,
+			want:   []models.Rect{{X: 10, Y: 10, Width: 980, Height: 780}},
+		},
+		{
+			name:   "two windows split master and stack",
+			layout: models.TilingMasterStack,
+			area:   area,
+			n:      2,
+			ratio:  0.5,
+			gap:    10,
+			want: []models.Rect{
+				{X: 10, Y: 10, Width: 485, Height: 780},
+				{X: 505, Y: 10, Width: 485, Height: 780},
+			},
+		},
+		{
+			name:   "five windows stack four on the right",
+			layout: models.TilingMasterStack,
+			area:   models.Rect{X: 0, Y: 0, Width: 1000, Height: 1000},
+			n:      5,
+			ratio:  0.6,
+			want: []models.Rect{
+				{X: 0, Y: 0, Width: 600, Height: 1000},
+				{X: 600, Y: 0, Width: 400, Height: 250},
+				{X: 600, Y: 250, Width: 400, Height: 250},
+				{X: 600, Y: 500, Width: 400, Height: 250},
+				{X: 600, Y: 750, Width: 400, Height: 250},
+			},
+		},
+		{
+			name:   "empty layout is master-stack",
+			layout: "",
+			area:   area,
+			n:      2,
+			ratio:  0.5,
+			want: []models.Rect{
+				{X: 0, Y: 0, Width: 500, Height: 800},
+				{X: 500, Y: 0, Width: 500, Height: 800},
+			},
+		},
+		{
+			name:   "master ratio above the maximum is clamped",
+			layout: models.TilingMasterStack,
+			area:   area,
+			n:      2,
+			ratio:  0.95,
+			want: []models.Rect{
+				{X: 0, Y: 0, Width: 900, Height: 800},
+				{X: 900, Y: 0, Width: 100, Height: 800},
+			},
+		},
+		{
+			name:   "master ratio below the minimum is clamped",
+			layout: models.TilingMasterStack,
+			area:   area,
+			n:      2,
+			ratio:  0,
+			want: []models.Rect{
+				{X: 0, Y: 0, Width: 100, Height: 800},
+				{X: 100, Y: 0, Width: 900, Height: 800},
+			},
+		},
+		{
+			name:   "columns spread leftover pixels",
+			layout: models.TilingColumns,
+			area:   area,
+			n:      3,
+			want: []models.Rect{
+				{X: 0, Y: 0, Width: 334, Height: 800},
+				{X: 334, Y: 0, Width: 333, Height: 800},
+				{X: 667, Y: 0, Width: 333, Height: 800},
+			},
+		},
+		{
+			name:   "grid with a shorter last row",
+			layout: models.TilingGrid,
+			area:   models.Rect{X: 0, Y: 0, Width: 900, Height: 600},
+			n:      5,
+			want: []models.Rect{
+				{X: 0, Y: 0, Width: 300, Height: 300},
+				{X: 300, Y: 0, Width: 300, Height: 300},
+				{X: 600, Y: 0, Width: 300, Height: 300},
+				{X: 0, Y: 300, Width: 450, Height: 300},
+				{X: 450, Y: 300, Width: 450, Height: 300},
+			},
+		},
+		{
+			name:   "grid of two is one row",
+			layout: models.TilingGrid,
+			area:   area,
+			n:      2,
+			gap:    10,
+			want: []models.Rect{
+				{X: 10, Y: 10, Width: 485, Height: 780},
+				{X: 505, Y: 10, Width: 485, Height: 780},
+			},
+		},
+		{
+			name:   "monocle stacks every window on the area",
+			layout: models.TilingMonocle,
+			area:   area,
+			n:      2,
+			gap:    10,
+			want: []models.Rect{
+				{X: 10, Y: 10, Width: 980, Height: 780},
+				{X: 10, Y: 10, Width: 980, Height: 780},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Tile(tt.layout, tt.area, tt.n, tt.ratio, tt.gap)
+			if err != nil {
+				t.Fatalf("Tile: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTileErrors(t *testing.T) {
+	area := models.Rect{X: 0, Y: 0, Width: 1000, Height: 800}
+
+	tests := []struct {
+		name   string
+		layout models.TilingLayout
+		gap    int
+	}{
+		{"gap larger than the area", models.TilingColumns, 500},
+		{"gap leaving zero height", models.TilingMasterStack, 400},
+		{"gaps between columns leave no width", models.TilingColumns, 330},
+		{"gaps between stacked windows leave no height", models.TilingMasterStack, 270},
+		{"gaps between grid cells leave no width", models.TilingGrid, 330},
+		{"unknown layout", "spiral", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rects, err := Tile(tt.layout, area, 3, 0.5, tt.gap); err == nil {
+				t.Errorf("Tile() = %+v, want error", rects)
+			}
+		})
+	}
+
+	t.Run("more windows than pixels", func(t *testing.T) {
+		narrow := models.Rect{X: 0, Y: 0, Width: 4, Height: 800}
+		if rects, err := Tile(models.TilingColumns, narrow, 5, 0.5, 0); err == nil {
+			t.Errorf("Tile() = %+v, want error", rects)
+		}
+	})
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		length, count, gap int
+		want               [][2]int
+	}{
+		{100, 1, 10, [][2]int{{0, 100}}},
+		{100, 2, 0, [][2]int{{0, 50}, {50, 50}}},
+		{100, 3, 0, [][2]int{{0, 34}, {34, 33}, {67, 33}}},
+		{10, 4, 2, [][2]int{{0, 1}, {3, 1}, {6, 1}, {9, 1}}},
+		{103, 5, 5, [][2]int{{0, 17}, {22, 17}, {44, 17}, {66, 16}, {87, 16}}},
+	}
+
+	for _, tt := range tests {
+		got, err := split(tt.length, tt.count, tt.gap)
+		if err != nil {
+			t.Errorf("split(%d, %d, %d): %v", tt.length, tt.count, tt.gap, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("split(%d, %d, %d) = %v, want %v", tt.length, tt.count, tt.gap, got, tt.want)
+			continue
+		}
+		if last := got[len(got)-1]; last[0]+last[1] != tt.length {
+			t.Errorf("split(%d, %d, %d) ends at %d, want %d", tt.length, tt.count, tt.gap, last[0]+last[1], tt.length)
+		}
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	tests := []struct {
+		name               string
+		length, count, gap int
+	}{
+		{"no parts", 100, 0, 0},
+		{"negative count", 100, -1, 0},
+		{"fewer pixels than parts", 3, 4, 0},
+		{"gaps use up the length", 100, 3, 50},
+		{"negative length", -10, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := split(tt.length, tt.count, tt.gap); err == nil {
+				t.Errorf("split(%d, %d, %d) = %v, want error", tt.length, tt.count, tt.gap, got)
+			}
+		})
+	}
+}
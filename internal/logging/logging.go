@@ -11,6 +11,12 @@ import (
 
 // NewLogger creates a new structured logger based on configuration
 func NewLogger(cfg *config.Config) *slog.Logger {
+	return NewLoggerTo(cfg, os.Stdout)
+}
+
+// NewLoggerTo creates a logger like NewLogger that writes to writer, e.g. to
+// keep stdout free for command output
+func NewLoggerTo(cfg *config.Config, writer io.Writer) *slog.Logger {
 	var level slog.Level
 	switch strings.ToLower(cfg.Log.Level) {
 	case "debug":
@@ -26,7 +32,6 @@ func NewLogger(cfg *config.Config) *slog.Logger {
 	}
 
 	var handler slog.Handler
-	var writer io.Writer = os.Stdout
 
 	opts := &slog.HandlerOptions{
 		Level: level,
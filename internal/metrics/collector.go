@@ -0,0 +1,23 @@
+// Package metrics samples per-process resource usage and keeps a short history
+package metrics
+
+import "time"
+
+// Reading holds the raw counters of one process at one point in time
+type Reading struct {
+	PID       int
+	ImageName string
+	// StartTime tells a process apart from a later one that reuses its PID
+	StartTime    int64
+	CPUTime      time.Duration
+	WorkingSet   uint64
+	PrivateBytes uint64
+	Handles      uint32
+	GDIObjects   uint32
+	UserObjects  uint32
+}
+
+// Collector reads the counters of every process it can access
+type Collector interface {
+	Collect() ([]Reading, error)
+}
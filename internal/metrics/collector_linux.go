@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of CPU times in /proc. It is 100 on every
+// mainstream architecture and cannot be queried without cgo.
+const clockTicks = 100
+
+type procCollector struct {
+	root     string
+	pageSize uint64
+}
+
+// NewProcCollector creates a collector that reads process counters from
+// /proc. Private bytes are resident pages that are not shared, handles are
+// open file descriptors, and GUI object counts stay zero.
+func NewProcCollector() Collector {
+	return &procCollector{root: "/proc", pageSize: uint64(os.Getpagesize())}
+}
+
+// Collect reads the counters of all processes whose /proc entries are readable
+func (c *procCollector) Collect() ([]Reading, error) {
+	dirs, err := os.ReadDir(c.root)
+	if err != nil {
+		return nil, err
+	}
+
+	var readings []Reading
+	for _, dir := range dirs {
+		pid, err := strconv.Atoi(dir.Name())
+		if err != nil || !dir.IsDir() {
+			continue
+		}
+		if reading, ok := c.read(pid); ok {
+			readings = append(readings, reading)
+		}
+	}
+	return readings, nil
+}
+
+func (c *procCollector) read(pid int) (Reading, bool) {
+	dir := filepath.Join(c.root, strconv.Itoa(pid))
+
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return Reading{}, false
+	}
+	// The command name is in parentheses and may itself contain spaces
+	open, end := strings.IndexByte(string(stat), '('), strings.LastIndexByte(string(stat), ')')
+	if open < 0 || end < open {
+		return Reading{}, false
+	}
+	// fields[0] is field 3 (state) of proc(5)
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 20 {
+		return Reading{}, false
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	start, _ := strconv.ParseInt(fields[19], 10, 64)
+
+	reading := Reading{
+		PID:       pid,
+		ImageName: string(stat[open+1 : end]),
+		StartTime: start,
+		CPUTime:   time.Duration(utime+stime) * time.Second / clockTicks,
+	}
+
+	if statm, err := os.ReadFile(filepath.Join(dir, "statm")); err == nil {
+		if f := strings.Fields(string(statm)); len(f) >= 3 {
+			resident, _ := strconv.ParseUint(f[1], 10, 64)
+			shared, _ := strconv.ParseUint(f[2], 10, 64)
+			reading.WorkingSet = resident * c.pageSize
+			if resident > shared {
+				reading.PrivateBytes = (resident - shared) * c.pageSize
+			}
+		}
+	}
+
+	if fds, err := os.ReadDir(filepath.Join(dir, "fd")); err == nil {
+		reading.Handles = uint32(len(fds))
+	}
+
+	return reading, true
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"syscall"
+
+	"hptools/internal/windows"
+)
+
+type windowsCollector struct {
+	api *windows.API
+}
+
+// NewWindowsCollector creates a collector that reads process counters through
+// the Win32 API. Processes that cannot be opened, such as protected system
+// processes, are skipped.
+func NewWindowsCollector(api *windows.API) Collector {
+	return &windowsCollector{api: api}
+}
+
+// Collect reads the counters of all accessible processes
+func (c *windowsCollector) Collect() ([]Reading, error) {
+	entries, err := c.api.GetProcessEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	readings := make([]Reading, 0, len(entries))
+	for pid, entry := range entries {
+		if reading, ok := c.read(pid, entry.ExeFile); ok {
+			readings = append(readings, reading)
+		}
+	}
+	return readings, nil
+}
+
+func (c *windowsCollector) read(pid int, imageName string) (Reading, bool) {
+	h, err := c.api.OpenProcess(pid)
+	if err != nil {
+		return Reading{}, false
+	}
+	defer syscall.CloseHandle(h)
+
+	counters, err := c.api.GetProcessCounters(h)
+	if err != nil {
+		return Reading{}, false
+	}
+	start, err := c.api.GetProcessStartTime(h)
+	if err != nil {
+		return Reading{}, false
+	}
+
+	return Reading{
+		PID:          pid,
+		ImageName:    imageName,
+		StartTime:    start.UnixNano(),
+		CPUTime:      counters.CPUTime,
+		WorkingSet:   counters.WorkingSet,
+		PrivateBytes: counters.PrivateBytes,
+		Handles:      counters.Handles,
+		GDIObjects:   counters.GDIObjects,
+		UserObjects:  counters.UserObjects,
+	}, true
+}
@@ -0,0 +1,270 @@
+package metrics
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"hptools/internal/events"
+	"hptools/internal/models"
+)
+
+const (
+	// EventName is the frontend event that carries each round of samples
+	EventName = "process-metrics"
+	// DefaultInterval is the time between two samples
+	DefaultInterval = 2 * time.Second
+	// HistoryLength is the number of samples kept per process
+	HistoryLength = 60
+)
+
+// processKey identifies a process instance; PIDs are reused, start times are not
+type processKey struct {
+	pid       int
+	startTime int64
+}
+
+// history is a fixed-size ring of the latest samples of one process
+type history struct {
+	key     processKey
+	last    Reading
+	samples [HistoryLength]models.ProcessMetrics
+	next    int
+	count   int
+}
+
+func (h *history) add(m models.ProcessMetrics) {
+	h.samples[h.next] = m
+	h.next = (h.next + 1) % HistoryLength
+	h.count = min(h.count+1, HistoryLength)
+}
+
+// ordered returns the samples oldest first
+func (h *history) ordered() []models.ProcessMetrics {
+	out := make([]models.ProcessMetrics, 0, h.count)
+	start := (h.next - h.count + HistoryLength) % HistoryLength
+	for i := 0; i < h.count; i++ {
+		out = append(out, h.samples[(start+i)%HistoryLength])
+	}
+	return out
+}
+
+// latest returns the newest sample
+func (h *history) latest() models.ProcessMetrics {
+	return h.samples[(h.next-1+HistoryLength)%HistoryLength]
+}
+
+// Sampler periodically collects process counters, turns CPU times into
+// percentages and keeps a short history per process
+type Sampler struct {
+	collector Collector
+	interval  time.Duration
+	logger    *slog.Logger
+	feed      *events.Feed[[]models.ProcessMetrics]
+	// now returns the sample time; tests replace it
+	now func() time.Time
+
+	mu        sync.Mutex
+	histories map[int]*history
+	lastAt    time.Time
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewSampler creates a sampler that collects every interval
+func NewSampler(collector Collector, interval time.Duration, logger *slog.Logger) *Sampler {
+	return &Sampler{
+		collector: collector,
+		interval:  interval,
+		logger:    logger,
+		feed:      events.NewFeed[[]models.ProcessMetrics](),
+		now:       time.Now,
+		histories: make(map[int]*history),
+	}
+}
+
+// Start begins sampling in the background. Calling Start on a running sampler is a no-op.
+func (s *Sampler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go s.run(s.stop, s.done)
+}
+
+// Stop ends sampling and waits for a running sample to finish
+func (s *Sampler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (s *Sampler) run(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.sample()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.sample()
+		}
+	}
+}
+
+// OnSample registers fn to receive the latest metrics of every process after
+// each sample and returns a function that removes it again
+func (s *Sampler) OnSample(fn func([]models.ProcessMetrics)) func() {
+	return s.feed.Subscribe(fn)
+}
+
+// sample collects one round of readings and updates the histories
+func (s *Sampler) sample() {
+	readings, err := s.collector.Collect()
+	if err != nil {
+		s.logger.Warn("Failed to collect process metrics", "error", err)
+		return
+	}
+	now := s.now()
+
+	s.mu.Lock()
+	elapsed := now.Sub(s.lastAt)
+	first := s.lastAt.IsZero()
+	s.lastAt = now
+
+	seen := make(map[int]bool, len(readings))
+	latest := make([]models.ProcessMetrics, 0, len(readings))
+	for _, r := range readings {
+		key := processKey{pid: r.PID, startTime: r.StartTime}
+		h := s.histories[r.PID]
+		if h == nil || h.key != key {
+			h = &history{key: key}
+			s.histories[r.PID] = h
+		} else if !first {
+			// CPU usage needs a previous reading of the same process
+			m := metricsOf(r, now)
+			m.CPUPercent = cpuPercent(r.CPUTime-h.last.CPUTime, elapsed)
+			h.add(m)
+			latest = append(latest, m)
+		}
+		h.last = r
+		seen[r.PID] = true
+	}
+
+	for pid := range s.histories {
+		if !seen[pid] {
+			delete(s.histories, pid)
+		}
+	}
+	s.mu.Unlock()
+
+	s.feed.Send(latest)
+}
+
+// metricsOf converts a reading without CPU usage
+func metricsOf(r Reading, at time.Time) models.ProcessMetrics {
+	return models.ProcessMetrics{
+		PID:          r.PID,
+		ImageName:    r.ImageName,
+		SampledAt:    at,
+		WorkingSet:   r.WorkingSet,
+		PrivateBytes: r.PrivateBytes,
+		Handles:      r.Handles,
+		GDIObjects:   r.GDIObjects,
+		UserObjects:  r.UserObjects,
+	}
+}
+
+// cpuPercent returns CPU usage as a share of all logical CPUs, like Task Manager
+func cpuPercent(cpu, elapsed time.Duration) float64 {
+	if elapsed <= 0 || cpu < 0 {
+		return 0
+	}
+	return float64(cpu) / float64(elapsed) / float64(runtime.NumCPU()) * 100
+}
+
+// Latest returns the newest sample of every process
+func (s *Sampler) Latest() []models.ProcessMetrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]models.ProcessMetrics, 0, len(s.histories))
+	for _, h := range s.histories {
+		if h.count > 0 {
+			out = append(out, h.latest())
+		}
+	}
+	return out
+}
+
+// History returns the kept samples of a process, oldest first
+func (s *Sampler) History(pid int) []models.ProcessMetrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.histories[pid]
+	if h == nil {
+		return nil
+	}
+	return h.ordered()
+}
+
+// Top returns the n processes with the highest value of key in their newest
+// sample. A non-positive n returns all processes in order.
+func (s *Sampler) Top(key models.MetricKey, n int) ([]models.ProcessMetrics, error) {
+	value, ok := metricValue(key)
+	if !ok {
+		return nil, fmt.Errorf("unknown metric %q", key)
+	}
+
+	latest := s.Latest()
+	sort.Slice(latest, func(i, j int) bool {
+		vi, vj := value(latest[i]), value(latest[j])
+		if vi != vj {
+			return vi > vj
+		}
+		return latest[i].PID < latest[j].PID
+	})
+
+	if n > 0 && n < len(latest) {
+		latest = latest[:n]
+	}
+	return latest, nil
+}
+
+// metricValue returns an accessor for a metric key
+func metricValue(key models.MetricKey) (func(models.ProcessMetrics) float64, bool) {
+	switch key {
+	case models.MetricCPU:
+		return func(m models.ProcessMetrics) float64 { return m.CPUPercent }, true
+	case models.MetricWorkingSet:
+		return func(m models.ProcessMetrics) float64 { return float64(m.WorkingSet) }, true
+	case models.MetricPrivateBytes:
+		return func(m models.ProcessMetrics) float64 { return float64(m.PrivateBytes) }, true
+	case models.MetricHandles:
+		return func(m models.ProcessMetrics) float64 { return float64(m.Handles) }, true
+	case models.MetricGDIObjects:
+		return func(m models.ProcessMetrics) float64 { return float64(m.GDIObjects) }, true
+	case models.MetricUserObjects:
+		return func(m models.ProcessMetrics) float64 { return float64(m.UserObjects) }, true
+	}
+	return nil, false
+}
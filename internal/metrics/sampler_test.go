@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"log/slog"
+	"math"
+	"runtime"
+	"testing"
+	"time"
+
+	"hptools/internal/models"
+)
+
+// fakeCollector returns one round of readings per call, then the last round again
+type fakeCollector struct {
+	rounds [][]Reading
+}
+
+func (f *fakeCollector) Collect() ([]Reading, error) {
+	round := f.rounds[0]
+	if len(f.rounds) > 1 {
+		f.rounds = f.rounds[1:]
+	}
+	return round, nil
+}
+
+// newTestSampler creates a sampler whose clock advances two seconds per sample
+func newTestSampler(rounds ...[]Reading) *Sampler {
+	s := NewSampler(&fakeCollector{rounds: rounds}, DefaultInterval, slog.New(slog.DiscardHandler))
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time {
+		at = at.Add(2 * time.Second)
+		return at
+	}
+	return s
+}
+
+func TestCPUPercent(t *testing.T) {
+	cpus := float64(runtime.NumCPU())
+
+	tests := []struct {
+		name    string
+		cpu     time.Duration
+		elapsed time.Duration
+		want    float64
+	}{
+		{"one CPU busy", 2 * time.Second, 2 * time.Second, 100 / cpus},
+		{"one CPU half busy", time.Second, 2 * time.Second, 50 / cpus},
+		{"idle", 0, 2 * time.Second, 0},
+		{"no elapsed time", time.Second, 0, 0},
+		{"counter went backwards", -time.Second, 2 * time.Second, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cpuPercent(tt.cpu, tt.elapsed); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("cpuPercent(%v, %v) = %v, want %v", tt.cpu, tt.elapsed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	tests := []struct {
+		name      string
+		adds      int
+		wantFirst int
+	}{
+		{"single sample", 1, 0},
+		{"partly filled", 10, 0},
+		{"exactly full", HistoryLength, 0},
+		{"wrapped around", HistoryLength + 5, 5},
+		{"wrapped twice", 2*HistoryLength + 1, HistoryLength + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h history
+			for i := 0; i < tt.adds; i++ {
+				h.add(models.ProcessMetrics{Handles: uint32(i)})
+			}
+
+			ordered := h.ordered()
+			wantLen := min(tt.adds, HistoryLength)
+			if len(ordered) != wantLen {
+				t.Fatalf("ordered() has %d samples, want %d", len(ordered), wantLen)
+			}
+			for i, m := range ordered {
+				if int(m.Handles) != tt.wantFirst+i {
+					t.Fatalf("ordered()[%d] = sample %d, want %d", i, m.Handles, tt.wantFirst+i)
+				}
+			}
+			if got := h.latest().Handles; int(got) != tt.adds-1 {
+				t.Errorf("latest() = sample %d, want %d", got, tt.adds-1)
+			}
+		})
+	}
+}
+
+func TestSamplerHistories(t *testing.T) {
+	s := newTestSampler(
+		[]Reading{
+			{PID: 1, ImageName: "editor", StartTime: 100, CPUTime: time.Second},
+			{PID: 2, ImageName: "shell", StartTime: 200},
+		},
+		[]Reading{
+			{PID: 1, ImageName: "editor", StartTime: 100, CPUTime: 2 * time.Second, WorkingSet: 4096},
+			// PID 2 was reused by another process
+			{PID: 2, ImageName: "browser", StartTime: 300, CPUTime: time.Minute},
+		},
+		[]Reading{
+			{PID: 2, ImageName: "browser", StartTime: 300, CPUTime: time.Minute},
+		},
+	)
+
+	var rounds [][]models.ProcessMetrics
+	s.OnSample(func(latest []models.ProcessMetrics) {
+		rounds = append(rounds, latest)
+	})
+
+	// The first round has no previous CPU time
+	s.sample()
+	if len(rounds[0]) != 0 {
+		t.Errorf("first round = %v, want no samples", rounds[0])
+	}
+
+	s.sample()
+	if len(rounds[1]) != 1 || rounds[1][0].PID != 1 {
+		t.Fatalf("second round = %v, want only PID 1", rounds[1])
+	}
+	want := 50 / float64(runtime.NumCPU())
+	if got := rounds[1][0].CPUPercent; math.Abs(got-want) > 1e-9 {
+		t.Errorf("CPUPercent = %v, want %v", got, want)
+	}
+	if h := s.History(1); len(h) != 1 || h[0].WorkingSet != 4096 {
+		t.Errorf("History(1) = %v, want one sample", h)
+	}
+	if h := s.History(2); len(h) != 0 {
+		t.Errorf("History(2) = %v, want it restarted for the new process", h)
+	}
+
+	// PID 1 exited
+	s.sample()
+	if h := s.History(1); h != nil {
+		t.Errorf("History(1) = %v, want it pruned", h)
+	}
+	if h := s.History(2); len(h) != 1 || h[0].ImageName != "browser" || h[0].CPUPercent != 0 {
+		t.Errorf("History(2) = %v, want one idle browser sample", h)
+	}
+}
+
+func TestSamplerTop(t *testing.T) {
+	round := []Reading{
+		{PID: 4, StartTime: 1, WorkingSet: 300, Handles: 10},
+		{PID: 2, StartTime: 1, WorkingSet: 500, Handles: 10},
+		{PID: 3, StartTime: 1, WorkingSet: 300, Handles: 30},
+		{PID: 1, StartTime: 1, WorkingSet: 100, Handles: 10},
+	}
+	s := newTestSampler(round)
+	s.sample()
+	s.sample()
+
+	tests := []struct {
+		name    string
+		key     models.MetricKey
+		n       int
+		want    []int
+		wantErr bool
+	}{
+		{name: "highest first", key: models.MetricWorkingSet, n: 2, want: []int{2, 3}},
+		{name: "ties by PID", key: models.MetricWorkingSet, n: 0, want: []int{2, 3, 4, 1}},
+		{name: "remaining ties by PID", key: models.MetricHandles, n: -1, want: []int{3, 1, 2, 4}},
+		{name: "n beyond the process count", key: models.MetricCPU, n: 10, want: []int{1, 2, 3, 4}},
+		{name: "unknown metric", key: "threads", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			top, err := s.Top(tt.key, tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Top() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var pids []int
+			for _, m := range top {
+				pids = append(pids, m.PID)
+			}
+			if len(pids) != len(tt.want) {
+				t.Fatalf("Top() = %v, want %v", pids, tt.want)
+			}
+			for i := range pids {
+				if pids[i] != tt.want[i] {
+					t.Fatalf("Top() = %v, want %v", pids, tt.want)
+				}
+			}
+		})
+	}
+}
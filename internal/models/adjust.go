@@ -0,0 +1,46 @@
+package models
+
+// Anchor names the point of a window that stays fixed while it is resized
+type Anchor string
+
+const (
+	AnchorTopLeft     Anchor = "top-left"
+	AnchorTop         Anchor = "top"
+	AnchorTopRight    Anchor = "top-right"
+	AnchorLeft        Anchor = "left"
+	AnchorCenter      Anchor = "center"
+	AnchorRight       Anchor = "right"
+	AnchorBottomLeft  Anchor = "bottom-left"
+	AnchorBottom      Anchor = "bottom"
+	AnchorBottomRight Anchor = "bottom-right"
+)
+
+// AdjustmentKind names a relative window operation
+type AdjustmentKind string
+
+const (
+	// AdjustMoveBy moves the window by DX, DY
+	AdjustMoveBy AdjustmentKind = "move-by"
+	// AdjustResizeBy grows the window by DWidth, DHeight around Anchor
+	AdjustResizeBy AdjustmentKind = "resize-by"
+	// AdjustScale sets the size to Percent of the current size around Anchor
+	AdjustScale AdjustmentKind = "scale"
+	// AdjustFitAspect makes the window as large as fits in Width x Height
+	// while keeping its aspect ratio. A zero Width or Height is unbounded.
+	AdjustFitAspect AdjustmentKind = "fit-aspect"
+)
+
+// WindowAdjustment is a move or resize relative to the current window rect.
+// The result is clamped to the work area of the window's monitor.
+type WindowAdjustment struct {
+	Kind    AdjustmentKind `json:"kind"`
+	DX      int            `json:"dx,omitempty"`
+	DY      int            `json:"dy,omitempty"`
+	DWidth  int            `json:"dWidth,omitempty"`
+	DHeight int            `json:"dHeight,omitempty"`
+	Percent int            `json:"percent,omitempty"`
+	Width   int            `json:"width,omitempty"`
+	Height  int            `json:"height,omitempty"`
+	// Anchor defaults to the top-left corner
+	Anchor Anchor `json:"anchor,omitempty"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// VirtualDesktop represents a Windows virtual desktop
+type VirtualDesktop struct {
+	ID      string `json:"id"`
+	Index   int    `json:"index"`
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+}
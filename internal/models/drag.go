@@ -0,0 +1,14 @@
+package models
+
+// DragOptions configures moving windows with Alt+drag and resizing them with
+// Alt+right-drag from anywhere inside the window
+type DragOptions struct {
+	Enabled bool `json:"enabled"`
+	// SnapThreshold is the distance in pixels within which dragged edges
+	// snap to monitor and window edges; 0 disables snapping
+	SnapThreshold int `json:"snapThreshold"`
+	// Overlap is applied to the window when it is dropped
+	Overlap OverlapPolicy `json:"overlap,omitempty"`
+	// Exclude lists windows that are never dragged
+	Exclude []WindowMatch `json:"exclude,omitempty"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// FilterAction decides what happens to a process matched by a filter rule
+type FilterAction string
+
+const (
+	// FilterInclude shows the matched process
+	FilterInclude FilterAction = "include"
+	// FilterExclude hides the matched process
+	FilterExclude FilterAction = "exclude"
+)
+
+// FilterProfile is a named, ordered list of process filter rules
+type FilterProfile struct {
+	Name string `json:"name"`
+	// Default is the action for processes that no rule matches
+	Default FilterAction `json:"default"`
+	// Group merges processes of the same application into one entry
+	Group bool         `json:"group"`
+	Rules []FilterRule `json:"rules"`
+}
+
+// FilterRule matches processes by any combination of criteria.
+// Empty criteria are ignored; all set criteria must match.
+// Rules are evaluated in order and the first matching rule wins.
+type FilterRule struct {
+	Action      FilterAction `json:"action"`
+	ImageGlob   string       `json:"imageGlob,omitempty"`
+	PathPrefix  string       `json:"pathPrefix,omitempty"`
+	WindowClass string       `json:"windowClass,omitempty"`
+	TitleRegex  string       `json:"titleRegex,omitempty"`
+	Session     *int         `json:"session,omitempty"`
+	Elevated    *bool        `json:"elevated,omitempty"`
+}
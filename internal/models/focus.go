@@ -0,0 +1,14 @@
+package models
+
+// FocusFollowOptions configures focus-follows-mouse. The window under the
+// cursor is focused once the cursor has rested on it for DelayMS; with
+// AutoRaise it is also raised after a further RaiseDelayMS. Without
+// AutoRaise focused windows keep their place in the z-order.
+type FocusFollowOptions struct {
+	Enabled      bool `json:"enabled"`
+	DelayMS      int  `json:"delayMs"`
+	AutoRaise    bool `json:"autoRaise"`
+	RaiseDelayMS int  `json:"raiseDelayMs"`
+	// ExcludeProcesses are image name globs of processes never focused
+	ExcludeProcesses []string `json:"excludeProcesses,omitempty"`
+}
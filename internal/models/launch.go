@@ -0,0 +1,25 @@
+package models
+
+// LaunchTarget is what to start: an executable, a shortcut (.lnk) or a
+// shell: URI, with optional arguments and working directory
+type LaunchTarget struct {
+	Path       string `json:"path"`
+	Args       string `json:"args,omitempty"`
+	WorkingDir string `json:"workingDir,omitempty"`
+}
+
+// LaunchRequest starts an application and places its main window once it
+// appears. TimeoutMS limits the wait for the window; 0 uses the default.
+type LaunchRequest struct {
+	LaunchTarget
+	WindowTarget
+	TimeoutMS int `json:"timeoutMs,omitempty"`
+}
+
+// LaunchResult identifies the window a launch produced. PID is the process
+// that owns the window, which may be a child of the started process.
+type LaunchResult struct {
+	PID    int     `json:"pid"`
+	Handle uintptr `json:"handle"`
+	Title  string  `json:"title"`
+}
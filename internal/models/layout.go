@@ -0,0 +1,70 @@
+package models
+
+// Placement names a predefined window placement relative to a monitor
+type Placement string
+
+const (
+	// PlacementLeftHalf fills the left half of the monitor work area
+	PlacementLeftHalf Placement = "left-half"
+	// PlacementRightHalf fills the right half of the monitor work area
+	PlacementRightHalf Placement = "right-half"
+	// PlacementCenter centers the window in the work area, keeping its size
+	PlacementCenter Placement = "center"
+	// PlacementLeftThird fills the left third of the monitor work area
+	PlacementLeftThird Placement = "left-third"
+	// PlacementLeftTwoThirds fills the left two thirds of the monitor work area
+	PlacementLeftTwoThirds Placement = "left-two-thirds"
+	// PlacementRightThird fills the right third of the monitor work area
+	PlacementRightThird Placement = "right-third"
+	// PlacementRightTwoThirds fills the right two thirds of the monitor work area
+	PlacementRightTwoThirds Placement = "right-two-thirds"
+	// PlacementNextMonitor moves the window to the next monitor
+	PlacementNextMonitor Placement = "next-monitor"
+	// PlacementPreviousMonitor moves the window to the previous monitor
+	PlacementPreviousMonitor Placement = "previous-monitor"
+)
+
+// Placement cycles applied by repeated cycle actions
+var (
+	// CycleLeft steps through the left half, third and two thirds
+	CycleLeft = []Placement{PlacementLeftHalf, PlacementLeftThird, PlacementLeftTwoThirds}
+	// CycleRight steps through the right half, third and two thirds
+	CycleRight = []Placement{PlacementRightHalf, PlacementRightThird, PlacementRightTwoThirds}
+)
+
+// Layout is a named set of window placements
+type Layout struct {
+	Name    string         `json:"name"`
+	Windows []LayoutWindow `json:"windows"`
+}
+
+// WindowTarget describes where a window should go. Rect takes precedence
+// over Monitor and Placement. Monitor is the index of the monitor to move the
+// window to first; Placement then applies on that monitor. There is no
+// virtual desktop target: Windows only moves windows of the calling process
+// between desktops.
+type WindowTarget struct {
+	Placement Placement `json:"placement,omitempty"`
+	Rect      *Rect     `json:"rect,omitempty"`
+	Monitor   *int      `json:"monitor,omitempty"`
+}
+
+// Empty reports whether the target leaves the window where it is
+func (t WindowTarget) Empty() bool {
+	return t.Rect == nil && t.Placement == "" && t.Monitor == nil
+}
+
+// LayoutWindow describes where a window of a given application should go.
+// If the application is not running and Launch is set, it is started first.
+type LayoutWindow struct {
+	ImageName string `json:"imageName"`
+	WindowTarget
+	Launch *LaunchTarget `json:"launch,omitempty"`
+}
+
+// DisplayLayout associates a monitor setup, identified by its fingerprint,
+// with the layout to apply when that setup becomes active
+type DisplayLayout struct {
+	Fingerprint string `json:"fingerprint"`
+	Layout      string `json:"layout"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ProcessMetrics is one resource sample of a process.
+// GDIObjects and UserObjects are only reported on Windows.
+type ProcessMetrics struct {
+	PID          int       `json:"pid"`
+	ImageName    string    `json:"imageName"`
+	SampledAt    time.Time `json:"sampledAt"`
+	CPUPercent   float64   `json:"cpuPercent"`
+	WorkingSet   uint64    `json:"workingSet"`
+	PrivateBytes uint64    `json:"privateBytes"`
+	Handles      uint32    `json:"handles"`
+	GDIObjects   uint32    `json:"gdiObjects"`
+	UserObjects  uint32    `json:"userObjects"`
+}
+
+// MetricKey names a ProcessMetrics field processes can be ranked by
+type MetricKey string
+
+const (
+	MetricCPU          MetricKey = "cpu"
+	MetricWorkingSet   MetricKey = "workingSet"
+	MetricPrivateBytes MetricKey = "privateBytes"
+	MetricHandles      MetricKey = "handles"
+	MetricGDIObjects   MetricKey = "gdiObjects"
+	MetricUserObjects  MetricKey = "userObjects"
+)
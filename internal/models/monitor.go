@@ -0,0 +1,45 @@
+package models
+
+// BoundsPolicy decides what happens to a requested window rect that is not
+// on the current monitors
+type BoundsPolicy string
+
+const (
+	// BoundsReject refuses rects the user could not reach afterwards
+	BoundsReject BoundsPolicy = "reject"
+	// BoundsClamp moves and shrinks rects into the work area they overlap most
+	BoundsClamp BoundsPolicy = "clamp"
+	// BoundsAllow applies rects unchanged
+	BoundsAllow BoundsPolicy = "allow"
+)
+
+// OverlapPolicy decides what happens to a snapped window rect that overlaps
+// other windows
+type OverlapPolicy string
+
+const (
+	// OverlapAllow leaves overlaps alone
+	OverlapAllow OverlapPolicy = "allow"
+	// OverlapAvoid moves the rect the shortest distance that clears all
+	// windows within its work area; resized rects are shrunk instead
+	OverlapAvoid OverlapPolicy = "avoid"
+	// OverlapShrink cuts the rect back so it stops covering other windows
+	OverlapShrink OverlapPolicy = "shrink"
+)
+
+// SnapOptions configures how window rects align with nearby edges.
+// Edges within Threshold pixels of a monitor, work area or window edge snap
+// to it; 0 disables snapping.
+type SnapOptions struct {
+	Threshold int           `json:"threshold"`
+	Overlap   OverlapPolicy `json:"overlap,omitempty"`
+}
+
+// MonitorInfo represents a connected display
+type MonitorInfo struct {
+	Handle   uintptr `json:"handle"`
+	Name     string  `json:"name"`
+	Primary  bool    `json:"primary"`
+	Bounds   Rect    `json:"bounds"`
+	WorkArea Rect    `json:"workArea"`
+}
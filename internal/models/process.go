@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // ProcessInfo represents information about a running process
 type ProcessInfo struct {
 	ImageName   string `json:"imageName"`
@@ -9,14 +11,34 @@ type ProcessInfo struct {
 	MemUsageB   int64  `json:"memUsageB"`
 	MemUsageStr string `json:"memUsageStr"`
 	WindowTitle string `json:"windowTitle"`
+	WindowClass string `json:"windowClass"`
 	HasWindow   bool   `json:"hasWindow"`
 	WindowCount int    `json:"windowCount"`
+	AppID       string `json:"appId,omitempty"`
+
+	// Windows lists every top-level window of the entry, main window first
+	Windows []WindowEntry   `json:"windows,omitempty"`
+	Details *ProcessDetails `json:"details,omitempty"`
+}
+
+// ProcessDetails contains metadata that is not available from tasklist
+type ProcessDetails struct {
+	ExePath      string    `json:"exePath"`
+	CommandLine  string    `json:"commandLine"`
+	ParentPID    int       `json:"parentPid"`
+	StartTime    time.Time `json:"startTime"`
+	User         string    `json:"user"`
+	Architecture string    `json:"architecture"`
+	Elevated     bool      `json:"elevated"`
+	AppID        string    `json:"appId,omitempty"` // AppUserModelID of packaged apps
 }
 
 // ProcessWindowInfo contains window information for a process
 type ProcessWindowInfo struct {
 	HasWindow     bool
 	WindowTitle   string
+	WindowClass   string
 	WindowCount   int
 	MainWindowPID int
+	Windows       []WindowEntry
 }
@@ -0,0 +1,40 @@
+package models
+
+// ProcessAction names an action that changes a running process
+type ProcessAction string
+
+const (
+	ProcessActionEnd      ProcessAction = "end"
+	ProcessActionSuspend  ProcessAction = "suspend"
+	ProcessActionResume   ProcessAction = "resume"
+	ProcessActionPriority ProcessAction = "priority"
+	ProcessActionAffinity ProcessAction = "affinity"
+)
+
+// PriorityClass is the scheduling priority class of a process
+type PriorityClass string
+
+const (
+	PriorityIdle        PriorityClass = "idle"
+	PriorityBelowNormal PriorityClass = "below-normal"
+	PriorityNormal      PriorityClass = "normal"
+	PriorityAboveNormal PriorityClass = "above-normal"
+	PriorityHigh        PriorityClass = "high"
+	PriorityRealtime    PriorityClass = "realtime"
+)
+
+// ProcessActionResult describes a process action. With DryRun set nothing
+// was changed, and the result tells the user what the action would do.
+// Before and After hold the priority class or affinity mask for those actions.
+type ProcessActionResult struct {
+	Action    ProcessAction `json:"action"`
+	PID       int           `json:"pid"`
+	ImageName string        `json:"imageName"`
+	DryRun    bool          `json:"dryRun"`
+	Summary   string        `json:"summary"`
+	Windows   []WindowEntry `json:"windows,omitempty"`
+	Before    string        `json:"before,omitempty"`
+	After     string        `json:"after,omitempty"`
+	// Forced is set when a process did not exit after its windows were closed and was terminated
+	Forced bool `json:"forced,omitempty"`
+}
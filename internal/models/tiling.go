@@ -0,0 +1,29 @@
+package models
+
+// TilingLayout names how tiled windows share the work area
+type TilingLayout string
+
+const (
+	// TilingMasterStack gives the first window the left part of the work
+	// area and stacks the others on the right
+	TilingMasterStack TilingLayout = "master-stack"
+	// TilingColumns splits the work area into equal columns
+	TilingColumns TilingLayout = "columns"
+	// TilingGrid arranges the windows in a near-square grid
+	TilingGrid TilingLayout = "grid"
+	// TilingMonocle gives every window the whole work area
+	TilingMonocle TilingLayout = "monocle"
+)
+
+// TilingOptions configures automatic tiling of one monitor. Windows that
+// open on the monitor and match any rule in Match are tiled; an empty Match
+// tiles every main window. MasterRatio is the share of the width given to
+// the master window; Gap is the space in pixels around and between tiles.
+type TilingOptions struct {
+	Enabled     bool          `json:"enabled"`
+	Monitor     int           `json:"monitor"`
+	Layout      TilingLayout  `json:"layout"`
+	MasterRatio float64       `json:"masterRatio"`
+	Gap         int           `json:"gap"`
+	Match       []WindowMatch `json:"match,omitempty"`
+}
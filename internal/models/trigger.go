@@ -0,0 +1,61 @@
+package models
+
+// TriggerCondition is the change to a matching window that fires a trigger
+type TriggerCondition string
+
+const (
+	// TriggerAppears fires when a window starts matching: it is shown, or its
+	// title or class changes so that it matches
+	TriggerAppears TriggerCondition = "appears"
+	// TriggerChanges fires when the title of a matching window changes and it
+	// still matches
+	TriggerChanges TriggerCondition = "changes"
+	// TriggerDisappears fires when a matching window is hidden, destroyed or
+	// stops matching
+	TriggerDisappears TriggerCondition = "disappears"
+)
+
+// TriggerActionType identifies what a trigger action does
+type TriggerActionType string
+
+const (
+	// TriggerFocus brings the window to the foreground
+	TriggerFocus TriggerActionType = "focus"
+	// TriggerPlace moves the window to the action's window target
+	TriggerPlace TriggerActionType = "place"
+	// TriggerFlash flashes the window's caption and taskbar button
+	TriggerFlash TriggerActionType = "flash"
+	// TriggerRun starts the action's command
+	TriggerRun TriggerActionType = "run"
+	// TriggerNotify posts a notification with the action's message
+	TriggerNotify TriggerActionType = "notify"
+)
+
+// Trigger runs actions when a window matching Match changes as described by On.
+// CooldownMS suppresses repeated firing for the same window.
+type Trigger struct {
+	Name       string             `json:"name"`
+	On         []TriggerCondition `json:"on"`
+	Match      WindowMatch        `json:"match"`
+	Actions    []TriggerAction    `json:"actions"`
+	CooldownMS int                `json:"cooldownMs,omitempty"`
+}
+
+// TriggerAction is one action of a trigger. WindowTarget is used by "place",
+// Command by "run" and Message by "notify"; "{title}" in Message is replaced
+// with the window title.
+type TriggerAction struct {
+	Type TriggerActionType `json:"type"`
+	WindowTarget
+	Command *LaunchTarget `json:"command,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// TriggerNotification is posted by a "notify" action
+type TriggerNotification struct {
+	Trigger   string           `json:"trigger"`
+	Condition TriggerCondition `json:"condition"`
+	Message   string           `json:"message"`
+	Title     string           `json:"title"`
+	Handle    uintptr          `json:"handle"`
+}
@@ -2,13 +2,78 @@ package models
 
 // WindowInfo represents window position and size information
 type WindowInfo struct {
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	DesktopID string `json:"desktopId,omitempty"`
+}
+
+// WindowEntry describes a top-level window of a process
+type WindowEntry struct {
+	Handle uintptr `json:"handle"`
+	PID    int     `json:"pid"`
+	Title  string  `json:"title"`
+	Class  string  `json:"class"`
+	AppID  string  `json:"appId,omitempty"`
+}
+
+// RecentWindow describes a window that was recently managed by hptools
+type RecentWindow struct {
+	PID   int    `json:"pid"`
+	Title string `json:"title"`
+}
+
+// Rect represents a rectangle in screen coordinates
+type Rect struct {
 	X      int `json:"x"`
 	Y      int `json:"y"`
 	Width  int `json:"width"`
 	Height int `json:"height"`
 }
 
+// Right returns the x coordinate of the right edge
+func (r Rect) Right() int {
+	return r.X + r.Width
+}
+
+// Bottom returns the y coordinate of the bottom edge
+func (r Rect) Bottom() int {
+	return r.Y + r.Height
+}
+
 // RECT structure for window coordinates
 type RECT struct {
 	Left, Top, Right, Bottom int32
 }
+
+// ToRect converts a Windows RECT to a Rect
+func (r RECT) ToRect() Rect {
+	return Rect{
+		X:      int(r.Left),
+		Y:      int(r.Top),
+		Width:  int(r.Right - r.Left),
+		Height: int(r.Bottom - r.Top),
+	}
+}
+
+// WindowEventType identifies what changed about a window
+type WindowEventType string
+
+const (
+	WindowCreated      WindowEventType = "created"
+	WindowDestroyed    WindowEventType = "destroyed"
+	WindowShown        WindowEventType = "shown"
+	WindowHidden       WindowEventType = "hidden"
+	WindowMoved        WindowEventType = "moved"
+	WindowTitleChanged WindowEventType = "titleChanged"
+	WindowFocused      WindowEventType = "focused"
+	WindowMinimized    WindowEventType = "minimized"
+	WindowRestored     WindowEventType = "restored"
+)
+
+// WindowEvent reports a change to a top-level window
+type WindowEvent struct {
+	Type   WindowEventType `json:"type"`
+	Handle uintptr         `json:"handle"`
+}
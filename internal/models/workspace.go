@@ -0,0 +1,56 @@
+package models
+
+// Workspace is a named set of applications that are launched if missing and
+// arranged together
+type Workspace struct {
+	Name string         `json:"name"`
+	Apps []WorkspaceApp `json:"apps"`
+}
+
+// WorkspaceApp is one application of a workspace. Apps are handled in order;
+// DelayMS waits after the app before the next one is handled.
+type WorkspaceApp struct {
+	Name  string      `json:"name"`
+	Match WindowMatch `json:"match"`
+	// Launch starts the app when no running process matches
+	Launch *LaunchTarget `json:"launch,omitempty"`
+	WindowTarget
+	DelayMS   int `json:"delayMs,omitempty"`
+	TimeoutMS int `json:"timeoutMs,omitempty"`
+}
+
+// WindowMatch finds the running process of a workspace app.
+// Empty criteria are ignored; all set criteria must match.
+type WindowMatch struct {
+	ImageGlob   string `json:"imageGlob,omitempty"`
+	WindowClass string `json:"windowClass,omitempty"`
+	TitleRegex  string `json:"titleRegex,omitempty"`
+}
+
+// WorkspaceAppState is the outcome for one app of a workspace run
+type WorkspaceAppState string
+
+const (
+	// WorkspaceAppReused means a running process was arranged
+	WorkspaceAppReused WorkspaceAppState = "reused"
+	// WorkspaceAppLaunched means the app was started and arranged
+	WorkspaceAppLaunched WorkspaceAppState = "launched"
+	// WorkspaceAppSkipped means the app was not running and has no launch target
+	WorkspaceAppSkipped WorkspaceAppState = "skipped"
+	// WorkspaceAppFailed means matching, launching or arranging failed
+	WorkspaceAppFailed WorkspaceAppState = "failed"
+)
+
+// WorkspaceAppStatus reports what a workspace run did with one app
+type WorkspaceAppStatus struct {
+	Name  string            `json:"name"`
+	State WorkspaceAppState `json:"state"`
+	PID   int               `json:"pid,omitempty"`
+	Error string            `json:"error,omitempty"`
+}
+
+// WorkspaceResult reports the outcome of a workspace run, one status per app
+type WorkspaceResult struct {
+	Workspace string               `json:"workspace"`
+	Apps      []WorkspaceAppStatus `json:"apps"`
+}
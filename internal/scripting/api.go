@@ -0,0 +1,227 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+
+	"hptools/internal/models"
+)
+
+// eventTypes are the window events scripts can register callbacks for
+var eventTypes = map[models.WindowEventType]bool{
+	models.WindowCreated:      true,
+	models.WindowDestroyed:    true,
+	models.WindowShown:        true,
+	models.WindowHidden:       true,
+	models.WindowMoved:        true,
+	models.WindowTitleChanged: true,
+	models.WindowFocused:      true,
+	models.WindowMinimized:    true,
+	models.WindowRestored:     true,
+}
+
+// builtins returns the functions predeclared for scripts
+func (e *Engine) builtins() starlark.StringDict {
+	return starlark.StringDict{
+		"list_windows": starlark.NewBuiltin("list_windows", e.listWindows),
+		"monitors":     starlark.NewBuiltin("monitors", e.monitors),
+		"move":         starlark.NewBuiltin("move", e.move),
+		"focus":        starlark.NewBuiltin("focus", e.focus),
+		"on":           starlark.NewBuiltin("on", e.on),
+	}
+}
+
+// listWindows implements list_windows(), which returns the top-level windows
+// of running applications with their process and position
+func (e *Engine) listWindows(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	processes, err := e.desktop.GetApplicationProcesses()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+
+	var list []starlark.Value
+	for _, proc := range processes {
+		windows := proc.Windows
+		if len(windows) == 0 && proc.HasWindow {
+			handle, err := e.desktop.FindWindowByPID(proc.PID)
+			if err != nil {
+				continue
+			}
+			windows = []models.WindowEntry{{Handle: handle, PID: proc.PID, Title: proc.WindowTitle, Class: proc.WindowClass, AppID: proc.AppID}}
+		}
+		for _, w := range windows {
+			list = append(list, e.windowValue(proc, w))
+		}
+	}
+	return starlark.NewList(list), nil
+}
+
+// windowValue describes a window; rect is None when the window has no
+// readable position
+func (e *Engine) windowValue(proc models.ProcessInfo, w models.WindowEntry) starlark.Value {
+	var rect starlark.Value = starlark.None
+	if info, err := e.desktop.GetWindowInfoByHandle(w.Handle); err == nil {
+		rect = rectValue(models.Rect{X: info.X, Y: info.Y, Width: info.Width, Height: info.Height})
+	}
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"handle": starlark.MakeUint64(uint64(w.Handle)),
+		"pid":    starlark.MakeInt(proc.PID),
+		"image":  starlark.String(proc.ImageName),
+		"title":  starlark.String(w.Title),
+		"class":  starlark.String(w.Class),
+		"app_id": starlark.String(w.AppID),
+		"rect":   rect,
+	})
+}
+
+// monitors implements monitors(), which returns the connected displays in
+// the order used by move(monitor=...)
+func (e *Engine) monitors(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	monitors, err := e.desktop.GetMonitors()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+
+	list := make([]starlark.Value, len(monitors))
+	for i, m := range monitors {
+		list[i] = starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+			"index":     starlark.MakeInt(i),
+			"name":      starlark.String(m.Name),
+			"primary":   starlark.Bool(m.Primary),
+			"bounds":    rectValue(m.Bounds),
+			"work_area": rectValue(m.WorkArea),
+		})
+	}
+	return starlark.NewList(list), nil
+}
+
+// move implements move(handle, rect=None, placement=None, monitor=None),
+// which applies a window target like the one of a layout entry
+func (e *Engine) move(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var handle uintptr
+	var rect, placement, monitor starlark.Value = starlark.None, starlark.None, starlark.None
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "handle", &handle, "rect?", &rect, "placement?", &placement, "monitor?", &monitor); err != nil {
+		return nil, err
+	}
+
+	var target models.WindowTarget
+	if rect != starlark.None {
+		r, err := toRect(rect)
+		if err != nil {
+			return nil, fmt.Errorf("%s: rect: %w", fn.Name(), err)
+		}
+		target.Rect = &r
+	}
+	if placement != starlark.None {
+		s, ok := starlark.AsString(placement)
+		if !ok {
+			return nil, fmt.Errorf("%s: placement: got %s, want string", fn.Name(), placement.Type())
+		}
+		target.Placement = models.Placement(s)
+	}
+	if monitor != starlark.None {
+		var index int
+		if err := starlark.AsInt(monitor, &index); err != nil {
+			return nil, fmt.Errorf("%s: monitor: %w", fn.Name(), err)
+		}
+		target.Monitor = &index
+	}
+	if target.Empty() {
+		return nil, fmt.Errorf("%s: one of rect, placement or monitor is required", fn.Name())
+	}
+
+	if err := e.desktop.ApplyWindowTarget(handle, target); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+// focus implements focus(handle), which brings a window to the foreground
+func (e *Engine) focus(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var handle uintptr
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &handle); err != nil {
+		return nil, err
+	}
+	if err := e.desktop.FocusWindow(handle); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+// on implements on(event, fn), which registers fn to be called with an
+// event struct whenever a window event of that type occurs. It can only be
+// called while the script loads.
+func (e *Engine) on(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var event string
+	var callback starlark.Callable
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &event, &callback); err != nil {
+		return nil, err
+	}
+	s, ok := thread.Local(loadingKey).(*script)
+	if !ok {
+		return nil, errors.New("on: callbacks can only be registered while the script loads")
+	}
+	eventType := models.WindowEventType(event)
+	if !eventTypes[eventType] {
+		return nil, fmt.Errorf("on: unknown event %q", event)
+	}
+	s.handlers[eventType] = append(s.handlers[eventType], callback)
+	return starlark.None, nil
+}
+
+// eventValue is the argument passed to event callbacks
+func eventValue(event models.WindowEvent) starlark.Value {
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"type":   starlark.String(event.Type),
+		"handle": starlark.MakeUint64(uint64(event.Handle)),
+	})
+}
+
+func rectValue(r models.Rect) starlark.Value {
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"x":      starlark.MakeInt(r.X),
+		"y":      starlark.MakeInt(r.Y),
+		"width":  starlark.MakeInt(r.Width),
+		"height": starlark.MakeInt(r.Height),
+	})
+}
+
+// toRect accepts a struct with x, y, width and height fields, such as a
+// window's rect, or an (x, y, width, height) tuple
+func toRect(v starlark.Value) (models.Rect, error) {
+	var fields [4]starlark.Value
+	switch v := v.(type) {
+	case starlark.Tuple:
+		if len(v) != len(fields) {
+			return models.Rect{}, fmt.Errorf("got %d values, want (x, y, width, height)", len(v))
+		}
+		copy(fields[:], v)
+	case starlark.HasAttrs:
+		for i, name := range []string{"x", "y", "width", "height"} {
+			attr, err := v.Attr(name)
+			if err != nil || attr == nil {
+				return models.Rect{}, fmt.Errorf("%s has no field %s", v.Type(), name)
+			}
+			fields[i] = attr
+		}
+	default:
+		return models.Rect{}, fmt.Errorf("got %s, want struct or tuple", v.Type())
+	}
+
+	var values [4]int
+	for i, f := range fields {
+		if err := starlark.AsInt(f, &values[i]); err != nil {
+			return models.Rect{}, err
+		}
+	}
+	return models.Rect{X: values[0], Y: values[1], Width: values[2], Height: values[3]}, nil
+}
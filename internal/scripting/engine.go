@@ -0,0 +1,328 @@
+// Package scripting runs user scripts written in Starlark, a small Python
+// dialect. Scripts see the desktop only through the functions in api.go: they
+// cannot read files, load other scripts or reach the network, and every run
+// is stopped after a timeout.
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"hptools/internal/events"
+	"hptools/internal/models"
+)
+
+const (
+	// DefaultTimeout limits loading a script and each call into it
+	DefaultTimeout = 2 * time.Second
+	// maxSteps bounds the computation of one run independently of the timeout
+	maxSteps = 10_000_000
+	// queueSize is how many window events may wait for their callbacks
+	queueSize = 64
+	// scriptExt is the file extension of scripts in the scripts directory
+	scriptExt = ".star"
+	// loadingKey is the thread-local that holds the script being loaded
+	loadingKey = "script"
+)
+
+// fileOptions allow while loops and top-level statements, since runaway
+// scripts are stopped by the timeout and the step limit
+var fileOptions = &syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+}
+
+// Desktop is the window service as seen by scripts
+type Desktop interface {
+	GetApplicationProcesses() ([]models.ProcessInfo, error)
+	FindWindowByPID(pid int) (uintptr, error)
+	GetWindowInfoByHandle(handle uintptr) (*models.WindowInfo, error)
+	GetMonitors() ([]models.MonitorInfo, error)
+	ApplyWindowTarget(handle uintptr, target models.WindowTarget) error
+	FocusWindow(handle uintptr) error
+}
+
+// script is a loaded script file
+type script struct {
+	name    string
+	globals starlark.StringDict
+	// handlers are the callbacks registered with on() while the script loaded
+	handlers map[models.WindowEventType][]starlark.Callable
+}
+
+// Engine loads the scripts of a directory, runs their main function on
+// request and calls their window event callbacks
+type Engine struct {
+	desktop   Desktop
+	subscribe func(fn func(models.WindowEvent)) func()
+	timeout   time.Duration
+	logger    *slog.Logger
+	changed   *events.Notifier
+
+	mu          sync.Mutex
+	scripts     []*script
+	paused      bool
+	queue       chan models.WindowEvent
+	unsubscribe func()
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// NewEngine creates an engine without scripts. subscribe registers for window
+// events, e.g. WindowWatcher.OnWindowEvent. A non-positive timeout uses
+// DefaultTimeout.
+func NewEngine(desktop Desktop, subscribe func(fn func(models.WindowEvent)) func(), timeout time.Duration, logger *slog.Logger) *Engine {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Engine{
+		desktop:   desktop,
+		subscribe: subscribe,
+		timeout:   timeout,
+		logger:    logger,
+		changed:   events.NewNotifier(),
+	}
+}
+
+// Load replaces the loaded scripts with the .star files of dir, in name
+// order. Loading runs each file, which registers its callbacks. Files that
+// fail to load are left out and their errors are reported together. A
+// missing or empty dir unloads every script.
+func (e *Engine) Load(dir string) error {
+	var entries []os.DirEntry
+	if dir != "" {
+		var err error
+		entries, err = os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("reading scripts directory: %w", err)
+		}
+	}
+
+	var loaded []*script
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != scriptExt {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s, err := e.load(strings.TrimSuffix(entry.Name(), scriptExt), src)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		loaded = append(loaded, s)
+	}
+
+	e.mu.Lock()
+	e.scripts = loaded
+	e.mu.Unlock()
+	e.changed.Notify()
+
+	e.logger.Info("Scripts loaded", "dir", dir, "count", len(loaded), "failures", len(errs))
+	return errors.Join(errs...)
+}
+
+// load runs the top level of a script
+func (e *Engine) load(name string, src []byte) (*script, error) {
+	s := &script{name: name, handlers: make(map[models.WindowEventType][]starlark.Callable)}
+
+	thread := e.newThread(name)
+	thread.SetLocal(loadingKey, s)
+	var globals starlark.StringDict
+	err := e.withTimeout(thread, func() error {
+		var err error
+		globals, err = starlark.ExecFileOptions(fileOptions, thread, name+scriptExt, src, e.builtins())
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading script %s: %w", name, err)
+	}
+
+	// Frozen globals can be shared by the threads of later calls
+	globals.Freeze()
+	s.globals = globals
+	return s, nil
+}
+
+// Scripts returns the names of the loaded scripts that define a main
+// function and can be run with RunScript
+func (e *Engine) Scripts() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var names []string
+	for _, s := range e.scripts {
+		if _, ok := s.globals["main"].(starlark.Callable); ok {
+			names = append(names, s.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OnScriptsChanged registers a listener called after scripts are loaded.
+// It returns a function that removes the listener.
+func (e *Engine) OnScriptsChanged(fn func()) func() {
+	return e.changed.Subscribe(fn)
+}
+
+// RunScript calls the main function of a loaded script
+func (e *Engine) RunScript(name string) error {
+	s, ok := e.find(name)
+	if !ok {
+		return fmt.Errorf("script %q not found", name)
+	}
+	main, ok := s.globals["main"].(starlark.Callable)
+	if !ok {
+		return fmt.Errorf("script %q has no main function", name)
+	}
+	return e.call(s, main)
+}
+
+// SetPaused suspends or resumes the event callbacks. Events that arrive
+// while paused are dropped; RunScript still works.
+func (e *Engine) SetPaused(paused bool) error {
+	e.mu.Lock()
+	e.paused = paused
+	e.mu.Unlock()
+	return nil
+}
+
+// Start subscribes to window events and calls the callbacks of the loaded
+// scripts in the background. Calling Start on a running engine is a no-op.
+func (e *Engine) Start() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stop != nil {
+		return
+	}
+	e.queue = make(chan models.WindowEvent, queueSize)
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+	go e.run(e.queue, e.stop, e.done)
+	e.unsubscribe = e.subscribe(e.enqueue)
+}
+
+// Stop unsubscribes from window events and waits for a running callback
+func (e *Engine) Stop() {
+	e.mu.Lock()
+	unsubscribe, stop, done := e.unsubscribe, e.stop, e.done
+	e.unsubscribe, e.stop, e.done, e.queue = nil, nil, nil, nil
+	e.mu.Unlock()
+
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// enqueue hands an event to the callback goroutine; it runs on the watcher
+// thread, so it never blocks
+func (e *Engine) enqueue(event models.WindowEvent) {
+	e.mu.Lock()
+	queue, paused := e.queue, e.paused
+	e.mu.Unlock()
+
+	if queue == nil || paused {
+		return
+	}
+	select {
+	case queue <- event:
+	default:
+		e.logger.Warn("Script callbacks are behind, dropping window event", "type", event.Type, "handle", event.Handle)
+	}
+}
+
+func (e *Engine) run(queue chan models.WindowEvent, stop, done chan struct{}) {
+	defer close(done)
+	for {
+		select {
+		case <-stop:
+			return
+		case event := <-queue:
+			e.dispatch(event)
+		}
+	}
+}
+
+// dispatch calls every callback registered for the event type
+func (e *Engine) dispatch(event models.WindowEvent) {
+	e.mu.Lock()
+	scripts, paused := e.scripts, e.paused
+	e.mu.Unlock()
+
+	if paused {
+		return
+	}
+	for _, s := range scripts {
+		for _, fn := range s.handlers[event.Type] {
+			if err := e.call(s, fn, eventValue(event)); err != nil {
+				e.logger.Warn("Script callback failed", "script", s.name, "event", event.Type, "error", err)
+			}
+		}
+	}
+}
+
+// call runs a function of a script on a new thread with the timeout
+func (e *Engine) call(s *script, fn starlark.Callable, args ...starlark.Value) error {
+	thread := e.newThread(s.name)
+	return e.withTimeout(thread, func() error {
+		_, err := starlark.Call(thread, fn, args, nil)
+		return err
+	})
+}
+
+// newThread creates a thread whose print goes to the log. Without a Load
+// function, load statements fail, so scripts cannot read other files.
+func (e *Engine) newThread(name string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			e.logger.Info("Script output", "script", name, "message", msg)
+		},
+	}
+	thread.SetMaxExecutionSteps(maxSteps)
+	return thread
+}
+
+// withTimeout runs fn and cancels thread when the timeout expires. A
+// desktop call in progress finishes first; the script stops right after it.
+func (e *Engine) withTimeout(thread *starlark.Thread, fn func() error) error {
+	timer := time.AfterFunc(e.timeout, func() {
+		thread.Cancel(fmt.Sprintf("timed out after %v", e.timeout))
+	})
+	defer timer.Stop()
+	return fn()
+}
+
+// find looks up a loaded script by name
+func (e *Engine) find(name string) (*script, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, s := range e.scripts {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
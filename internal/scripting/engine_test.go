@@ -0,0 +1,354 @@
+package scripting
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hptools/internal/models"
+)
+
+// fakeDesktop serves fixed processes and monitors and records the targets
+// and focus requests of scripts
+type fakeDesktop struct {
+	processes []models.ProcessInfo
+	monitors  []models.MonitorInfo
+
+	mu      sync.Mutex
+	targets map[uintptr]models.WindowTarget
+	focused []uintptr
+}
+
+func (f *fakeDesktop) GetApplicationProcesses() ([]models.ProcessInfo, error) {
+	return f.processes, nil
+}
+
+func (f *fakeDesktop) FindWindowByPID(pid int) (uintptr, error) {
+	return uintptr(pid * 10), nil
+}
+
+func (f *fakeDesktop) GetWindowInfoByHandle(handle uintptr) (*models.WindowInfo, error) {
+	return &models.WindowInfo{X: 100, Y: 100, Width: 800, Height: 600}, nil
+}
+
+func (f *fakeDesktop) GetMonitors() ([]models.MonitorInfo, error) {
+	return f.monitors, nil
+}
+
+func (f *fakeDesktop) ApplyWindowTarget(handle uintptr, target models.WindowTarget) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.targets == nil {
+		f.targets = make(map[uintptr]models.WindowTarget)
+	}
+	f.targets[handle] = target
+	return nil
+}
+
+func (f *fakeDesktop) FocusWindow(handle uintptr) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.focused = append(f.focused, handle)
+	return nil
+}
+
+func (f *fakeDesktop) focusCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.focused)
+}
+
+// fakeEvents stands in for WindowWatcher.OnWindowEvent
+type fakeEvents struct {
+	mu sync.Mutex
+	fn func(models.WindowEvent)
+}
+
+func (f *fakeEvents) subscribe(fn func(models.WindowEvent)) func() {
+	f.mu.Lock()
+	f.fn = fn
+	f.mu.Unlock()
+	return func() {
+		f.mu.Lock()
+		f.fn = nil
+		f.mu.Unlock()
+	}
+}
+
+func (f *fakeEvents) send(event models.WindowEvent) {
+	f.mu.Lock()
+	fn := f.fn
+	f.mu.Unlock()
+	if fn != nil {
+		fn(event)
+	}
+}
+
+var (
+	primary   = models.MonitorInfo{Name: "primary", Primary: true, Bounds: models.Rect{Width: 1920, Height: 1080}, WorkArea: models.Rect{Width: 1920, Height: 1040}}
+	secondary = models.MonitorInfo{Name: "laptop", Bounds: models.Rect{X: 1920, Width: 1366, Height: 768}, WorkArea: models.Rect{X: 1920, Width: 1366, Height: 728}}
+
+	processes = []models.ProcessInfo{
+		{PID: 1, ImageName: "OUTLOOK.EXE", HasWindow: true, WindowTitle: "Inbox", WindowClass: "rctrl_renwnd32"},
+		{PID: 2, ImageName: "code.exe", HasWindow: true, Windows: []models.WindowEntry{
+			{Handle: 200, PID: 2, Title: "main.go"},
+			{Handle: 201, PID: 2, Title: "README.md"},
+		}},
+	}
+)
+
+// outlookScript puts Outlook on the smaller of two monitors, or on the
+// left third of the only one
+const outlookScript = `
+def main():
+    screens = monitors()
+    for w in list_windows():
+        if w.image.lower() != "outlook.exe":
+            continue
+        if len(screens) > 1:
+            small = sorted(screens, key = lambda m: m.bounds.width * m.bounds.height)[0]
+            move(w.handle, placement = "left-half", monitor = small.index)
+        else:
+            move(w.handle, placement = "left-third")
+        focus(w.handle)
+`
+
+// writeScripts creates a scripts directory with the given files
+func writeScripts(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func newTestEngine(desktop Desktop, events *fakeEvents, timeout time.Duration) *Engine {
+	if events == nil {
+		events = &fakeEvents{}
+	}
+	return NewEngine(desktop, events.subscribe, timeout, slog.New(slog.DiscardHandler))
+}
+
+func TestRunScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		monitors []models.MonitorInfo
+		want     models.WindowTarget
+	}{
+		{
+			name:     "two monitors",
+			monitors: []models.MonitorInfo{primary, secondary},
+			want:     models.WindowTarget{Placement: models.PlacementLeftHalf, Monitor: ptr(1)},
+		},
+		{
+			name:     "one monitor",
+			monitors: []models.MonitorInfo{primary},
+			want:     models.WindowTarget{Placement: models.PlacementLeftThird},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desktop := &fakeDesktop{processes: processes, monitors: tt.monitors}
+			engine := newTestEngine(desktop, nil, 0)
+			if err := engine.Load(writeScripts(t, map[string]string{"outlook.star": outlookScript})); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			if err := engine.RunScript("outlook"); err != nil {
+				t.Fatalf("RunScript: %v", err)
+			}
+			// Outlook has no window list, so its handle comes from FindWindowByPID
+			if got := desktop.targets[10]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("target = %+v, want %+v", got, tt.want)
+			}
+			if len(desktop.targets) != 1 {
+				t.Errorf("moved windows = %v, want only Outlook", desktop.targets)
+			}
+			if !reflect.DeepEqual(desktop.focused, []uintptr{10}) {
+				t.Errorf("focused = %v, want [10]", desktop.focused)
+			}
+		})
+	}
+}
+
+func TestRunScriptErrors(t *testing.T) {
+	desktop := &fakeDesktop{processes: processes, monitors: []models.MonitorInfo{primary}}
+	engine := newTestEngine(desktop, nil, 0)
+	dir := writeScripts(t, map[string]string{
+		"callbacks.star": `on("created", lambda e: None)`,
+		"empty.star":     `def main(): move(200)`,
+		"rect.star":      `def main(): move(201, rect = (1, 2, 3))`,
+		"list.star": `
+def main():
+    for w in list_windows():
+        move(w.handle, rect = w.rect)
+`,
+	})
+	if err := engine.Load(dir); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if got, want := engine.Scripts(), []string{"empty", "list", "rect"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Scripts() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+	}{
+		{"unknown script", "missing", "not found"},
+		{"no main function", "callbacks", "no main function"},
+		{"empty target", "empty", "one of rect, placement or monitor"},
+		{"short rect tuple", "rect", "want (x, y, width, height)"},
+		{"rect struct", "list", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := engine.RunScript(tt.script)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("RunScript: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("RunScript() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	want := models.Rect{X: 100, Y: 100, Width: 800, Height: 600}
+	if got := desktop.targets[200].Rect; got == nil || *got != want {
+		t.Errorf("rect of 200 = %v, want %v", got, want)
+	}
+}
+
+func TestLoadSandbox(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{"good script", "def main(): pass", ""},
+		{"load statement", `load("other.star", "x")`, "load not implemented"},
+		{"no file access", `open("secrets.txt")`, "undefined: open"},
+		{"endless loop", "while True:\n    pass", "timed out"},
+		{"unknown event", `on("resized", lambda e: None)`, `unknown event "resized"`},
+		{"syntax error", "def main(:", "test.star:1:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(&fakeDesktop{}, nil, 50*time.Millisecond)
+			err := engine.Load(writeScripts(t, map[string]string{
+				"test.star": tt.src,
+				"ok.star":   "def main(): pass",
+			}))
+
+			want := []string{"ok"}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+				want = []string{"ok", "test"}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+			// A failing script does not keep the others from loading
+			if got := engine.Scripts(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Scripts() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	t.Run("missing directory", func(t *testing.T) {
+		engine := newTestEngine(&fakeDesktop{}, nil, 0)
+		if err := engine.Load(filepath.Join(t.TempDir(), "scripts")); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got := engine.Scripts(); len(got) != 0 {
+			t.Errorf("Scripts() = %v, want none", got)
+		}
+	})
+
+	t.Run("on outside loading", func(t *testing.T) {
+		engine := newTestEngine(&fakeDesktop{}, nil, 0)
+		if err := engine.Load(writeScripts(t, map[string]string{"late.star": `def main(): on("created", main)`})); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if err := engine.RunScript("late"); err == nil || !strings.Contains(err.Error(), "only be registered while the script loads") {
+			t.Errorf("RunScript() error = %v, want a loading error", err)
+		}
+	})
+}
+
+func TestEventCallbacks(t *testing.T) {
+	desktop := &fakeDesktop{}
+	events := &fakeEvents{}
+	engine := newTestEngine(desktop, events, 50*time.Millisecond)
+	err := engine.Load(writeScripts(t, map[string]string{
+		"focus.star": `
+def on_created(event):
+    if event.handle == 1:
+        while True:
+            pass
+    focus(event.handle)
+
+on("created", on_created)
+`,
+	}))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	engine.Start()
+	defer engine.Stop()
+
+	// The first callback times out without blocking the ones after it
+	events.send(models.WindowEvent{Type: models.WindowCreated, Handle: 1})
+	events.send(models.WindowEvent{Type: models.WindowMoved, Handle: 2})
+	events.send(models.WindowEvent{Type: models.WindowCreated, Handle: 3})
+	waitFor(t, func() bool { return desktop.focusCount() == 1 })
+
+	// Events are dropped while paused
+	engine.SetPaused(true)
+	events.send(models.WindowEvent{Type: models.WindowCreated, Handle: 4})
+	engine.SetPaused(false)
+	events.send(models.WindowEvent{Type: models.WindowCreated, Handle: 5})
+	waitFor(t, func() bool { return desktop.focusCount() == 2 })
+
+	engine.Stop()
+	events.send(models.WindowEvent{Type: models.WindowCreated, Handle: 6})
+
+	desktop.mu.Lock()
+	defer desktop.mu.Unlock()
+	if want := []uintptr{3, 5}; !reflect.DeepEqual(desktop.focused, want) {
+		t.Errorf("focused = %v, want %v", desktop.focused, want)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
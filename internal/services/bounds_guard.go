@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"syscall"
+
+	apperrors "hptools/internal/errors"
+	"hptools/internal/geometry"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// boundsGuard checks requested window rects against the current monitor
+// topology, snaps them to nearby edges and moves unreachable windows back on
+// screen
+type boundsGuard struct {
+	api      *windows.API
+	resolver *windowResolver
+	logger   *slog.Logger
+
+	mu     sync.Mutex
+	policy models.BoundsPolicy
+	snap   models.SnapOptions
+}
+
+func newBoundsGuard(api *windows.API, resolver *windowResolver, logger *slog.Logger) *boundsGuard {
+	return &boundsGuard{api: api, resolver: resolver, logger: logger, policy: models.BoundsReject}
+}
+
+// SetBoundsPolicy sets how rects outside the monitors are handled
+func (b *boundsGuard) SetBoundsPolicy(policy models.BoundsPolicy) error {
+	switch policy {
+	case models.BoundsReject, models.BoundsClamp, models.BoundsAllow:
+	case "":
+		policy = models.BoundsReject
+	default:
+		return apperrors.NewConfigError(fmt.Sprintf("unknown bounds policy %q", policy), nil)
+	}
+
+	b.mu.Lock()
+	b.policy = policy
+	b.mu.Unlock()
+	return nil
+}
+
+// confine applies the bounds policy to a requested window rect of hwnd.
+// The visible frame is checked, so invisible resize borders may hang over
+// the edge of the work area.
+func (b *boundsGuard) confine(hwnd syscall.Handle, rect models.Rect) (models.Rect, error) {
+	b.mu.Lock()
+	policy := b.policy
+	b.mu.Unlock()
+
+	if policy == models.BoundsAllow {
+		return rect, nil
+	}
+
+	monitors, err := b.api.EnumMonitors()
+	if err != nil {
+		return models.Rect{}, fmt.Errorf("enumerating monitors: %w", err)
+	}
+
+	inset := frameInset(b.api, hwnd)
+	frame, err := geometry.Confine(toFrame(rect, inset), monitors, policy)
+	if errors.Is(err, geometry.ErrUnreachable) {
+		return models.Rect{}, apperrors.NewBoundsError(
+			fmt.Sprintf("%dx%d at %d,%d would be off screen", rect.Width, rect.Height, rect.X, rect.Y), err)
+	}
+	if err != nil {
+		return models.Rect{}, err
+	}
+
+	target := fromFrame(frame, inset)
+	if target != rect {
+		b.logger.Debug("Window rect clamped to work area", "requested", rect, "applied", target)
+	}
+	return target, nil
+}
+
+// RescueOffscreenWindows moves every window the user cannot reach onto the
+// primary work area and returns the windows it moved. Minimized windows and
+// windows on other virtual desktops are left alone.
+func (b *boundsGuard) RescueOffscreenWindows() ([]models.WindowEntry, error) {
+	monitors, err := b.api.EnumMonitors()
+	if err != nil {
+		return nil, fmt.Errorf("enumerating monitors: %w", err)
+	}
+	primary := geometry.Primary(monitors)
+	if primary < 0 {
+		return nil, errors.New("no monitors found")
+	}
+	work := monitors[primary].WorkArea
+
+	b.resolver.desktop.invalidate()
+	snapshot, err := b.resolver.desktop.get()
+	if err != nil {
+		return nil, err
+	}
+
+	var rescued []models.WindowEntry
+	for pid, candidates := range snapshot.byPID {
+		for _, c := range rankWindows(candidates) {
+			if c.cloak != 0 || b.api.IsIconic(c.hwnd) || geometry.Reachable(c.rect, monitors) {
+				continue
+			}
+
+			target := geometry.CenterIn(c.rect, work)
+			err := b.api.SetWindowPos(c.hwnd, target.X, target.Y, target.Width, target.Height, windows.SWP_NOZORDER|windows.SWP_NOACTIVATE)
+			if err != nil {
+				b.logger.Warn("Failed to rescue window", "pid", pid, "title", c.title, "error", err)
+				continue
+			}
+
+			rescued = append(rescued, models.WindowEntry{Handle: uintptr(c.hwnd), PID: pid, Title: c.title, Class: c.class})
+			b.logger.Info("Offscreen window rescued", "pid", pid, "title", c.title, "from", c.rect, "to", target)
+		}
+	}
+
+	if len(rescued) > 0 {
+		b.resolver.desktop.invalidate()
+	}
+	return rescued, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"sync"
+	"syscall"
+	"time"
+)
+
+// desktopSnapshotTTL is how long a desktop snapshot is reused. A refresh of
+// the process list resolves every process against the same snapshot.
+const desktopSnapshotTTL = 500 * time.Millisecond
+
+// desktopSnapshot holds every visible top-level window, indexed by owning PID
+type desktopSnapshot struct {
+	takenAt time.Time
+	byPID   map[int][]windowCandidate
+}
+
+// windowEnumerator lists and describes top-level windows. The window
+// resolver implements it with Win32 calls.
+type windowEnumerator interface {
+	// visibleWindows returns the visible top-level windows in z-order from the top
+	visibleWindows() ([]syscall.Handle, error)
+	windowPID(hwnd syscall.Handle) int
+	describe(hwnd syscall.Handle) windowCandidate
+}
+
+// desktopCache builds desktop snapshots in a single enumeration pass and
+// reuses them until they expire
+type desktopCache struct {
+	source windowEnumerator
+	ttl    time.Duration
+
+	mu       sync.Mutex
+	snapshot *desktopSnapshot
+}
+
+func newDesktopCache(source windowEnumerator, ttl time.Duration) *desktopCache {
+	return &desktopCache{source: source, ttl: ttl}
+}
+
+// get returns the cached snapshot, taking a new one if it has expired
+func (c *desktopCache) get() (*desktopSnapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.snapshot != nil && time.Since(c.snapshot.takenAt) < c.ttl {
+		return c.snapshot, nil
+	}
+
+	snapshot, err := c.take()
+	if err != nil {
+		return nil, err
+	}
+	c.snapshot = snapshot
+	return snapshot, nil
+}
+
+// invalidate drops the cached snapshot so the next lookup sees current windows
+func (c *desktopCache) invalidate() {
+	c.mu.Lock()
+	c.snapshot = nil
+	c.mu.Unlock()
+}
+
+// take enumerates all top-level windows once and groups the visible ones by PID
+func (c *desktopCache) take() (*desktopSnapshot, error) {
+	handles, err := c.source.visibleWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &desktopSnapshot{
+		takenAt: time.Now(),
+		byPID:   make(map[int][]windowCandidate),
+	}
+	for _, hwnd := range handles {
+		pid := c.source.windowPID(hwnd)
+		snapshot.byPID[pid] = append(snapshot.byPID[pid], c.source.describe(hwnd))
+	}
+	return snapshot, nil
+}
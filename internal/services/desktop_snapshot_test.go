@@ -0,0 +1,134 @@
+package services
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeDesktop is a windowEnumerator over a fixed set of windows that counts
+// the per-window queries, which are the Win32 round trips a real desktop pays
+type fakeDesktop struct {
+	handles []syscall.Handle
+	pids    map[syscall.Handle]int
+	queries int
+}
+
+// newFakeDesktop creates windowsPerProcess windows for each of processes processes
+func newFakeDesktop(processes, windowsPerProcess int) *fakeDesktop {
+	d := &fakeDesktop{pids: make(map[syscall.Handle]int)}
+	for pid := 1; pid <= processes; pid++ {
+		for range windowsPerProcess {
+			hwnd := syscall.Handle(len(d.handles) + 1)
+			d.handles = append(d.handles, hwnd)
+			d.pids[hwnd] = pid
+		}
+	}
+	return d
+}
+
+func (d *fakeDesktop) visibleWindows() ([]syscall.Handle, error) {
+	return append([]syscall.Handle(nil), d.handles...), nil
+}
+
+func (d *fakeDesktop) windowPID(hwnd syscall.Handle) int {
+	d.queries++
+	return d.pids[hwnd]
+}
+
+func (d *fakeDesktop) describe(hwnd syscall.Handle) windowCandidate {
+	d.queries++
+	return windowCandidate{hwnd: hwnd, title: "Window", class: "AppWindow"}
+}
+
+// enumerateForPID is the per-call lookup the snapshot replaced: one full
+// enumeration for every process that is resolved
+func enumerateForPID(source windowEnumerator, pid int) ([]windowCandidate, error) {
+	handles, err := source.visibleWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []windowCandidate
+	for _, hwnd := range handles {
+		if source.windowPID(hwnd) == pid {
+			found = append(found, source.describe(hwnd))
+		}
+	}
+	return found, nil
+}
+
+func TestDesktopSnapshotGroupsByPID(t *testing.T) {
+	desktop := newFakeDesktop(3, 2)
+	cache := newDesktopCache(desktop, time.Minute)
+
+	snapshot, err := cache.get()
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	for pid := 1; pid <= 3; pid++ {
+		want, _ := enumerateForPID(newFakeDesktop(3, 2), pid)
+		got := snapshot.byPID[pid]
+		if len(got) != len(want) {
+			t.Fatalf("PID %d: %d windows, want %d", pid, len(got), len(want))
+		}
+		for i := range got {
+			if got[i].hwnd != want[i].hwnd {
+				t.Errorf("PID %d window %d = %d, want %d", pid, i, got[i].hwnd, want[i].hwnd)
+			}
+		}
+	}
+
+	queries := desktop.queries
+	if _, err := cache.get(); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if desktop.queries != queries {
+		t.Errorf("cached get queried %d windows, want none", desktop.queries-queries)
+	}
+
+	cache.invalidate()
+	if _, err := cache.get(); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if desktop.queries == queries {
+		t.Error("get after invalidate did not take a new snapshot")
+	}
+}
+
+// BenchmarkDesktopSnapshot resolves the windows of every process once, as a
+// process list refresh does, with a shared snapshot and with the old
+// per-call enumeration
+func BenchmarkDesktopSnapshot(b *testing.B) {
+	for _, size := range []struct{ processes, windows int }{{20, 3}, {100, 5}, {300, 4}} {
+		name := fmt.Sprintf("%dprocs-%dwins", size.processes, size.windows)
+
+		b.Run(name+"/snapshot", func(b *testing.B) {
+			desktop := newFakeDesktop(size.processes, size.windows)
+			for b.Loop() {
+				cache := newDesktopCache(desktop, time.Minute)
+				for pid := 1; pid <= size.processes; pid++ {
+					snapshot, err := cache.get()
+					if err != nil {
+						b.Fatal(err)
+					}
+					_ = snapshot.byPID[pid]
+				}
+			}
+			b.ReportMetric(float64(desktop.queries)/float64(b.N), "queries/op")
+		})
+
+		b.Run(name+"/per-call", func(b *testing.B) {
+			desktop := newFakeDesktop(size.processes, size.windows)
+			for b.Loop() {
+				for pid := 1; pid <= size.processes; pid++ {
+					if _, err := enumerateForPID(desktop, pid); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			b.ReportMetric(float64(desktop.queries)/float64(b.N), "queries/op")
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"hptools/internal/geometry"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// displaySettleDelay is how long the monitor topology must stay quiet before
+// it is fingerprinted. A dock or undock raises a burst of notifications, and
+// Windows rearranges windows itself after the last one.
+const displaySettleDelay = 3 * time.Second
+
+type displayWatcher struct {
+	api     *windows.API
+	layouts LayoutManager
+	logger  *slog.Logger
+
+	mu           sync.Mutex
+	associations []models.DisplayLayout
+	fingerprint  string
+	settle       *time.Timer
+	stop         func()
+}
+
+// NewDisplayWatcher creates a watcher that applies the layout associated with
+// a monitor setup whenever that setup becomes active
+func NewDisplayWatcher(api *windows.API, layouts LayoutManager, associations []models.DisplayLayout, logger *slog.Logger) DisplayWatcher {
+	return &displayWatcher{
+		api:          api,
+		layouts:      layouts,
+		logger:       logger,
+		associations: associations,
+	}
+}
+
+// Start records the current monitor setup and begins watching for changes.
+// The layout of the current setup is not applied on start.
+func (d *displayWatcher) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop != nil {
+		return nil
+	}
+
+	fingerprint, err := d.currentFingerprint()
+	if err != nil {
+		return err
+	}
+	d.fingerprint = fingerprint
+
+	stop, err := d.api.WatchDisplayChanges(d.scheduleCheck)
+	if err != nil {
+		return fmt.Errorf("watching display changes: %w", err)
+	}
+	d.stop = stop
+
+	d.logger.Info("Display watcher started", "fingerprint", fingerprint)
+	return nil
+}
+
+// Stop ends watching and cancels a pending check
+func (d *displayWatcher) Stop() {
+	d.mu.Lock()
+	stop := d.stop
+	d.stop = nil
+	if d.settle != nil {
+		d.settle.Stop()
+		d.settle = nil
+	}
+	d.mu.Unlock()
+
+	// Called outside the lock; the watcher thread may be waiting for it in scheduleCheck
+	if stop != nil {
+		stop()
+	}
+}
+
+// SetDisplayLayouts replaces the monitor setup to layout associations
+func (d *displayWatcher) SetDisplayLayouts(associations []models.DisplayLayout) {
+	d.mu.Lock()
+	d.associations = associations
+	d.mu.Unlock()
+}
+
+// CurrentFingerprint returns the fingerprint of the active monitor setup,
+// as used in the display layout associations
+func (d *displayWatcher) CurrentFingerprint() (string, error) {
+	return d.currentFingerprint()
+}
+
+func (d *displayWatcher) currentFingerprint() (string, error) {
+	monitors, err := d.api.EnumMonitors()
+	if err != nil {
+		return "", fmt.Errorf("enumerating monitors: %w", err)
+	}
+	return geometry.Fingerprint(monitors), nil
+}
+
+// scheduleCheck (re)starts the settle timer; it runs on the notification thread
+func (d *displayWatcher) scheduleCheck() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop == nil {
+		return
+	}
+	if d.settle != nil {
+		d.settle.Reset(displaySettleDelay)
+		return
+	}
+	d.settle = time.AfterFunc(displaySettleDelay, d.check)
+}
+
+// check fingerprints the settled monitor setup and applies its layout if the
+// setup changed since the last check
+func (d *displayWatcher) check() {
+	fingerprint, err := d.currentFingerprint()
+	if err != nil {
+		d.logger.Error("Failed to fingerprint displays", "error", err)
+		return
+	}
+
+	d.mu.Lock()
+	changed := fingerprint != d.fingerprint
+	d.fingerprint = fingerprint
+	layout, ok := d.layoutFor(fingerprint)
+	d.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	d.logger.Info("Display setup changed", "fingerprint", fingerprint)
+	if !ok {
+		return
+	}
+
+	if err := d.layouts.ApplyLayout(layout); err != nil {
+		d.logger.Error("Failed to apply display layout", "layout", layout, "fingerprint", fingerprint, "error", err)
+		return
+	}
+	d.logger.Info("Display layout applied", "layout", layout)
+}
+
+// layoutFor returns the layout associated with a fingerprint; d.mu must be held
+func (d *displayWatcher) layoutFor(fingerprint string) (string, bool) {
+	for _, a := range d.associations {
+		if a.Fingerprint == fingerprint {
+			return a.Layout, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	apperrors "hptools/internal/errors"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// focusPollInterval is how often the cursor position is sampled
+const focusPollInterval = 50 * time.Millisecond
+
+type focusFollower struct {
+	api      *windows.API
+	resolver *windowResolver
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	options models.FocusFollowOptions
+	paused  bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// hoverState tracks the window under the cursor between polls
+type hoverState struct {
+	hwnd     syscall.Handle
+	since    time.Time
+	eligible bool
+	focused  bool
+	raised   bool
+}
+
+// NewFocusFollower creates a focus-follows-mouse controller; it is idle until
+// enabled with SetOptions
+func NewFocusFollower(api *windows.API, logger *slog.Logger) FocusFollower {
+	return &focusFollower{
+		api:      api,
+		resolver: newWindowResolver(api),
+		logger:   logger,
+	}
+}
+
+// SetOptions validates and applies the options, starting or stopping the
+// cursor polling as needed. On error the current options are kept.
+func (f *focusFollower) SetOptions(options models.FocusFollowOptions) error {
+	for i, glob := range options.ExcludeProcesses {
+		if _, err := path.Match(strings.ToLower(glob), ""); err != nil {
+			return apperrors.NewConfigError(fmt.Sprintf("invalid exclude pattern %d", i), err)
+		}
+	}
+
+	f.mu.Lock()
+	f.options = options
+	stop, done := f.syncLocked()
+	f.mu.Unlock()
+
+	f.waitPoller(stop, done)
+	return nil
+}
+
+// SetPaused suspends or resumes the cursor polling without changing the
+// options
+func (f *focusFollower) SetPaused(paused bool) error {
+	f.mu.Lock()
+	f.paused = paused
+	stop, done := f.syncLocked()
+	f.mu.Unlock()
+
+	f.waitPoller(stop, done)
+	return nil
+}
+
+// syncLocked starts or stops the cursor polling to match the options and
+// pause state. A stopped poller's channels are returned for waitPoller.
+// f.mu must be held.
+func (f *focusFollower) syncLocked() (stop, done chan struct{}) {
+	active := f.options.Enabled && !f.paused
+	running := f.stop != nil
+	switch {
+	case active && !running:
+		f.stop = make(chan struct{})
+		f.done = make(chan struct{})
+		go f.poll(f.stop, f.done)
+		f.logger.Info("Focus follows mouse enabled", "delayMs", f.options.DelayMS, "autoRaise", f.options.AutoRaise)
+	case !active && running:
+		stop, done = f.stop, f.done
+		f.stop, f.done = nil, nil
+	}
+	return stop, done
+}
+
+// waitPoller stops a poller returned by syncLocked. The poller takes f.mu for
+// every step, so it is waited for unlocked.
+func (f *focusFollower) waitPoller(stop, done chan struct{}) {
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+	f.logger.Info("Focus follows mouse disabled")
+}
+
+// Stop stops the cursor polling
+func (f *focusFollower) Stop() {
+	f.mu.Lock()
+	stop, done := f.stop, f.done
+	f.stop, f.done = nil, nil
+	f.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// poll samples the window under the cursor until stop is closed
+func (f *focusFollower) poll(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(focusPollInterval)
+	defer ticker.Stop()
+
+	var hover hoverState
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			f.mu.Lock()
+			options := f.options
+			f.mu.Unlock()
+			f.step(&hover, options)
+		}
+	}
+}
+
+// step handles one cursor sample
+func (f *focusFollower) step(hover *hoverState, options models.FocusFollowOptions) {
+	// Never interrupt drags, selections, open menus or move/size loops
+	if f.api.IsMouseButtonDown() || f.api.IsForegroundBusy() {
+		*hover = hoverState{}
+		return
+	}
+
+	x, y, err := f.api.GetCursorPos()
+	if err != nil {
+		return
+	}
+	hwnd := f.api.GetRootWindow(f.api.WindowFromPoint(x, y))
+	if hwnd != hover.hwnd {
+		*hover = hoverState{hwnd: hwnd, since: time.Now(), eligible: hwnd != 0 && f.eligible(hwnd, options)}
+	}
+	if !hover.eligible {
+		return
+	}
+
+	elapsed := time.Since(hover.since)
+	delay := time.Duration(options.DelayMS) * time.Millisecond
+	raiseDelay := time.Duration(options.RaiseDelayMS) * time.Millisecond
+
+	if !hover.focused && elapsed >= delay {
+		hover.focused = true
+		if f.api.GetForegroundWindow() == hwnd {
+			hover.raised = true
+			return
+		}
+
+		above := f.api.GetWindowAbove(hwnd)
+		if err := f.api.FocusWindow(hwnd); err != nil {
+			f.logger.Debug("Failed to focus window under cursor", "hwnd", hwnd, "error", err)
+			return
+		}
+		if options.AutoRaise && raiseDelay == 0 {
+			hover.raised = true
+		} else if above != 0 {
+			// Focusing raised the window; put it back where it was
+			if err := f.api.SetZOrder(hwnd, above); err != nil {
+				f.logger.Debug("Failed to restore z-order", "hwnd", hwnd, "error", err)
+			}
+		}
+	}
+
+	if options.AutoRaise && hover.focused && !hover.raised && elapsed >= delay+raiseDelay {
+		hover.raised = true
+		if err := f.api.SetZOrder(hwnd, windows.HWND_TOP); err != nil {
+			f.logger.Debug("Failed to raise window under cursor", "hwnd", hwnd, "error", err)
+		}
+	}
+}
+
+// eligible reports whether a top-level window may be focused: it must be a
+// window that could be a main window, accept activation and not belong to an
+// excluded process. This leaves out the taskbar, the desktop, menus, tooltips
+// and other popups.
+func (f *focusFollower) eligible(hwnd syscall.Handle, options models.FocusFollowOptions) bool {
+	c := f.resolver.describe(hwnd)
+	if scoreWindow(c) < 0 || c.exStyle&windows.WS_EX_NOACTIVATE != 0 {
+		return false
+	}
+	if len(options.ExcludeProcesses) == 0 {
+		return true
+	}
+
+	image := strings.ToLower(processImageName(f.api, f.resolver.windowPID(hwnd)))
+	for _, glob := range options.ExcludeProcesses {
+		if ok, _ := path.Match(strings.ToLower(glob), image); ok {
+			return false
+		}
+	}
+	return true
+}
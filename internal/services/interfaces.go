@@ -5,8 +5,19 @@ import "hptools/internal/models"
 // ProcessManager defines the interface for process management operations
 type ProcessManager interface {
 	GetApplicationProcesses() ([]models.ProcessInfo, error)
+	GetProcessesForProfile(profile string) ([]models.ProcessInfo, error)
 	GetAllProcessesWithWindows() ([]models.ProcessInfo, error)
 	IsApplication(proc models.ProcessInfo) bool
+	SetFilterProfiles(profiles []models.FilterProfile, defaultProfile string) error
+	ListFilterProfiles() []string
+	GetProcessDetails(pid int) (*models.ProcessDetails, error)
+	GetProcessIcon(pid int) ([]byte, error)
+	// Process actions only report what they would do when dryRun is set
+	EndProcess(pid int, dryRun bool) (*models.ProcessActionResult, error)
+	SuspendProcess(pid int, dryRun bool) (*models.ProcessActionResult, error)
+	ResumeProcess(pid int, dryRun bool) (*models.ProcessActionResult, error)
+	SetProcessPriority(pid int, priority models.PriorityClass, dryRun bool) (*models.ProcessActionResult, error)
+	SetProcessAffinity(pid int, mask uint64, dryRun bool) (*models.ProcessActionResult, error)
 }
 
 // WindowManager defines the interface for window management operations
@@ -15,10 +26,151 @@ type WindowManager interface {
 	SetWindowPosition(pid int, x, y, width, height int) error
 	GetWindowInfo(pid int) (*models.WindowInfo, error)
 	FindWindowByPID(pid int) (uintptr, error)
+	ApplyPlacement(pid int, placement models.Placement) error
+	AdjustWindow(pid int, adjust models.WindowAdjustment) error
+	CyclePlacement(pid int, cycle []models.Placement) error
+	SwapWindows(pidA, pidB int) error
+	GetMonitors() ([]models.MonitorInfo, error)
+	GetVirtualDesktops() ([]models.VirtualDesktop, error)
+	// The ByHandle variants act on a specific window rather than the main
+	// window of its process, e.g. one of several windows split by AppUserModelID
+	SetWindowSizeByHandle(handle uintptr, width, height int) error
+	SetWindowPositionByHandle(handle uintptr, x, y, width, height int) error
+	GetWindowInfoByHandle(handle uintptr) (*models.WindowInfo, error)
+	ApplyPlacementByHandle(handle uintptr, placement models.Placement) error
+	AdjustWindowByHandle(handle uintptr, adjust models.WindowAdjustment) error
+	RecentWindows() []models.RecentWindow
+	OnRecentWindowsChanged(fn func()) func()
 }
 
 // WindowService combines both process and window management
 type WindowService interface {
 	ProcessManager
 	WindowManager
+	WindowIdentifier
+	BoundsGuard
+}
+
+// BoundsGuard defines the interface for keeping windows on screen and aligned
+type BoundsGuard interface {
+	SetBoundsPolicy(policy models.BoundsPolicy) error
+	SetSnapOptions(options models.SnapOptions) error
+	RescueOffscreenWindows() ([]models.WindowEntry, error)
+}
+
+// WindowIdentifier defines the interface for confirming which window an action targets
+type WindowIdentifier interface {
+	// HighlightWindow briefly draws a border around the main window of a process
+	HighlightWindow(pid int) error
+	// HighlightWindowByHandle briefly draws a border around a specific window
+	HighlightWindowByHandle(handle uintptr) error
+	// PickWindow waits for the user to click a window and returns it
+	PickWindow() (*models.WindowEntry, error)
+}
+
+// LayoutManager defines the interface for saved layout operations
+type LayoutManager interface {
+	ListLayouts() []models.Layout
+	ApplyLayout(name string) error
+	SetLayouts(layouts []models.Layout)
+	OnLayoutsChanged(fn func()) func()
+}
+
+// WorkspaceManager defines the interface for workspace operations
+type WorkspaceManager interface {
+	ListWorkspaces() []models.Workspace
+	RunWorkspace(name string) (*models.WorkspaceResult, error)
+	SetWorkspaces(workspaces []models.Workspace)
+	OnWorkspacesChanged(fn func()) func()
+}
+
+// TriggerManager defines the interface for window title and class triggers
+type TriggerManager interface {
+	Start()
+	Stop()
+	ListTriggers() []models.Trigger
+	SetTriggers(triggers []models.Trigger) error
+	// Notifications returns the recent notifications of "notify" actions
+	Notifications() []models.TriggerNotification
+	SetPaused(paused bool) error
+}
+
+// FocusFollower defines the interface for focus-follows-mouse
+type FocusFollower interface {
+	SetOptions(options models.FocusFollowOptions) error
+	SetPaused(paused bool) error
+	Stop()
+}
+
+// WindowDragger defines the interface for moving and resizing windows with Alt+drag
+type WindowDragger interface {
+	SetOptions(options models.DragOptions) error
+	SetPaused(paused bool) error
+	Stop()
+}
+
+// Tiler defines the interface for automatic tiling of a monitor
+type Tiler interface {
+	SetOptions(options models.TilingOptions) error
+	Options() models.TilingOptions
+	OnTilingChanged(fn func()) func()
+	SetLayout(layout models.TilingLayout) error
+	AdjustMasterRatio(delta float64) error
+	AdjustGap(delta int) error
+	Retile() error
+	SetPaused(paused bool) error
+	Stop()
+}
+
+// ScriptEngine defines the interface for user scripts
+type ScriptEngine interface {
+	// Load replaces the loaded scripts with those of a directory
+	Load(dir string) error
+	// Scripts lists the scripts that can be run from the menu
+	Scripts() []string
+	RunScript(name string) error
+	OnScriptsChanged(fn func()) func()
+	SetPaused(paused bool) error
+	Start()
+	Stop()
+}
+
+// RulesPauser suspends and resumes the automatic window rules together:
+// triggers, focus follows mouse, window dragging, auto tiling and script
+// callbacks
+type RulesPauser interface {
+	Paused() bool
+	SetPaused(paused bool) error
+}
+
+// WindowWatcher reports changes to top-level windows as they happen
+type WindowWatcher interface {
+	Start() error
+	Stop()
+	OnWindowEvent(fn func(models.WindowEvent)) func()
+}
+
+// ThumbnailManager defines the interface for window preview images
+type ThumbnailManager interface {
+	// GetThumbnail returns a PNG of the window scaled to fit maxWidth x maxHeight
+	GetThumbnail(handle uintptr, maxWidth, maxHeight int) ([]byte, error)
+}
+
+// Launcher defines the interface for starting applications at a target placement
+type Launcher interface {
+	Launch(request models.LaunchRequest) (*models.LaunchResult, error)
+}
+
+// MetricsSampler defines the interface for sampled process resource usage
+type MetricsSampler interface {
+	Top(key models.MetricKey, n int) ([]models.ProcessMetrics, error)
+	History(pid int) []models.ProcessMetrics
+}
+
+// DisplayWatcher defines the interface for reacting to monitor setup changes
+type DisplayWatcher interface {
+	Start() error
+	Stop()
+	SetDisplayLayouts(associations []models.DisplayLayout)
+	CurrentFingerprint() (string, error)
 }
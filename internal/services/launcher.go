@@ -0,0 +1,218 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	apperrors "hptools/internal/errors"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+const (
+	// appsFolderPrefix starts shell URIs that launch Store apps by AppUserModelID
+	appsFolderPrefix = `shell:appsfolder\`
+	// defaultLaunchTimeout is how long Launch waits for a window by default
+	defaultLaunchTimeout = 15 * time.Second
+	// launchPollInterval is how often Launch looks for the new window
+	launchPollInterval = 250 * time.Millisecond
+)
+
+type launcher struct {
+	api      *windows.API
+	windows  WindowManager
+	resolver *windowResolver
+	logger   *slog.Logger
+}
+
+// NewLauncher creates a launcher that places new windows through windows
+func NewLauncher(api *windows.API, windows WindowManager, logger *slog.Logger) Launcher {
+	return &launcher{
+		api:      api,
+		windows:  windows,
+		resolver: newWindowResolver(api),
+		logger:   logger,
+	}
+}
+
+// Launch starts an application, waits for its main window and moves it to
+// the requested target
+func (l *launcher) Launch(request models.LaunchRequest) (*models.LaunchResult, error) {
+	timeout := defaultLaunchTimeout
+	if request.TimeoutMS > 0 {
+		timeout = time.Duration(request.TimeoutMS) * time.Millisecond
+	}
+
+	before, err := l.existingWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	identity := l.identify(request.Path)
+
+	pid, err := l.api.ShellExecute(request.Path, request.Args, request.WorkingDir)
+	if err != nil {
+		return nil, apperrors.NewProcessError(fmt.Sprintf("failed to start %s", request.Path), err)
+	}
+	l.logger.Info("Application started", "path", request.Path, "pid", pid)
+
+	if pid == 0 && identity == (launchIdentity{}) {
+		return nil, apperrors.NewProcessError(fmt.Sprintf("cannot tell which window belongs to %s", request.Path), nil)
+	}
+
+	result, err := l.waitForWindow(pid, identity, before, timeout)
+	if err != nil {
+		return nil, apperrors.NewProcessError(fmt.Sprintf("no window of %s appeared", request.Path), err)
+	}
+
+	if target := request.WindowTarget; !target.Empty() {
+		if err := applyWindowTarget(l.windows, result.Handle, target); err != nil {
+			return result, fmt.Errorf("placing launched window: %w", err)
+		}
+	}
+
+	l.logger.Info("Launched window placed", "path", request.Path, "pid", result.PID, "title", result.Title)
+	return result, nil
+}
+
+// launchIdentity is what identifies the windows of a launched application
+// once the started process is gone: its executable name or, for Store apps
+// started through shell:AppsFolder, its AppUserModelID
+type launchIdentity struct {
+	image string
+	appID string
+}
+
+// identify derives the identity of the application path starts. Shortcuts
+// are resolved to their target; other files are identified by the process
+// the shell starts for them, once it is known.
+func (l *launcher) identify(path string) launchIdentity {
+	if len(path) > len(appsFolderPrefix) && strings.EqualFold(path[:len(appsFolderPrefix)], appsFolderPrefix) {
+		return launchIdentity{appID: path[len(appsFolderPrefix):]}
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".exe":
+		return launchIdentity{image: filepath.Base(path)}
+	case ".lnk":
+		target, err := l.api.ShortcutTarget(path)
+		if err != nil {
+			l.logger.Debug("Shortcut target unknown", "path", path, "error", err)
+			return launchIdentity{}
+		}
+		if strings.EqualFold(filepath.Ext(target), ".exe") {
+			return launchIdentity{image: filepath.Base(target)}
+		}
+	}
+	return launchIdentity{}
+}
+
+// matches reports whether a window with the given AppUserModelID, owned by
+// a process with the given executable name, belongs to the launched application
+func (id launchIdentity) matches(image, appID string) bool {
+	if id.appID != "" {
+		return strings.EqualFold(appID, id.appID)
+	}
+	return id.image != "" && strings.EqualFold(image, id.image)
+}
+
+// existingWindows returns the top-level windows that exist before a launch
+func (l *launcher) existingWindows() (map[syscall.Handle]bool, error) {
+	handles, err := l.api.EnumWindows()
+	if err != nil {
+		return nil, fmt.Errorf("enumerating windows: %w", err)
+	}
+	existing := make(map[syscall.Handle]bool, len(handles))
+	for _, hwnd := range handles {
+		existing[hwnd] = true
+	}
+	return existing, nil
+}
+
+// waitForWindow polls for a main window that did not exist before the launch.
+// Launcher stubs start the real application as a child and exit, so windows
+// of any descendant of pid count. Once the started process has exited, or if
+// the shell handed the target to a running application (pid 0), only new
+// windows matching identity are accepted.
+func (l *launcher) waitForWindow(pid int, identity launchIdentity, before map[syscall.Handle]bool, timeout time.Duration) (*models.LaunchResult, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		l.resolver.desktop.invalidate()
+		snapshot, err := l.resolver.desktop.get()
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := l.api.GetProcessEntries()
+		if err != nil {
+			return nil, fmt.Errorf("listing processes: %w", err)
+		}
+
+		var family map[int]bool
+		if pid != 0 {
+			// Documents and scripts are identified by the process started for them
+			if started, alive := entries[pid]; alive && identity == (launchIdentity{}) {
+				identity.image = started.ExeFile
+			}
+			family = processFamily(entries, pid)
+		}
+
+		for owner, candidates := range snapshot.byPID {
+			if family != nil && !family[owner] {
+				continue
+			}
+			for _, c := range rankWindows(candidates) {
+				if before[c.hwnd] {
+					continue
+				}
+				if family == nil {
+					appID, _ := l.resolver.appID(c.hwnd)
+					if !identity.matches(entries[owner].ExeFile, appID) {
+						continue
+					}
+				}
+				return &models.LaunchResult{PID: owner, Handle: uintptr(c.hwnd), Title: c.title}, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		time.Sleep(launchPollInterval)
+	}
+}
+
+// processFamily returns pid and all its descendants among entries. It returns
+// nil once pid has exited and left no descendants.
+func processFamily(entries map[int]windows.ProcessEntry, pid int) map[int]bool {
+	children := make(map[int][]int)
+	for _, e := range entries {
+		if e.ParentPID != e.PID {
+			children[e.ParentPID] = append(children[e.ParentPID], e.PID)
+		}
+	}
+
+	family := map[int]bool{}
+	queue := []int{pid}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if family[p] {
+			continue
+		}
+		if _, alive := entries[p]; alive {
+			family[p] = true
+		}
+		// Descendants of an exited stub still point at its PID
+		queue = append(queue, children[p]...)
+	}
+
+	if len(family) == 0 {
+		return nil
+	}
+	return family
+}
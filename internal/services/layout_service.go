@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"hptools/internal/events"
+	"hptools/internal/models"
+)
+
+type layoutService struct {
+	windows  WindowService
+	launcher Launcher
+	logger   *slog.Logger
+
+	mu      sync.RWMutex
+	layouts []models.Layout
+	changed *events.Notifier
+}
+
+// NewLayoutService creates a new layout manager for the given saved layouts.
+// launcher starts applications that are missing and have a launch target.
+func NewLayoutService(windows WindowService, launcher Launcher, layouts []models.Layout, logger *slog.Logger) LayoutManager {
+	return &layoutService{
+		windows:  windows,
+		launcher: launcher,
+		logger:   logger,
+		layouts:  layouts,
+		changed:  events.NewNotifier(),
+	}
+}
+
+// ListLayouts returns all saved layouts
+func (l *layoutService) ListLayouts() []models.Layout {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]models.Layout(nil), l.layouts...)
+}
+
+// SetLayouts replaces the saved layouts, e.g. after a config reload
+func (l *layoutService) SetLayouts(layouts []models.Layout) {
+	l.mu.Lock()
+	l.layouts = layouts
+	l.mu.Unlock()
+
+	l.changed.Notify()
+}
+
+// OnLayoutsChanged registers a listener called when the layouts change.
+// It returns a function that removes the listener.
+func (l *layoutService) OnLayoutsChanged(fn func()) func() {
+	return l.changed.Subscribe(fn)
+}
+
+// ApplyLayout moves the windows of running applications according to a saved
+// layout and starts missing applications that have a launch target
+func (l *layoutService) ApplyLayout(name string) error {
+	layout, ok := l.findLayout(name)
+	if !ok {
+		return fmt.Errorf("layout %q not found", name)
+	}
+
+	processes, err := l.windows.GetApplicationProcesses()
+	if err != nil {
+		return fmt.Errorf("getting application processes: %w", err)
+	}
+
+	byImage := make(map[string]models.ProcessInfo, len(processes))
+	for _, proc := range processes {
+		byImage[strings.ToLower(proc.ImageName)] = proc
+	}
+
+	var errs []error
+	for _, entry := range layout.Windows {
+		proc, ok := byImage[strings.ToLower(entry.ImageName)]
+		if !ok {
+			if entry.Launch == nil {
+				l.logger.Debug("Layout window not running", "layout", name, "imageName", entry.ImageName)
+				continue
+			}
+			request := models.LaunchRequest{LaunchTarget: *entry.Launch, WindowTarget: entry.WindowTarget}
+			if _, err := l.launcher.Launch(request); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", entry.ImageName, err))
+			}
+			continue
+		}
+
+		hwnd, err := mainWindowOf(l.windows, proc)
+		if err == nil {
+			err = applyWindowTarget(l.windows, hwnd, entry.WindowTarget)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.ImageName, err))
+		}
+	}
+
+	l.logger.Info("Layout applied", "layout", name, "failures", len(errs))
+	return errors.Join(errs...)
+}
+
+// findLayout looks up a layout by name
+func (l *layoutService) findLayout(name string) (models.Layout, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, layout := range l.layouts {
+		if layout.Name == name {
+			return layout, true
+		}
+	}
+	return models.Layout{}, false
+}
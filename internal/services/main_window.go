@@ -0,0 +1,264 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// ignoredWindowClasses are helper windows that are never a main window
+var ignoredWindowClasses = map[string]bool{
+	"ime":                        true,
+	"msctfime ui":                true,
+	"progman":                    true,
+	"workerw":                    true,
+	"shell_traywnd":              true,
+	"shell_secondarytraywnd":     true,
+	"windows.ui.core.corewindow": true,
+}
+
+const (
+	// frameHostClass is the class of ApplicationFrameHost.exe windows hosting UWP apps
+	frameHostClass = "ApplicationFrameWindow"
+	// coreWindowClass is the class of the UWP app window inside a frame host window
+	coreWindowClass = "Windows.UI.Core.CoreWindow"
+)
+
+// minMainWindowSize is the smallest width or height a main window can have
+const minMainWindowSize = 100
+
+// windowCandidate holds the attributes used to pick the main window of a process
+type windowCandidate struct {
+	hwnd    syscall.Handle
+	title   string
+	class   string
+	owner   syscall.Handle
+	style   uint32
+	exStyle uint32
+	cloak   uint32
+	rect    models.Rect
+	// desktopID is set for windows cloaked because they are on another virtual desktop
+	desktopID string
+}
+
+// scoreWindow rates how likely a window is the main window of its process.
+// A negative score means the window must never be picked.
+func scoreWindow(c windowCandidate) int {
+	if ignoredWindowClasses[strings.ToLower(c.class)] {
+		return -1
+	}
+	// Cloaked windows are hidden, unless the shell cloaked them for another virtual desktop
+	if c.cloak != 0 && c.desktopID == "" {
+		return -1
+	}
+	if c.title == "" || c.title == "Default IME" || c.title == "MSCTFIME UI" {
+		return -1
+	}
+
+	appWindow := c.exStyle&windows.WS_EX_APPWINDOW != 0
+	// Tool windows and owned windows only show in the taskbar when forced with WS_EX_APPWINDOW
+	if !appWindow && (c.exStyle&windows.WS_EX_TOOLWINDOW != 0 || c.owner != 0) {
+		return -1
+	}
+
+	score := 10
+	if appWindow {
+		score += 20
+	}
+	if c.style&windows.WS_CAPTION == windows.WS_CAPTION {
+		score += 20
+	} else if c.style&windows.WS_POPUP != 0 {
+		score -= 5 // Captionless popups are usually splash screens
+	}
+	if c.exStyle&windows.WS_EX_NOACTIVATE != 0 {
+		score -= 5
+	}
+	if c.desktopID != "" {
+		score -= 5 // Prefer windows on the current desktop
+	}
+
+	if c.rect.Width < minMainWindowSize || c.rect.Height < minMainWindowSize {
+		score -= 5
+	} else {
+		// Larger windows are more likely the main window; capped so size never dominates
+		score += min(c.rect.Width*c.rect.Height/100000, 10)
+	}
+
+	return score
+}
+
+// rankWindows returns the candidates that can be a main window, best first.
+// Ties keep the enumeration order, which is the z-order from the top.
+func rankWindows(candidates []windowCandidate) []windowCandidate {
+	type scored struct {
+		candidate windowCandidate
+		score     int
+	}
+
+	var eligible []scored
+	for _, c := range candidates {
+		if s := scoreWindow(c); s >= 0 {
+			eligible = append(eligible, scored{c, s})
+		}
+	}
+
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return eligible[i].score > eligible[j].score
+	})
+
+	ranked := make([]windowCandidate, len(eligible))
+	for i, e := range eligible {
+		ranked[i] = e.candidate
+	}
+	return ranked
+}
+
+// windowResolver finds the main window of a process; it is shared by the
+// process and window managers so both agree on which window is the target
+type windowResolver struct {
+	api     *windows.API
+	desktop *desktopCache
+
+	// appIDs caches window AppUserModelIDs; reading one initializes COM
+	appIDMu sync.Mutex
+	appIDs  map[syscall.Handle]string
+}
+
+func newWindowResolver(api *windows.API) *windowResolver {
+	r := &windowResolver{api: api, appIDs: make(map[syscall.Handle]string)}
+	r.desktop = newDesktopCache(r, desktopSnapshotTTL)
+	return r
+}
+
+// visibleWindows enumerates the visible top-level windows in z-order and
+// forgets cached data of windows that have been closed
+func (r *windowResolver) visibleWindows() ([]syscall.Handle, error) {
+	handles, err := r.api.EnumWindows()
+	if err != nil {
+		return nil, fmt.Errorf("enumerating windows: %w", err)
+	}
+	r.retainAppIDs(handles)
+
+	visible := handles[:0]
+	for _, hwnd := range handles {
+		if r.api.IsWindowVisible(hwnd) {
+			visible = append(visible, hwnd)
+		}
+	}
+	return visible, nil
+}
+
+// describe reads the attributes of a window
+func (r *windowResolver) describe(hwnd syscall.Handle) windowCandidate {
+	c := windowCandidate{
+		hwnd:    hwnd,
+		title:   r.api.GetWindowText(hwnd),
+		class:   r.api.GetClassName(hwnd),
+		owner:   r.api.GetOwner(hwnd),
+		style:   r.api.GetWindowStyle(hwnd),
+		exStyle: r.api.GetWindowExStyle(hwnd),
+		cloak:   r.api.GetWindowCloak(hwnd),
+	}
+	if c.cloak == windows.DWM_CLOAKED_SHELL {
+		// Errors leave desktopID empty, which treats the window as hidden
+		c.desktopID, _ = r.api.GetWindowDesktopID(hwnd)
+	}
+	if rect, err := r.api.GetWindowRect(hwnd); err == nil {
+		c.rect = rect.ToRect()
+	}
+	return c
+}
+
+// appID returns the AppUserModelID of a window, cached per handle.
+// Failures are not cached so they are retried on the next lookup.
+func (r *windowResolver) appID(hwnd syscall.Handle) (string, error) {
+	r.appIDMu.Lock()
+	appID, ok := r.appIDs[hwnd]
+	r.appIDMu.Unlock()
+	if ok {
+		return appID, nil
+	}
+
+	appID, err := r.api.GetWindowAppUserModelID(hwnd)
+	if err != nil {
+		return "", err
+	}
+
+	r.appIDMu.Lock()
+	r.appIDs[hwnd] = appID
+	r.appIDMu.Unlock()
+	return appID, nil
+}
+
+// retainAppIDs drops cached AppUserModelIDs of windows that no longer exist,
+// so a reused handle does not inherit the ID of a closed window
+func (r *windowResolver) retainAppIDs(handles []syscall.Handle) {
+	live := make(map[syscall.Handle]bool, len(handles))
+	for _, hwnd := range handles {
+		live[hwnd] = true
+	}
+
+	r.appIDMu.Lock()
+	for hwnd := range r.appIDs {
+		if !live[hwnd] {
+			delete(r.appIDs, hwnd)
+		}
+	}
+	r.appIDMu.Unlock()
+}
+
+// windowPID returns the process that owns a window. Frame host windows are
+// attributed to the UWP app they host rather than ApplicationFrameHost.exe.
+func (r *windowResolver) windowPID(hwnd syscall.Handle) int {
+	pid := int(r.api.GetWindowThreadProcessId(hwnd))
+	if r.api.GetClassName(hwnd) != frameHostClass {
+		return pid
+	}
+
+	for _, child := range r.api.EnumChildWindows(hwnd) {
+		if r.api.GetClassName(child) != coreWindowClass {
+			continue
+		}
+		if hosted := int(r.api.GetWindowThreadProcessId(child)); hosted != pid {
+			return hosted
+		}
+	}
+	return pid
+}
+
+// candidates returns all visible top-level windows of a process in z-order
+func (r *windowResolver) candidates(targetPID int) ([]windowCandidate, error) {
+	snapshot, err := r.desktop.get()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.byPID[targetPID], nil
+}
+
+// appWindows returns the windows of a process that can be a main window, best first
+func (r *windowResolver) appWindows(pid int) ([]windowCandidate, error) {
+	candidates, err := r.candidates(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := rankWindows(candidates)
+	if len(ranked) == 0 {
+		return nil, fmt.Errorf("no visible window found for PID %d", pid)
+	}
+	return ranked, nil
+}
+
+// mainWindow resolves the main window of a process
+func (r *windowResolver) mainWindow(pid int) (windowCandidate, error) {
+	ranked, err := r.appWindows(pid)
+	if err != nil {
+		return windowCandidate{}, err
+	}
+	return ranked[0], nil
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// mainWindowFixture is a captioned, normally sized top-level window
+func mainWindowFixture(hwnd syscall.Handle) windowCandidate {
+	return windowCandidate{
+		hwnd:  hwnd,
+		title: "Document - Editor",
+		class: "EditorMainWindow",
+		style: windows.WS_CAPTION,
+		rect:  models.Rect{X: 100, Y: 100, Width: 1200, Height: 800},
+	}
+}
+
+func TestScoreWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(c *windowCandidate)
+		eligible bool
+	}{
+		{"main window", func(c *windowCandidate) {}, true},
+		{"empty title", func(c *windowCandidate) { c.title = "" }, false},
+		{"IME window", func(c *windowCandidate) { c.title = "Default IME"; c.class = "IME" }, false},
+		{"ignored class", func(c *windowCandidate) { c.class = "Shell_TrayWnd" }, false},
+		{"owned window", func(c *windowCandidate) { c.owner = 0x42 }, false},
+		{"owned app window", func(c *windowCandidate) {
+			c.owner = 0x42
+			c.exStyle = windows.WS_EX_APPWINDOW
+		}, true},
+		{"tool window", func(c *windowCandidate) { c.exStyle = windows.WS_EX_TOOLWINDOW }, false},
+		{"tool app window", func(c *windowCandidate) {
+			c.exStyle = windows.WS_EX_TOOLWINDOW | windows.WS_EX_APPWINDOW
+		}, true},
+		{"cloaked by the app", func(c *windowCandidate) { c.cloak = windows.DWM_CLOAKED_APP }, false},
+		{"cloaked by the shell without desktop", func(c *windowCandidate) { c.cloak = windows.DWM_CLOAKED_SHELL }, false},
+		{"on another virtual desktop", func(c *windowCandidate) {
+			c.cloak = windows.DWM_CLOAKED_SHELL
+			c.desktopID = "{desktop-2}"
+		}, true},
+		{"minimized", func(c *windowCandidate) {
+			c.rect = models.Rect{X: -32000, Y: -32000, Width: 160, Height: 28}
+		}, true},
+		{"frame host window", func(c *windowCandidate) { c.class = frameHostClass }, true},
+		{"hosted core window", func(c *windowCandidate) { c.class = coreWindowClass }, false},
+		{"below size threshold", func(c *windowCandidate) {
+			c.rect.Width = minMainWindowSize - 1
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mainWindowFixture(1)
+			tt.modify(&c)
+			if got := scoreWindow(c) >= 0; got != tt.eligible {
+				t.Errorf("scoreWindow() = %d, eligible %v, want %v", scoreWindow(c), got, tt.eligible)
+			}
+		})
+	}
+}
+
+func TestScoreWindowPenalties(t *testing.T) {
+	base := scoreWindow(mainWindowFixture(1))
+
+	tests := []struct {
+		name   string
+		modify func(c *windowCandidate)
+	}{
+		{"minimized", func(c *windowCandidate) {
+			c.rect = models.Rect{X: -32000, Y: -32000, Width: 160, Height: 28}
+		}},
+		{"below size threshold", func(c *windowCandidate) {
+			c.rect.Height = minMainWindowSize - 1
+		}},
+		{"on another virtual desktop", func(c *windowCandidate) {
+			c.cloak = windows.DWM_CLOAKED_SHELL
+			c.desktopID = "{desktop-2}"
+		}},
+		{"captionless popup", func(c *windowCandidate) { c.style = windows.WS_POPUP }},
+		{"no activate", func(c *windowCandidate) { c.exStyle = windows.WS_EX_NOACTIVATE }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mainWindowFixture(1)
+			tt.modify(&c)
+			if got := scoreWindow(c); got >= base {
+				t.Errorf("scoreWindow() = %d, want less than main window score %d", got, base)
+			}
+		})
+	}
+
+	appWindow := mainWindowFixture(1)
+	appWindow.exStyle = windows.WS_EX_APPWINDOW
+	if got := scoreWindow(appWindow); got <= base {
+		t.Errorf("app window score = %d, want more than %d", got, base)
+	}
+}
+
+func TestRankWindows(t *testing.T) {
+	tool := mainWindowFixture(1)
+	tool.exStyle = windows.WS_EX_TOOLWINDOW
+
+	splash := mainWindowFixture(2)
+	splash.style = windows.WS_POPUP
+	splash.rect = models.Rect{Width: 80, Height: 80}
+
+	minimized := mainWindowFixture(3)
+	minimized.rect = models.Rect{X: -32000, Y: -32000, Width: 160, Height: 28}
+
+	primary := mainWindowFixture(4)
+
+	cloaked := mainWindowFixture(5)
+	cloaked.cloak = windows.DWM_CLOAKED_APP
+
+	// Identical windows keep their z-order
+	second := mainWindowFixture(6)
+
+	tests := []struct {
+		name       string
+		candidates []windowCandidate
+		want       []syscall.Handle
+	}{
+		{"no candidates", nil, nil},
+		{"only ineligible", []windowCandidate{tool, cloaked}, nil},
+		{"best first", []windowCandidate{splash, tool, minimized, primary, cloaked}, []syscall.Handle{4, 3, 2}},
+		{"ties keep z-order", []windowCandidate{primary, second}, []syscall.Handle{4, 6}},
+		{"ties keep z-order reversed", []windowCandidate{second, primary}, []syscall.Handle{6, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := rankWindows(tt.candidates)
+			if len(ranked) != len(tt.want) {
+				t.Fatalf("rankWindows() returned %d windows, want %d", len(ranked), len(tt.want))
+			}
+			for i, c := range ranked {
+				if c.hwnd != tt.want[i] {
+					t.Errorf("rank %d = hwnd %d, want %d", i, c.hwnd, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// windowTreeFixture is the top-level window tree of one process in z-order
+// from the top, as describe reports it, and the window that must be picked
+type windowTreeFixture struct {
+	Process string  `json:"process"`
+	Want    uintptr `json:"want"`
+	Windows []struct {
+		Hwnd      uintptr     `json:"hwnd"`
+		Title     string      `json:"title"`
+		Class     string      `json:"class"`
+		Owner     uintptr     `json:"owner"`
+		Style     uint32      `json:"style"`
+		ExStyle   uint32      `json:"exStyle"`
+		Cloak     uint32      `json:"cloak"`
+		Rect      models.Rect `json:"rect"`
+		DesktopID string      `json:"desktopId"`
+	} `json:"windows"`
+}
+
+func TestRankWindowTrees(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "window_trees", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no window tree fixtures")
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fixture windowTreeFixture
+			if err := json.Unmarshal(data, &fixture); err != nil {
+				t.Fatalf("parsing fixture: %v", err)
+			}
+
+			candidates := make([]windowCandidate, 0, len(fixture.Windows))
+			for _, w := range fixture.Windows {
+				candidates = append(candidates, windowCandidate{
+					hwnd:      syscall.Handle(w.Hwnd),
+					title:     w.Title,
+					class:     w.Class,
+					owner:     syscall.Handle(w.Owner),
+					style:     w.Style,
+					exStyle:   w.ExStyle,
+					cloak:     w.Cloak,
+					rect:      w.Rect,
+					desktopID: w.DesktopID,
+				})
+			}
+
+			ranked := rankWindows(candidates)
+			if len(ranked) == 0 {
+				t.Fatalf("%s: no main window, want hwnd %#x", fixture.Process, fixture.Want)
+			}
+			if got := uintptr(ranked[0].hwnd); got != fixture.Want {
+				t.Errorf("%s: main window = %#x %q, want %#x", fixture.Process, got, ranked[0].title, fixture.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	apperrors "hptools/internal/errors"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// endProcessTimeout is how long EndProcess waits for a process to exit after
+// its windows were asked to close before terminating it
+const endProcessTimeout = 5 * time.Second
+
+// priorityClasses maps priority classes to their Win32 values
+var priorityClasses = map[models.PriorityClass]uint32{
+	models.PriorityIdle:        windows.IDLE_PRIORITY_CLASS,
+	models.PriorityBelowNormal: windows.BELOW_NORMAL_PRIORITY_CLASS,
+	models.PriorityNormal:      windows.NORMAL_PRIORITY_CLASS,
+	models.PriorityAboveNormal: windows.ABOVE_NORMAL_PRIORITY_CLASS,
+	models.PriorityHigh:        windows.HIGH_PRIORITY_CLASS,
+	models.PriorityRealtime:    windows.REALTIME_PRIORITY_CLASS,
+}
+
+// priorityName returns the priority class name of a Win32 priority value
+func priorityName(value uint32) string {
+	for name, v := range priorityClasses {
+		if v == value {
+			return string(name)
+		}
+	}
+	return fmt.Sprintf("0x%x", value)
+}
+
+// EndProcess asks every window of a process to close and terminates the
+// process if it is still running after a timeout. Processes without
+// windows are terminated right away.
+func (p *processManager) EndProcess(pid int, dryRun bool) (*models.ProcessActionResult, error) {
+	h, result, err := p.openForAction(models.ProcessActionEnd, pid, windows.PROCESS_TERMINATE|windows.SYNCHRONIZE, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(h)
+
+	p.resolver.desktop.invalidate()
+	snapshot, err := p.resolver.desktop.get()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range snapshot.byPID[pid] {
+		result.Windows = append(result.Windows, models.WindowEntry{Handle: uintptr(c.hwnd), PID: pid, Title: c.title, Class: c.class})
+	}
+
+	if len(result.Windows) == 0 {
+		result.Summary = fmt.Sprintf("Terminate %s (PID %d). It has no windows to close.", result.ImageName, pid)
+	} else {
+		result.Summary = fmt.Sprintf("Close %d window(s) of %s (PID %d), then terminate it if it is still running after %s.",
+			len(result.Windows), result.ImageName, pid, endProcessTimeout)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	exited := false
+	if len(result.Windows) > 0 {
+		for _, w := range result.Windows {
+			if err := p.api.PostClose(syscall.Handle(w.Handle)); err != nil {
+				p.logger.Debug("Failed to post WM_CLOSE", "pid", pid, "title", w.Title, "error", err)
+			}
+		}
+		if exited, err = p.api.WaitForProcessExit(h, endProcessTimeout); err != nil {
+			return nil, actionError(models.ProcessActionEnd, pid, err)
+		}
+	}
+
+	if !exited {
+		if err := p.api.TerminateProcess(h, 1); err != nil {
+			return nil, actionError(models.ProcessActionEnd, pid, err)
+		}
+		result.Forced = true
+	}
+
+	p.resolver.desktop.invalidate()
+	p.logger.Info("Process ended", "pid", pid, "image", result.ImageName, "forced", result.Forced)
+	return result, nil
+}
+
+// SuspendProcess suspends every thread of a process
+func (p *processManager) SuspendProcess(pid int, dryRun bool) (*models.ProcessActionResult, error) {
+	h, result, err := p.openForAction(models.ProcessActionSuspend, pid, windows.PROCESS_SUSPEND_RESUME, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(h)
+
+	result.Summary = fmt.Sprintf("Suspend %s (PID %d). Its windows stop responding until it is resumed.", result.ImageName, pid)
+	if dryRun {
+		return result, nil
+	}
+
+	if err := p.api.SuspendProcess(h); err != nil {
+		return nil, actionError(models.ProcessActionSuspend, pid, err)
+	}
+	p.logger.Info("Process suspended", "pid", pid, "image", result.ImageName)
+	return result, nil
+}
+
+// ResumeProcess resumes a suspended process
+func (p *processManager) ResumeProcess(pid int, dryRun bool) (*models.ProcessActionResult, error) {
+	h, result, err := p.openForAction(models.ProcessActionResume, pid, windows.PROCESS_SUSPEND_RESUME, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(h)
+
+	result.Summary = fmt.Sprintf("Resume %s (PID %d).", result.ImageName, pid)
+	if dryRun {
+		return result, nil
+	}
+
+	if err := p.api.ResumeProcess(h); err != nil {
+		return nil, actionError(models.ProcessActionResume, pid, err)
+	}
+	p.logger.Info("Process resumed", "pid", pid, "image", result.ImageName)
+	return result, nil
+}
+
+// SetProcessPriority sets the priority class of a process. After reports the
+// class Windows actually applied: without the right to raise priorities,
+// realtime silently becomes high.
+func (p *processManager) SetProcessPriority(pid int, priority models.PriorityClass, dryRun bool) (*models.ProcessActionResult, error) {
+	value, ok := priorityClasses[priority]
+	if !ok {
+		return nil, apperrors.NewProcessError(fmt.Sprintf("unknown priority class %q", priority), nil)
+	}
+
+	h, result, err := p.openForAction(models.ProcessActionPriority, pid, windows.PROCESS_QUERY_LIMITED_INFORMATION|windows.PROCESS_SET_INFORMATION, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(h)
+
+	current, err := p.api.GetPriorityClass(h)
+	if err != nil {
+		return nil, actionError(models.ProcessActionPriority, pid, err)
+	}
+	result.Before = priorityName(current)
+	result.After = string(priority)
+	result.Summary = fmt.Sprintf("Change the priority of %s (PID %d) from %s to %s.", result.ImageName, pid, result.Before, result.After)
+	if dryRun {
+		return result, nil
+	}
+
+	if err := p.api.SetPriorityClass(h, value); err != nil {
+		return nil, actionError(models.ProcessActionPriority, pid, err)
+	}
+	if applied, err := p.api.GetPriorityClass(h); err == nil {
+		result.After = priorityName(applied)
+	}
+
+	p.logger.Info("Process priority changed", "pid", pid, "image", result.ImageName, "from", result.Before, "to", result.After)
+	return result, nil
+}
+
+// SetProcessAffinity restricts a process to the CPUs set in mask
+func (p *processManager) SetProcessAffinity(pid int, mask uint64, dryRun bool) (*models.ProcessActionResult, error) {
+	h, result, err := p.openForAction(models.ProcessActionAffinity, pid, windows.PROCESS_QUERY_LIMITED_INFORMATION|windows.PROCESS_SET_INFORMATION, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(h)
+
+	current, system, err := p.api.GetProcessAffinityMask(h)
+	if err != nil {
+		return nil, actionError(models.ProcessActionAffinity, pid, err)
+	}
+	if mask == 0 || mask&^system != 0 {
+		return nil, apperrors.NewProcessError(fmt.Sprintf("affinity mask 0x%x is not a subset of the system CPUs 0x%x", mask, system), nil)
+	}
+
+	result.Before = fmt.Sprintf("0x%x", current)
+	result.After = fmt.Sprintf("0x%x", mask)
+	result.Summary = fmt.Sprintf("Change the CPU affinity of %s (PID %d) from %s to %s.", result.ImageName, pid, result.Before, result.After)
+	if dryRun {
+		return result, nil
+	}
+
+	if err := p.api.SetProcessAffinityMask(h, mask); err != nil {
+		return nil, actionError(models.ProcessActionAffinity, pid, err)
+	}
+	p.logger.Info("Process affinity changed", "pid", pid, "image", result.ImageName, "from", result.Before, "to", result.After)
+	return result, nil
+}
+
+// openForAction checks that a process may be acted on and opens it with the
+// rights the action needs. Dry runs open it too, so a missing right shows up
+// before the user confirms.
+func (p *processManager) openForAction(action models.ProcessAction, pid int, access uint32, dryRun bool) (syscall.Handle, *models.ProcessActionResult, error) {
+	// PIDs 0 and 4 are the idle and system processes
+	if pid <= 4 || pid == os.Getpid() {
+		return 0, nil, apperrors.NewProcessError(fmt.Sprintf("refusing to %s process %d", action, pid), nil)
+	}
+
+	entries, err := p.api.GetProcessEntries()
+	if err != nil {
+		return 0, nil, fmt.Errorf("listing processes: %w", err)
+	}
+	entry, ok := entries[pid]
+	if !ok {
+		return 0, nil, apperrors.NewProcessError(fmt.Sprintf("process %d is not running", pid), nil)
+	}
+
+	h, err := p.api.OpenProcessWithAccess(pid, access)
+	if err != nil {
+		return 0, nil, actionError(action, pid, err)
+	}
+
+	return h, &models.ProcessActionResult{
+		Action:    action,
+		PID:       pid,
+		ImageName: entry.ExeFile,
+		DryRun:    dryRun,
+	}, nil
+}
+
+// actionError wraps a failed process action, turning access denied into a
+// permission error the frontend can recognize
+func actionError(action models.ProcessAction, pid int, err error) error {
+	if errors.Is(err, syscall.ERROR_ACCESS_DENIED) {
+		return apperrors.NewPermissionError(
+			fmt.Sprintf("not allowed to %s process %d; it may belong to another user or need hptools to run elevated", action, pid), err)
+	}
+	return fmt.Errorf("%s process %d: %w", action, pid, err)
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"syscall"
+	"time"
+
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// detailsMaxAge is how long an entry may go unused before it is pruned.
+// Every refresh touches the processes it lists, so only processes that have
+// exited or dropped out of every view age out
+const detailsMaxAge = 2 * time.Minute
+
+// detailsKey identifies a process instance; PIDs are reused, start times are not
+type detailsKey struct {
+	pid       int
+	startTime int64
+}
+
+// detailsEntry is a cached details value with the time it was last requested
+type detailsEntry struct {
+	details  *models.ProcessDetails
+	lastUsed time.Time
+}
+
+// GetProcessDetails returns executable path, command line, parent, user and
+// architecture for a process
+func (p *processManager) GetProcessDetails(pid int) (*models.ProcessDetails, error) {
+	details, err := p.processDetails(pid, p.processTree())
+	if err != nil {
+		return nil, fmt.Errorf("getting details for PID %d: %w", pid, err)
+	}
+	return details, nil
+}
+
+// GetProcessIcon returns the PNG-encoded icon of a process's executable. Icons
+// are fetched on demand rather than with the process list to keep it small.
+func (p *processManager) GetProcessIcon(pid int) ([]byte, error) {
+	details, err := p.processDetails(pid, p.processTree())
+	if err != nil {
+		return nil, fmt.Errorf("getting icon for PID %d: %w", pid, err)
+	}
+	if details.ExePath == "" {
+		return nil, fmt.Errorf("getting icon for PID %d: executable path unknown", pid)
+	}
+	icon := p.iconForPath(details.ExePath)
+	if icon == nil {
+		return nil, fmt.Errorf("getting icon for PID %d: no icon in %s", pid, details.ExePath)
+	}
+	return icon, nil
+}
+
+// enrichProcesses attaches details to each process and prunes stale cache entries
+func (p *processManager) enrichProcesses(processes []models.ProcessInfo) {
+	tree := p.processTree()
+
+	for i := range processes {
+		details, err := p.processDetails(processes[i].PID, tree)
+		if err != nil {
+			p.logger.Debug("Failed to get process details", "pid", processes[i].PID, "error", err)
+			continue
+		}
+		processes[i].Details = details
+	}
+
+	p.pruneDetails(time.Now().Add(-detailsMaxAge))
+}
+
+// pruneDetails drops details not used since cutoff and icons no remaining
+// entry refers to. Pruning by age rather than by the caller's process list
+// keeps entries that another view, filter profile or GetProcessDetails
+// caller still uses
+func (p *processManager) pruneDetails(cutoff time.Time) {
+	p.detailsMu.Lock()
+	defer p.detailsMu.Unlock()
+
+	paths := make(map[string]bool, len(p.detailsCache))
+	for key, entry := range p.detailsCache {
+		if entry.lastUsed.Before(cutoff) {
+			delete(p.detailsCache, key)
+			continue
+		}
+		paths[entry.details.ExePath] = true
+	}
+	for path := range p.iconCache {
+		if !paths[path] {
+			delete(p.iconCache, path)
+		}
+	}
+}
+
+// processDetails returns cached details for a process, collecting them on a cache miss
+func (p *processManager) processDetails(pid int, tree func() map[int]windows.ProcessEntry) (*models.ProcessDetails, error) {
+	h, err := p.api.OpenProcess(pid)
+	if err != nil {
+		return nil, fmt.Errorf("opening process: %w", err)
+	}
+	defer syscall.CloseHandle(h)
+
+	startTime, err := p.api.GetProcessStartTime(h)
+	if err != nil {
+		return nil, fmt.Errorf("getting start time: %w", err)
+	}
+
+	key := detailsKey{pid: pid, startTime: startTime.UnixNano()}
+	now := time.Now()
+	p.detailsMu.Lock()
+	cached, ok := p.detailsCache[key]
+	if ok {
+		cached.lastUsed = now
+	}
+	p.detailsMu.Unlock()
+	if ok {
+		return cached.details, nil
+	}
+
+	details := &models.ProcessDetails{
+		StartTime: startTime,
+		ParentPID: tree()[pid].ParentPID,
+	}
+
+	// The remaining fields are best effort; protected processes deny some queries
+	if details.ExePath, err = p.api.QueryFullProcessImageName(h); err != nil {
+		p.logger.Debug("Failed to get executable path", "pid", pid, "error", err)
+	}
+	if details.CommandLine, err = p.api.GetProcessCommandLine(h); err != nil {
+		p.logger.Debug("Failed to get command line", "pid", pid, "error", err)
+	}
+	if details.User, err = p.api.GetProcessUser(h); err != nil {
+		p.logger.Debug("Failed to get process user", "pid", pid, "error", err)
+	}
+	if details.Architecture, err = p.api.GetProcessArchitecture(h); err != nil {
+		p.logger.Debug("Failed to get process architecture", "pid", pid, "error", err)
+	}
+	if details.Elevated, err = p.api.IsProcessElevated(h); err != nil {
+		p.logger.Debug("Failed to get process elevation", "pid", pid, "error", err)
+	}
+	if details.AppID, err = p.api.GetProcessAppUserModelID(h); err != nil {
+		p.logger.Debug("Failed to get AppUserModelID", "pid", pid, "error", err)
+	}
+	p.detailsMu.Lock()
+	p.detailsCache[key] = &detailsEntry{details: details, lastUsed: now}
+	p.detailsMu.Unlock()
+
+	return details, nil
+}
+
+// iconForPath returns the PNG-encoded icon of an executable, cached by path
+func (p *processManager) iconForPath(path string) []byte {
+	p.detailsMu.Lock()
+	icon, ok := p.iconCache[path]
+	p.detailsMu.Unlock()
+	if ok {
+		return icon
+	}
+
+	img, err := p.api.ExtractIcon(path)
+	if err == nil {
+		var buf bytes.Buffer
+		if err = png.Encode(&buf, img); err == nil {
+			icon = buf.Bytes()
+		}
+	}
+	if err != nil {
+		p.logger.Debug("Failed to extract icon", "path", path, "error", err)
+	}
+
+	// Cache failures too so broken executables are not retried on every refresh
+	p.detailsMu.Lock()
+	p.iconCache[path] = icon
+	p.detailsMu.Unlock()
+
+	return icon
+}
+
+// processTree returns a function that takes the process snapshot on first use
+func (p *processManager) processTree() func() map[int]windows.ProcessEntry {
+	var entries map[int]windows.ProcessEntry
+	return func() map[int]windows.ProcessEntry {
+		if entries == nil {
+			var err error
+			if entries, err = p.api.GetProcessEntries(); err != nil {
+				p.logger.Debug("Failed to get process snapshot", "error", err)
+				entries = map[int]windows.ProcessEntry{}
+			}
+		}
+		return entries
+	}
+}
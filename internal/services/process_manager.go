@@ -5,57 +5,158 @@ import (
 	"fmt"
 	"log/slog"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
-	"syscall"
+	"sync"
 
+	"hptools/internal/filter"
 	"hptools/internal/models"
 	"hptools/internal/windows"
 )
 
 type processManager struct {
-	api    *windows.API
-	logger *slog.Logger
+	api      *windows.API
+	resolver *windowResolver
+	logger   *slog.Logger
+
+	detailsMu    sync.Mutex
+	detailsCache map[detailsKey]*detailsEntry
+	iconCache    map[string][]byte
+
+	filterMu       sync.RWMutex
+	filters        map[string]*filter.Matcher
+	defaultProfile string
 }
 
-// NewProcessManager creates a new process manager
+// NewProcessManager creates a new process manager using the built-in filter profiles
 func NewProcessManager(api *windows.API, logger *slog.Logger) ProcessManager {
-	return &processManager{
-		api:    api,
-		logger: logger,
+	return newProcessManager(api, newWindowResolver(api), logger)
+}
+
+// newProcessManager creates a process manager that shares a window resolver
+func newProcessManager(api *windows.API, resolver *windowResolver, logger *slog.Logger) *processManager {
+	p := &processManager{
+		api:          api,
+		resolver:     resolver,
+		logger:       logger,
+		detailsCache: make(map[detailsKey]*detailsEntry),
+		iconCache:    make(map[string][]byte),
+	}
+	if err := p.SetFilterProfiles(nil, filter.ProfileApplications); err != nil {
+		// The built-in profiles are static, so this only fails on a programming error
+		panic(err)
 	}
+	return p
 }
 
-// GetApplicationProcesses returns only processes that have visible windows
+// GetApplicationProcesses returns processes with visible windows matching the default filter profile
 func (p *processManager) GetApplicationProcesses() ([]models.ProcessInfo, error) {
-	processes, err := p.getAllProcesses()
+	p.filterMu.RLock()
+	profile := p.defaultProfile
+	p.filterMu.RUnlock()
+
+	return p.GetProcessesForProfile(profile)
+}
+
+// GetProcessesForProfile returns processes with visible windows matching the named filter profile
+func (p *processManager) GetProcessesForProfile(profile string) ([]models.ProcessInfo, error) {
+	matcher, err := p.matcher(profile)
 	if err != nil {
-		return nil, fmt.Errorf("getting all processes: %w", err)
+		return nil, err
+	}
+
+	processes, err := p.getWindowedProcesses(matcher)
+	if err != nil {
+		return nil, err
 	}
 
 	var apps []models.ProcessInfo
 	for _, proc := range processes {
-		if p.IsApplication(proc) {
-			// Check if this process actually has visible windows
-			windowInfo := p.getProcessWindowInfo(proc.PID)
-			if windowInfo.HasWindow {
-				proc.WindowTitle = windowInfo.WindowTitle
-				proc.HasWindow = windowInfo.HasWindow
-				proc.WindowCount = windowInfo.WindowCount
-				apps = append(apps, proc)
-			}
+		if matcher.Match(proc) {
+			apps = append(apps, proc)
 		}
 	}
 
-	// Group processes by image name and only keep the one with the main window
-	groupedApps := p.groupAndFilterProcesses(apps)
-	p.logger.Info("Found application processes", "count", len(groupedApps))
+	// Group windows by application identity into one entry per application
+	if matcher.Group() {
+		apps = p.groupAndFilterProcesses(apps)
+	}
+	p.logger.Info("Found application processes", "profile", profile, "count", len(apps))
 
-	return groupedApps, nil
+	return apps, nil
 }
 
 // GetAllProcessesWithWindows returns all processes that have visible windows (for debugging)
 func (p *processManager) GetAllProcessesWithWindows() ([]models.ProcessInfo, error) {
+	apps, err := p.getWindowedProcesses(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	p.logger.Debug("Found processes with windows", "count", len(apps))
+	return apps, nil
+}
+
+// IsApplication determines if a process matches the default filter profile
+func (p *processManager) IsApplication(proc models.ProcessInfo) bool {
+	p.filterMu.RLock()
+	defer p.filterMu.RUnlock()
+	return p.filters[p.defaultProfile].Match(proc)
+}
+
+// SetFilterProfiles compiles filter profiles on top of the built-in ones.
+// Profiles with the same name as a built-in profile replace it.
+func (p *processManager) SetFilterProfiles(profiles []models.FilterProfile, defaultProfile string) error {
+	filters := make(map[string]*filter.Matcher)
+	for _, profile := range append(filter.DefaultProfiles(), profiles...) {
+		matcher, err := filter.Compile(profile)
+		if err != nil {
+			return fmt.Errorf("compiling filter profile: %w", err)
+		}
+		filters[profile.Name] = matcher
+	}
+
+	if _, ok := filters[defaultProfile]; !ok {
+		return fmt.Errorf("default filter profile %q not found", defaultProfile)
+	}
+
+	p.filterMu.Lock()
+	p.filters = filters
+	p.defaultProfile = defaultProfile
+	p.filterMu.Unlock()
+	return nil
+}
+
+// ListFilterProfiles returns the names of all filter profiles
+func (p *processManager) ListFilterProfiles() []string {
+	p.filterMu.RLock()
+	defer p.filterMu.RUnlock()
+
+	names := make([]string, 0, len(p.filters))
+	for name := range p.filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matcher looks up a compiled filter profile by name
+func (p *processManager) matcher(profile string) (*filter.Matcher, error) {
+	p.filterMu.RLock()
+	defer p.filterMu.RUnlock()
+
+	matcher, ok := p.filters[profile]
+	if !ok {
+		return nil, fmt.Errorf("filter profile %q not found", profile)
+	}
+	return matcher, nil
+}
+
+// getWindowedProcesses returns processes with visible windows, enriched with details.
+// When matcher is set, processes it rules out by image and session are dropped
+// before their windows are enumerated.
+func (p *processManager) getWindowedProcesses(matcher *filter.Matcher) ([]models.ProcessInfo, error) {
 	processes, err := p.getAllProcesses()
 	if err != nil {
 		return nil, fmt.Errorf("getting all processes: %w", err)
@@ -63,44 +164,30 @@ func (p *processManager) GetAllProcessesWithWindows() ([]models.ProcessInfo, err
 
 	var apps []models.ProcessInfo
 	for _, proc := range processes {
-		// Check if this process has visible windows (no application filtering)
+		if matcher != nil && !matcher.MayMatch(proc) {
+			continue
+		}
 		windowInfo := p.getProcessWindowInfo(proc.PID)
 		if windowInfo.HasWindow {
 			proc.WindowTitle = windowInfo.WindowTitle
+			proc.WindowClass = windowInfo.WindowClass
 			proc.HasWindow = windowInfo.HasWindow
 			proc.WindowCount = windowInfo.WindowCount
+			proc.Windows = windowInfo.Windows
 			apps = append(apps, proc)
 		}
 	}
 
-	p.logger.Debug("Found processes with windows", "count", len(apps))
-	return apps, nil
-}
-
-// IsApplication determines if a process is likely an application (not a system process)
-func (p *processManager) IsApplication(proc models.ProcessInfo) bool {
-	imageName := strings.ToLower(proc.ImageName)
-
-	// System processes to exclude
-	systemProcesses := []string{
-		"system", "smss.exe", "csrss.exe", "wininit.exe", "winlogon.exe",
-		"services.exe", "lsass.exe", "svchost.exe", "spoolsv.exe",
-		"dwm.exe", "audiodg.exe", "conhost.exe", "taskmgr.exe",
-		"cmd.exe", "powershell.exe", "wuauclt.exe", "mmc.exe",
-		"rundll32.exe", "dllhost.exe", "sihost.exe", "fontdrvhost.exe",
-		"winrt.exe", "backgroundtaskhost.exe", "runtimebroker.exe",
-	}
-
-	// Check if it's a known system process
-	for _, sys := range systemProcesses {
-		if imageName == sys {
-			return false
+	p.enrichProcesses(apps)
+	for i := range apps {
+		switch {
+		case apps[i].Windows[0].AppID != "":
+			apps[i].AppID = apps[i].Windows[0].AppID
+		case apps[i].Details != nil:
+			apps[i].AppID = apps[i].Details.AppID
 		}
 	}
-
-	// Applications typically run in user sessions (not session 0)
-	// and have .exe extension
-	return proc.SessionNum > 0 && strings.HasSuffix(imageName, ".exe")
+	return apps, nil
 }
 
 // getAllProcesses gets all running processes using tasklist
@@ -150,66 +237,124 @@ func (p *processManager) getAllProcesses() ([]models.ProcessInfo, error) {
 // getProcessWindowInfo checks if a process has visible windows and gets window info
 func (p *processManager) getProcessWindowInfo(targetPID int) models.ProcessWindowInfo {
 	var windowInfo models.ProcessWindowInfo
-	var foundWindows []string
 
-	// Callback function for EnumWindows
-	enumProc := syscall.NewCallback(func(hwnd syscall.Handle, lParam uintptr) uintptr {
-		// Check if window is visible
-		if !p.api.IsWindowVisible(hwnd) {
-			return 1 // Continue enumeration for invisible windows
-		}
+	ranked, err := p.resolver.appWindows(targetPID)
+	if err != nil {
+		return windowInfo
+	}
 
-		pid := p.api.GetWindowThreadProcessId(hwnd)
-		if int(pid) == targetPID {
-			// Get window title
-			title := p.api.GetWindowText(hwnd)
-			if title != "" && title != "Default IME" && title != "MSCTFIME UI" {
-				foundWindows = append(foundWindows, title)
-				if windowInfo.WindowTitle == "" || len(title) > len(windowInfo.WindowTitle) {
-					windowInfo.WindowTitle = title // Keep the longest/most descriptive title
-				}
-			}
-			windowInfo.WindowCount++
+	for _, c := range ranked {
+		appID, err := p.resolver.appID(c.hwnd)
+		if err != nil {
+			p.logger.Debug("Failed to get window AppUserModelID", "pid", targetPID, "error", err)
 		}
-		return 1 // Continue enumeration
-	})
-
-	if err := p.api.EnumWindows(enumProc); err != nil {
-		p.logger.Warn("Failed to enumerate windows", "pid", targetPID, "error", err)
+		windowInfo.Windows = append(windowInfo.Windows, models.WindowEntry{
+			Handle: uintptr(c.hwnd),
+			PID:    targetPID,
+			Title:  c.title,
+			Class:  c.class,
+			AppID:  appID,
+		})
 	}
 
-	windowInfo.HasWindow = windowInfo.WindowCount > 0 && windowInfo.WindowTitle != ""
+	windowInfo.HasWindow = true
+	windowInfo.WindowTitle = ranked[0].title
+	windowInfo.WindowClass = ranked[0].class
+	windowInfo.WindowCount = len(ranked)
 	return windowInfo
 }
 
-// groupAndFilterProcesses groups processes by image name and keeps only the main process with window
+// appGroup collects the processes and windows that belong to one application
+type appGroup struct {
+	appID   string
+	members []models.ProcessInfo
+	windows []models.WindowEntry
+}
+
+// groupAndFilterProcesses groups windows by application identity and returns one
+// entry per application, keeping every window of the application as a child entry
 func (p *processManager) groupAndFilterProcesses(processes []models.ProcessInfo) []models.ProcessInfo {
-	processGroups := make(map[string][]models.ProcessInfo)
+	tree := p.processTree()
+	groups := make(map[string]*appGroup)
+	var order []string
 
-	// Group by image name
 	for _, proc := range processes {
-		key := strings.ToLower(proc.ImageName)
-		processGroups[key] = append(processGroups[key], proc)
-	}
-
-	var result []models.ProcessInfo
-	for _, group := range processGroups {
-		if len(group) == 1 {
-			// Single process, keep it
-			result = append(result, group[0])
-		} else {
-			// Multiple processes, find the best candidate
-			best := p.selectBestProcess(group)
-			if best != nil {
-				result = append(result, *best)
+		procKey := processIdentity(proc, tree())
+		joined := make(map[string]bool)
+
+		for _, win := range proc.Windows {
+			// Windows with their own AppUserModelID (e.g. browser profiles) form separate apps
+			key, appID := procKey, proc.AppID
+			if win.AppID != "" {
+				key, appID = "app:"+strings.ToLower(win.AppID), win.AppID
+			}
+
+			group, ok := groups[key]
+			if !ok {
+				group = &appGroup{appID: appID}
+				groups[key] = group
+				order = append(order, key)
+			}
+			group.windows = append(group.windows, win)
+			if !joined[key] {
+				group.members = append(group.members, proc)
+				joined[key] = true
+			}
+		}
+	}
+
+	result := make([]models.ProcessInfo, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+		best := *p.selectBestProcess(group.members)
+
+		// Describe the entry by the best process's first window in this group
+		for _, win := range group.windows {
+			if win.PID == best.PID {
+				best.WindowTitle = win.Title
+				best.WindowClass = win.Class
+				break
 			}
 		}
+		best.AppID = group.appID
+		best.Windows = group.windows
+		best.WindowCount = len(group.windows)
+		result = append(result, best)
 	}
 
 	return result
 }
 
-// selectBestProcess selects the best process from a group of processes with the same name
+// processIdentity returns a key identifying the application a process belongs to:
+// its AppUserModelID, or its executable path and the root of its process tree
+func processIdentity(proc models.ProcessInfo, tree map[int]windows.ProcessEntry) string {
+	if proc.AppID != "" {
+		return "app:" + strings.ToLower(proc.AppID)
+	}
+
+	root := processTreeRoot(proc.PID, proc.ImageName, tree)
+	if proc.Details != nil && proc.Details.ExePath != "" {
+		return fmt.Sprintf("exe:%s#%d", strings.ToLower(proc.Details.ExePath), root)
+	}
+	return fmt.Sprintf("image:%s#%d", strings.ToLower(proc.ImageName), root)
+}
+
+// processTreeRoot walks up the parents of a process while they run the same
+// executable and returns the topmost PID
+func processTreeRoot(pid int, imageName string, tree map[int]windows.ProcessEntry) int {
+	root := pid
+	// Bound the walk; PID reuse can create cycles in the parent chain
+	for range len(tree) {
+		parent, ok := tree[tree[root].ParentPID]
+		if !ok || parent.PID == root || !strings.EqualFold(parent.ExeFile, imageName) {
+			break
+		}
+		root = parent.PID
+	}
+	return root
+}
+
+// selectBestProcess selects the best process from a group of processes of the same application
 func (p *processManager) selectBestProcess(processes []models.ProcessInfo) *models.ProcessInfo {
 	var best *models.ProcessInfo
 
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"errors"
+	"sync"
+)
+
+// pausable is a rule that can be suspended without losing its settings
+type pausable interface {
+	SetPaused(paused bool) error
+}
+
+type rulesPauser struct {
+	rules []pausable
+
+	mu     sync.Mutex
+	paused bool
+}
+
+// NewRulesPauser creates a pauser for the trigger, focus-follow, drag and
+// tiling rules and the script callbacks; they start running
+func NewRulesPauser(triggers TriggerManager, focus FocusFollower, dragger WindowDragger, tiler Tiler, scripts ScriptEngine) RulesPauser {
+	return &rulesPauser{rules: []pausable{triggers, focus, dragger, tiler, scripts}}
+}
+
+// Paused reports whether the rules are suspended
+func (r *rulesPauser) Paused() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.paused
+}
+
+// SetPaused suspends or resumes every rule. Each rule is updated even when
+// another fails; the errors are reported together.
+func (r *rulesPauser) SetPaused(paused bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.paused = paused
+
+	var errs []error
+	for _, rule := range r.rules {
+		errs = append(errs, rule.SetPaused(paused))
+	}
+	return errors.Join(errs...)
+}
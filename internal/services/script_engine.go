@@ -0,0 +1,35 @@
+package services
+
+import (
+	"log/slog"
+	"syscall"
+	"time"
+
+	"hptools/internal/models"
+	"hptools/internal/scripting"
+	"hptools/internal/windows"
+)
+
+// scriptDesktop gives scripts the window service plus targets and focus
+type scriptDesktop struct {
+	WindowService
+	api *windows.API
+}
+
+// ApplyWindowTarget moves a window like a layout entry would
+func (d *scriptDesktop) ApplyWindowTarget(handle uintptr, target models.WindowTarget) error {
+	return applyWindowTarget(d.WindowService, handle, target)
+}
+
+// FocusWindow brings a window to the foreground
+func (d *scriptDesktop) FocusWindow(handle uintptr) error {
+	return d.api.FocusWindow(syscall.Handle(handle))
+}
+
+// NewScriptEngine creates an engine for the user scripts that acts on the
+// window service and calls back on the watcher's window events. Scripts are
+// loaded with Load and their callbacks run after Start.
+func NewScriptEngine(api *windows.API, windows WindowService, watcher WindowWatcher, timeout time.Duration, logger *slog.Logger) ScriptEngine {
+	desktop := &scriptDesktop{WindowService: windows, api: api}
+	return scripting.NewEngine(desktop, watcher.OnWindowEvent, timeout, logger)
+}
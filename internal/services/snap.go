@@ -0,0 +1,149 @@
+package services
+
+import (
+	"fmt"
+	"syscall"
+
+	apperrors "hptools/internal/errors"
+	"hptools/internal/geometry"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// SetSnapOptions sets how requested rects align with nearby monitor and
+// window edges
+func (b *boundsGuard) SetSnapOptions(options models.SnapOptions) error {
+	if options.Threshold < 0 {
+		return apperrors.NewConfigError(fmt.Sprintf("negative snap threshold %d", options.Threshold), nil)
+	}
+	if err := checkOverlapPolicy(options.Overlap); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.snap = options
+	b.mu.Unlock()
+	return nil
+}
+
+// snapMove aligns a requested window rect of hwnd with nearby edges and
+// resolves overlaps. Snapping is best effort: if the surroundings cannot be
+// read the rect is returned unchanged.
+func (b *boundsGuard) snapMove(hwnd syscall.Handle, rect models.Rect) models.Rect {
+	return b.snapWith(hwnd, rect, func(frame models.Rect, env geometry.SnapEnv, options models.SnapOptions) (models.Rect, error) {
+		return geometry.Snap(frame, env, options), nil
+	})
+}
+
+// snapResize is snapMove for a rect resized around anchor
+func (b *boundsGuard) snapResize(hwnd syscall.Handle, rect models.Rect, anchor models.Anchor) models.Rect {
+	return b.snapWith(hwnd, rect, func(frame models.Rect, env geometry.SnapEnv, options models.SnapOptions) (models.Rect, error) {
+		return geometry.SnapResized(frame, anchor, env, options)
+	})
+}
+
+// snapWith snaps the visible frame of a requested window rect and converts
+// the result back to a window rect
+func (b *boundsGuard) snapWith(hwnd syscall.Handle, rect models.Rect, snap func(models.Rect, geometry.SnapEnv, models.SnapOptions) (models.Rect, error)) models.Rect {
+	b.mu.Lock()
+	options := b.snap
+	b.mu.Unlock()
+
+	if options.Threshold == 0 && (options.Overlap == "" || options.Overlap == models.OverlapAllow) {
+		return rect
+	}
+
+	env, err := collectSnapEnv(b.api, b.resolver, hwnd)
+	if err != nil {
+		b.logger.Debug("Snapping skipped", "error", err)
+		return rect
+	}
+
+	inset := frameInset(b.api, hwnd)
+	snapped, err := snap(toFrame(rect, inset), env, options)
+	if err != nil {
+		b.logger.Debug("Snapping skipped", "error", err)
+		return rect
+	}
+
+	target := fromFrame(snapped, inset)
+	if target != rect {
+		b.logger.Debug("Window rect snapped", "requested", rect, "applied", target)
+	}
+	return target
+}
+
+// checkOverlapPolicy validates an overlap policy; empty means allow
+func checkOverlapPolicy(policy models.OverlapPolicy) error {
+	switch policy {
+	case "", models.OverlapAllow, models.OverlapAvoid, models.OverlapShrink:
+		return nil
+	}
+	return apperrors.NewConfigError(fmt.Sprintf("unknown overlap policy %q", policy), nil)
+}
+
+// collectSnapEnv returns the monitors and the visible frames of the main
+// windows on the current desktop, leaving out exclude
+func collectSnapEnv(api *windows.API, resolver *windowResolver, exclude syscall.Handle) (geometry.SnapEnv, error) {
+	monitors, err := api.EnumMonitors()
+	if err != nil {
+		return geometry.SnapEnv{}, fmt.Errorf("enumerating monitors: %w", err)
+	}
+
+	snapshot, err := resolver.desktop.get()
+	if err != nil {
+		return geometry.SnapEnv{}, err
+	}
+
+	env := geometry.SnapEnv{Monitors: monitors}
+	for _, candidates := range snapshot.byPID {
+		for _, c := range candidates {
+			if c.hwnd == exclude || c.cloak != 0 || scoreWindow(c) < 0 || api.IsIconic(c.hwnd) {
+				continue
+			}
+			if frame, err := api.GetWindowFrameRect(c.hwnd); err == nil {
+				env.Windows = append(env.Windows, frame.ToRect())
+			}
+		}
+	}
+	return env, nil
+}
+
+// frameInset returns how far the window rect of hwnd extends beyond its
+// visible frame on each side, i.e. the invisible resize borders
+func frameInset(api *windows.API, hwnd syscall.Handle) models.RECT {
+	window, err := api.GetWindowRect(hwnd)
+	if err != nil {
+		return models.RECT{}
+	}
+	frame, err := api.GetWindowFrameRect(hwnd)
+	if err != nil {
+		return models.RECT{}
+	}
+	return models.RECT{
+		Left:   frame.Left - window.Left,
+		Top:    frame.Top - window.Top,
+		Right:  window.Right - frame.Right,
+		Bottom: window.Bottom - frame.Bottom,
+	}
+}
+
+// toFrame converts a window rect to its visible frame
+func toFrame(rect models.Rect, inset models.RECT) models.Rect {
+	return models.Rect{
+		X:      rect.X + int(inset.Left),
+		Y:      rect.Y + int(inset.Top),
+		Width:  rect.Width - int(inset.Left+inset.Right),
+		Height: rect.Height - int(inset.Top+inset.Bottom),
+	}
+}
+
+// fromFrame converts a visible frame back to a window rect
+func fromFrame(frame models.Rect, inset models.RECT) models.Rect {
+	return models.Rect{
+		X:      frame.X - int(inset.Left),
+		Y:      frame.Y - int(inset.Top),
+		Width:  frame.Width + int(inset.Left+inset.Right),
+		Height: frame.Height + int(inset.Top+inset.Bottom),
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"log/slog"
+	"sync"
+	"syscall"
+	"time"
+
+	"hptools/internal/models"
+	"hptools/internal/thumbnail"
+	"hptools/internal/windows"
+)
+
+// thumbnailTTL bounds how stale a cached thumbnail may get. Content changes
+// inside a window raise no event, so entries expire even without one.
+const thumbnailTTL = 5 * time.Second
+
+// maxThumbnails caps the cache. Windows that are never requested again and
+// raise no destroy event would otherwise keep their entries forever.
+const maxThumbnails = 64
+
+type thumbnailKey struct {
+	handle              uintptr
+	maxWidth, maxHeight int
+}
+
+type thumbnailEntry struct {
+	png      []byte
+	takenAt  time.Time
+	lastUsed time.Time
+}
+
+type thumbnailService struct {
+	api    *windows.API
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	cache map[thumbnailKey]thumbnailEntry
+}
+
+// NewThumbnailService creates a thumbnail service. Cached thumbnails are
+// dropped when watcher reports a change to their window.
+func NewThumbnailService(api *windows.API, watcher WindowWatcher, logger *slog.Logger) ThumbnailManager {
+	t := &thumbnailService{
+		api:    api,
+		logger: logger,
+		cache:  make(map[thumbnailKey]thumbnailEntry),
+	}
+	watcher.OnWindowEvent(t.invalidate)
+	return t
+}
+
+// GetThumbnail returns a PNG of the window scaled to fit maxWidth x maxHeight
+func (t *thumbnailService) GetThumbnail(handle uintptr, maxWidth, maxHeight int) ([]byte, error) {
+	if maxWidth <= 0 || maxHeight <= 0 {
+		return nil, fmt.Errorf("invalid thumbnail size %dx%d", maxWidth, maxHeight)
+	}
+
+	key := thumbnailKey{handle: handle, maxWidth: maxWidth, maxHeight: maxHeight}
+
+	t.mu.Lock()
+	entry, ok := t.cache[key]
+	if ok && time.Since(entry.takenAt) < thumbnailTTL {
+		entry.lastUsed = time.Now()
+		t.cache[key] = entry
+		t.mu.Unlock()
+		return entry.png, nil
+	}
+	t.mu.Unlock()
+
+	img, err := t.api.CaptureWindow(syscall.Handle(handle))
+	if err != nil {
+		return nil, fmt.Errorf("capturing window %#x: %w", handle, err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, thumbnail.Downscale(img, maxWidth, maxHeight)); err != nil {
+		return nil, fmt.Errorf("encoding thumbnail: %w", err)
+	}
+
+	now := time.Now()
+	t.mu.Lock()
+	t.evict(now)
+	t.cache[key] = thumbnailEntry{png: buf.Bytes(), takenAt: now, lastUsed: now}
+	t.mu.Unlock()
+
+	return buf.Bytes(), nil
+}
+
+// invalidate drops every cached size of the window an event refers to,
+// including destroyed windows
+func (t *thumbnailService) invalidate(event models.WindowEvent) {
+	if event.Type == models.WindowFocused {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.cache {
+		if key.handle == event.Handle {
+			delete(t.cache, key)
+		}
+	}
+}
+
+// evict drops expired entries and, if the cache is still full, the least
+// recently used one. The caller holds t.mu.
+func (t *thumbnailService) evict(now time.Time) {
+	for key, entry := range t.cache {
+		if now.Sub(entry.takenAt) >= thumbnailTTL {
+			delete(t.cache, key)
+		}
+	}
+
+	for len(t.cache) >= maxThumbnails {
+		var oldest thumbnailKey
+		var oldestUsed time.Time
+		for key, entry := range t.cache {
+			if oldestUsed.IsZero() || entry.lastUsed.Before(oldestUsed) {
+				oldest, oldestUsed = key, entry.lastUsed
+			}
+		}
+		delete(t.cache, oldest)
+	}
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	apperrors "hptools/internal/errors"
+	"hptools/internal/events"
+	"hptools/internal/filter"
+	"hptools/internal/geometry"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// retileDelay batches the window events of an application starting or
+// closing into one retile
+const retileDelay = 100 * time.Millisecond
+
+type tiler struct {
+	api      *windows.API
+	resolver *windowResolver
+	watcher  WindowWatcher
+	logger   *slog.Logger
+
+	mu          sync.Mutex
+	options     models.TilingOptions
+	paused      bool
+	matchers    []*filter.Matcher
+	tiled       []syscall.Handle
+	unsubscribe func()
+	pending     *time.Timer
+	changed     *events.Notifier
+}
+
+// NewTiler creates a tiler that follows the watcher's window events; it is
+// idle until enabled with SetOptions
+func NewTiler(api *windows.API, watcher WindowWatcher, logger *slog.Logger) Tiler {
+	return &tiler{
+		api:      api,
+		resolver: newWindowResolver(api),
+		watcher:  watcher,
+		logger:   logger,
+		changed:  events.NewNotifier(),
+	}
+}
+
+// SetOptions validates and applies the tiling options. Enabling tiling tiles
+// the matching windows already on the monitor. On error the current options
+// are kept.
+func (t *tiler) SetOptions(options models.TilingOptions) error {
+	if err := checkTilingLayout(options.Layout); err != nil {
+		return err
+	}
+	if options.Gap < 0 {
+		return apperrors.NewConfigError(fmt.Sprintf("negative tiling gap %d", options.Gap), nil)
+	}
+	if options.Monitor < 0 {
+		return apperrors.NewConfigError(fmt.Sprintf("invalid tiling monitor %d", options.Monitor), nil)
+	}
+	options.MasterRatio = clampMasterRatio(options.MasterRatio)
+
+	matchers := make([]*filter.Matcher, 0, len(options.Match))
+	for i, match := range options.Match {
+		matcher, err := compileWindowMatch(match)
+		if err != nil {
+			return apperrors.NewConfigError(fmt.Sprintf("invalid tiling match %d", i), err)
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	t.mu.Lock()
+	previous := t.options
+	t.options = options
+	t.matchers = matchers
+	running := t.unsubscribe != nil
+	active := options.Enabled && !t.paused
+	switch {
+	case active && !running:
+		t.unsubscribe = t.watcher.OnWindowEvent(t.handle)
+	case !active && running:
+		t.stopLocked()
+	}
+	t.mu.Unlock()
+
+	if options.Enabled != previous.Enabled {
+		t.changed.Notify()
+	}
+	if !active {
+		return nil
+	}
+	// A different monitor or new rules select a different set of windows
+	if !running || previous.Monitor != options.Monitor || len(options.Match) > 0 || len(previous.Match) > 0 {
+		t.seed()
+	}
+	t.logger.Info("Tiling enabled", "monitor", options.Monitor, "layout", options.Layout)
+	return t.Retile()
+}
+
+// SetPaused suspends or resumes tiling without changing the options.
+// Resuming tiles the matching windows on the monitor again.
+func (t *tiler) SetPaused(paused bool) error {
+	t.mu.Lock()
+	t.paused = paused
+	running := t.unsubscribe != nil
+	active := t.options.Enabled && !paused
+	switch {
+	case active && !running:
+		t.unsubscribe = t.watcher.OnWindowEvent(t.handle)
+	case !active && running:
+		t.stopLocked()
+	}
+	t.mu.Unlock()
+
+	if !active || running {
+		return nil
+	}
+	t.seed()
+	return t.Retile()
+}
+
+// Options returns the current tiling options
+func (t *tiler) Options() models.TilingOptions {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.options
+}
+
+// OnTilingChanged registers a listener called when tiling is enabled or
+// disabled. It returns a function that removes the listener.
+func (t *tiler) OnTilingChanged(fn func()) func() {
+	return t.changed.Subscribe(fn)
+}
+
+// SetLayout switches the tiling layout and retiles
+func (t *tiler) SetLayout(layout models.TilingLayout) error {
+	if err := checkTilingLayout(layout); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.options.Layout = layout
+	t.mu.Unlock()
+	return t.Retile()
+}
+
+// AdjustMasterRatio changes the master window's share of the width by delta
+// and retiles
+func (t *tiler) AdjustMasterRatio(delta float64) error {
+	t.mu.Lock()
+	t.options.MasterRatio = clampMasterRatio(t.options.MasterRatio + delta)
+	t.mu.Unlock()
+	return t.Retile()
+}
+
+// AdjustGap changes the gap by delta pixels and retiles
+func (t *tiler) AdjustGap(delta int) error {
+	t.mu.Lock()
+	t.options.Gap = max(t.options.Gap+delta, 0)
+	t.mu.Unlock()
+	return t.Retile()
+}
+
+// Stop stops following window events; tiled windows stay where they are
+func (t *tiler) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.unsubscribe != nil {
+		t.stopLocked()
+	}
+}
+
+// stopLocked unsubscribes and forgets the tiled windows. t.mu must be held.
+func (t *tiler) stopLocked() {
+	t.unsubscribe()
+	t.unsubscribe = nil
+	if t.pending != nil {
+		t.pending.Stop()
+		t.pending = nil
+	}
+	t.tiled = nil
+	t.logger.Info("Tiling disabled")
+}
+
+// Retile moves every tiled window to its tile in one batch
+func (t *tiler) Retile() error {
+	t.mu.Lock()
+	options := t.options
+	paused := t.paused
+	// Windows can disappear without an event reaching us, e.g. when the
+	// event queue overflowed
+	t.tiled = slices.DeleteFunc(t.tiled, func(hwnd syscall.Handle) bool {
+		return !t.api.IsWindowVisible(hwnd) || t.api.IsIconic(hwnd)
+	})
+	tiled := slices.Clone(t.tiled)
+	t.mu.Unlock()
+
+	if !options.Enabled || paused || len(tiled) == 0 {
+		return nil
+	}
+
+	monitors, err := t.api.EnumMonitors()
+	if err != nil {
+		return fmt.Errorf("enumerating monitors: %w", err)
+	}
+	if options.Monitor >= len(monitors) {
+		return fmt.Errorf("monitor %d does not exist", options.Monitor)
+	}
+
+	rects, err := geometry.Tile(options.Layout, monitors[options.Monitor].WorkArea, len(tiled), options.MasterRatio, options.Gap)
+	if err != nil {
+		return fmt.Errorf("computing tiles: %w", err)
+	}
+
+	moves := make([]windows.WindowMove, 0, len(tiled))
+	for i, hwnd := range tiled {
+		// A maximized window ignores its normal rect until it is restored
+		if t.api.IsZoomed(hwnd) {
+			t.api.RestoreWindow(hwnd)
+		}
+		rect := fromFrame(rects[i], frameInset(t.api, hwnd))
+		moves = append(moves, windows.WindowMove{Hwnd: hwnd, X: rect.X, Y: rect.Y, Width: rect.Width, Height: rect.Height})
+	}
+	if err := t.api.MoveWindows(moves); err != nil {
+		return fmt.Errorf("moving tiled windows: %w", err)
+	}
+
+	t.logger.Debug("Windows retiled", "count", len(tiled), "layout", options.Layout)
+	return nil
+}
+
+// seed replaces the tiled windows with the matching windows on the monitor,
+// in z-order from the top
+func (t *tiler) seed() {
+	handles, err := t.api.EnumWindows()
+	if err != nil {
+		t.logger.Warn("Failed to enumerate windows for tiling", "error", err)
+		return
+	}
+
+	t.mu.Lock()
+	monitor, matchers := t.options.Monitor, t.matchers
+	t.mu.Unlock()
+
+	var tiled []syscall.Handle
+	for _, hwnd := range handles {
+		if t.tileable(hwnd, monitor, matchers) {
+			tiled = append(tiled, hwnd)
+		}
+	}
+
+	t.mu.Lock()
+	t.tiled = tiled
+	t.mu.Unlock()
+}
+
+// handle adds windows that appear on the monitor and removes windows that
+// go away or are moved to another monitor, then schedules a retile. Windows
+// are classified outside t.mu, since describing them can wait on other
+// processes.
+func (t *tiler) handle(event models.WindowEvent) {
+	hwnd := syscall.Handle(event.Handle)
+
+	t.mu.Lock()
+	tracked := slices.Contains(t.tiled, hwnd)
+	monitor, matchers := t.options.Monitor, t.matchers
+	t.mu.Unlock()
+
+	var add bool
+	switch event.Type {
+	case models.WindowCreated, models.WindowShown, models.WindowRestored, models.WindowTitleChanged:
+		// Windows often get their title after they are shown, so a title
+		// change can make a window tileable
+		if tracked || !t.tileable(hwnd, monitor, matchers) {
+			return
+		}
+		add = true
+	case models.WindowDestroyed, models.WindowHidden, models.WindowMinimized:
+		if !tracked {
+			return
+		}
+	case models.WindowMoved:
+		// Retiling moves windows within the monitor, so only moves by the
+		// user can take a window off it
+		if !tracked || t.onMonitor(hwnd, monitor) {
+			return
+		}
+	default:
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Tiling may have stopped or moved to another monitor in the meantime
+	if t.unsubscribe == nil || t.options.Monitor != monitor {
+		return
+	}
+	if add {
+		if slices.Contains(t.tiled, hwnd) {
+			return
+		}
+		t.tiled = append(t.tiled, hwnd)
+	} else {
+		t.tiled = slices.DeleteFunc(t.tiled, func(h syscall.Handle) bool { return h == hwnd })
+	}
+
+	if t.pending != nil {
+		t.pending.Stop()
+	}
+	t.pending = time.AfterFunc(retileDelay, func() {
+		if err := t.Retile(); err != nil {
+			t.logger.Warn("Failed to retile", "error", err)
+		}
+	})
+}
+
+// tileable reports whether a window is a visible main window on monitor
+// that matches the rules. Our own windows are never tiled.
+func (t *tiler) tileable(hwnd syscall.Handle, monitor int, matchers []*filter.Matcher) bool {
+	if !t.api.IsWindowVisible(hwnd) || t.api.IsIconic(hwnd) {
+		return false
+	}
+	c := t.resolver.describe(hwnd)
+	if c.cloak != 0 || scoreWindow(c) < 0 {
+		return false
+	}
+	pid := t.resolver.windowPID(hwnd)
+	if pid == os.Getpid() {
+		return false
+	}
+
+	if !t.onMonitor(hwnd, monitor) {
+		return false
+	}
+
+	if len(matchers) == 0 {
+		return true
+	}
+	proc := models.ProcessInfo{
+		PID:         pid,
+		ImageName:   processImageName(t.api, pid),
+		WindowTitle: c.title,
+		WindowClass: c.class,
+	}
+	for _, matcher := range matchers {
+		if matcher.Match(proc) {
+			return true
+		}
+	}
+	return false
+}
+
+// onMonitor reports whether the center of a window's frame is on monitor
+func (t *tiler) onMonitor(hwnd syscall.Handle, monitor int) bool {
+	monitors, err := t.api.EnumMonitors()
+	if err != nil {
+		return false
+	}
+	frame, err := t.api.GetWindowFrameRect(hwnd)
+	return err == nil && geometry.MonitorAt(monitors, frame.ToRect()) == monitor
+}
+
+// checkTilingLayout validates a tiling layout; empty means master-stack
+func checkTilingLayout(layout models.TilingLayout) error {
+	switch layout {
+	case "", models.TilingMasterStack, models.TilingColumns, models.TilingGrid, models.TilingMonocle:
+		return nil
+	}
+	return apperrors.NewConfigError(fmt.Sprintf("unknown tiling layout %q", layout), nil)
+}
+
+// clampMasterRatio keeps the master ratio usable; 0 means the default of 0.55
+func clampMasterRatio(ratio float64) float64 {
+	if ratio == 0 {
+		return 0.55
+	}
+	return min(max(ratio, geometry.MinMasterRatio), geometry.MaxMasterRatio)
+}
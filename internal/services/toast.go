@@ -0,0 +1,38 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/go-toast/toast"
+
+	"hptools/internal/models"
+)
+
+// toastReplacer makes text safe for the PowerShell script go-toast runs: it
+// puts text inside CDATA in a double-quoted here-string, where "$" and "`"
+// are interpreted, "]]>" ends the CDATA and a line starting with `"@` ends
+// the string
+var toastReplacer = strings.NewReplacer(
+	"`", "``",
+	"$", "`$",
+	"]]>", "]] >",
+	"\r\n", " ",
+	"\n", " ",
+	"\r", " ",
+)
+
+// ShowTriggerToast shows a trigger notification as a Windows toast. It starts
+// PowerShell and can take a few seconds.
+func ShowTriggerToast(appID string, n models.TriggerNotification) error {
+	notification := toast.Notification{
+		AppID:   strings.ReplaceAll(appID, "'", "''"),
+		Title:   toastText(n.Trigger),
+		Message: toastText(n.Message),
+	}
+	return notification.Push()
+}
+
+// toastText escapes text for a toast
+func toastText(s string) string {
+	return toastReplacer.Replace(s)
+}
@@ -0,0 +1,27 @@
+package services
+
+import "testing"
+
+func TestToastText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain", "Build failed", "Build failed"},
+		{"subexpression", "$(Remove-Item C:\\)", "`$(Remove-Item C:\\)"},
+		{"variable", "cost $5", "cost `$5"},
+		{"backtick", "a`nb", "a``nb"},
+		{"end of CDATA", "x]]>y", "x]] >y"},
+		{"end of here-string", "line\n\"@\nrest", "line \"@ rest"},
+		{"windows newline", "a\r\nb", "a b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toastText(tt.text); got != tt.want {
+				t.Errorf("toastText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	apperrors "hptools/internal/errors"
+	"hptools/internal/filter"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// TriggerEventName is the frontend event that "notify" actions are posted as
+const TriggerEventName = "window-trigger"
+
+// notificationHistory is how many recent notifications are kept for the frontend
+const notificationHistory = 20
+
+// compiledTrigger is a validated trigger ready for matching
+type compiledTrigger struct {
+	models.Trigger
+	matcher   *filter.Matcher
+	on        map[models.TriggerCondition]bool
+	lastFired map[syscall.Handle]time.Time
+}
+
+// trackedWindow is a window that matches at least one trigger
+type trackedWindow struct {
+	pid     int
+	title   string
+	matched map[int]bool
+}
+
+// triggerFiring is a trigger that fired for a window, run outside the lock
+type triggerFiring struct {
+	trigger   *compiledTrigger
+	condition models.TriggerCondition
+	hwnd      syscall.Handle
+	pid       int
+	title     string
+}
+
+type triggerService struct {
+	api      *windows.API
+	windows  WindowService
+	watcher  WindowWatcher
+	resolver *windowResolver
+	notify   func(models.TriggerNotification)
+	logger   *slog.Logger
+
+	mu            sync.Mutex
+	triggers      []*compiledTrigger
+	tracked       map[syscall.Handle]*trackedWindow
+	images        map[int]string
+	notifications []models.TriggerNotification
+	unsubscribe   func()
+	paused        bool
+}
+
+// NewTriggerService creates a trigger manager driven by the watcher's window
+// events. notify receives the notifications posted by "notify" actions.
+func NewTriggerService(api *windows.API, windows WindowService, watcher WindowWatcher, notify func(models.TriggerNotification), logger *slog.Logger) TriggerManager {
+	return &triggerService{
+		api:      api,
+		windows:  windows,
+		watcher:  watcher,
+		resolver: newWindowResolver(api),
+		notify:   notify,
+		logger:   logger,
+		tracked:  make(map[syscall.Handle]*trackedWindow),
+		images:   make(map[int]string),
+	}
+}
+
+// SetTriggers validates and replaces the triggers. Windows that already match
+// a new trigger are recorded without firing it. On error the current triggers
+// are kept.
+func (t *triggerService) SetTriggers(triggers []models.Trigger) error {
+	compiled := make([]*compiledTrigger, 0, len(triggers))
+	for _, trigger := range triggers {
+		c, err := compileTrigger(trigger)
+		if err != nil {
+			return apperrors.NewConfigError(fmt.Sprintf("invalid trigger %q", trigger.Name), err)
+		}
+		compiled = append(compiled, c)
+	}
+
+	t.mu.Lock()
+	t.triggers = compiled
+	t.tracked = make(map[syscall.Handle]*trackedWindow)
+	running := t.unsubscribe != nil
+	t.mu.Unlock()
+
+	if running {
+		t.seed()
+	}
+	t.logger.Info("Window triggers set", "count", len(compiled))
+	return nil
+}
+
+// SetPaused suspends or resumes running trigger actions. Windows are still
+// tracked while paused, so resuming does not fire for windows that appeared
+// in the meantime.
+func (t *triggerService) SetPaused(paused bool) error {
+	t.mu.Lock()
+	t.paused = paused
+	t.mu.Unlock()
+	return nil
+}
+
+// ListTriggers returns the current triggers
+func (t *triggerService) ListTriggers() []models.Trigger {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	triggers := make([]models.Trigger, 0, len(t.triggers))
+	for _, c := range t.triggers {
+		triggers = append(triggers, c.Trigger)
+	}
+	return triggers
+}
+
+// Notifications returns the recent notifications of "notify" actions, oldest first
+func (t *triggerService) Notifications() []models.TriggerNotification {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]models.TriggerNotification(nil), t.notifications...)
+}
+
+// Start subscribes to window events. Windows that match when Start is called
+// are recorded without firing "appears" triggers.
+func (t *triggerService) Start() {
+	t.mu.Lock()
+	if t.unsubscribe != nil {
+		t.mu.Unlock()
+		return
+	}
+	t.unsubscribe = t.watcher.OnWindowEvent(t.handle)
+	t.mu.Unlock()
+
+	t.seed()
+}
+
+// Stop unsubscribes from window events
+func (t *triggerService) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.unsubscribe != nil {
+		t.unsubscribe()
+		t.unsubscribe = nil
+	}
+}
+
+// seed records the visible windows that match a trigger without firing
+func (t *triggerService) seed() {
+	handles, err := t.api.EnumWindows()
+	if err != nil {
+		t.logger.Warn("Failed to enumerate windows for triggers", "error", err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, hwnd := range handles {
+		if t.api.IsWindowVisible(hwnd) {
+			t.evaluate(hwnd)
+		}
+	}
+}
+
+// handle updates the tracked state of the window an event is about and runs
+// the actions of every trigger that fired
+func (t *triggerService) handle(event models.WindowEvent) {
+	hwnd := syscall.Handle(event.Handle)
+
+	var firings []triggerFiring
+	t.mu.Lock()
+	switch event.Type {
+	case models.WindowDestroyed, models.WindowHidden:
+		firings = t.gone(hwnd)
+	case models.WindowCreated, models.WindowShown, models.WindowTitleChanged:
+		if t.api.IsWindowVisible(hwnd) {
+			firings = t.evaluate(hwnd)
+		} else {
+			firings = t.gone(hwnd)
+		}
+	}
+	if t.paused {
+		firings = nil
+	}
+	t.mu.Unlock()
+
+	for _, f := range firings {
+		go t.run(f)
+	}
+}
+
+// evaluate matches a visible window against every trigger and returns the
+// triggers whose condition was met. t.mu must be held.
+func (t *triggerService) evaluate(hwnd syscall.Handle) []triggerFiring {
+	if len(t.triggers) == 0 {
+		return nil
+	}
+
+	pid := t.resolver.windowPID(hwnd)
+	proc := models.ProcessInfo{
+		PID:         pid,
+		ImageName:   t.imageName(pid),
+		WindowTitle: t.api.GetWindowText(hwnd),
+		WindowClass: t.api.GetClassName(hwnd),
+	}
+
+	prev := t.tracked[hwnd]
+	next := &trackedWindow{pid: pid, title: proc.WindowTitle, matched: make(map[int]bool)}
+
+	var firings []triggerFiring
+	for i, trigger := range t.triggers {
+		was := prev != nil && prev.matched[i]
+		now := trigger.matcher.Match(proc)
+		if now {
+			next.matched[i] = true
+		}
+
+		var condition models.TriggerCondition
+		switch {
+		case !was && now:
+			condition = models.TriggerAppears
+		case was && !now:
+			condition = models.TriggerDisappears
+		case was && now && prev.title != proc.WindowTitle:
+			condition = models.TriggerChanges
+		default:
+			continue
+		}
+		if t.fires(trigger, condition, hwnd) {
+			firings = append(firings, triggerFiring{trigger: trigger, condition: condition, hwnd: hwnd, pid: pid, title: proc.WindowTitle})
+		}
+	}
+
+	if len(next.matched) > 0 {
+		t.tracked[hwnd] = next
+	} else {
+		delete(t.tracked, hwnd)
+	}
+	return firings
+}
+
+// gone fires "disappears" for every trigger a hidden or destroyed window
+// matched. t.mu must be held.
+func (t *triggerService) gone(hwnd syscall.Handle) []triggerFiring {
+	prev, ok := t.tracked[hwnd]
+	if !ok {
+		return nil
+	}
+	delete(t.tracked, hwnd)
+
+	var firings []triggerFiring
+	for i := range prev.matched {
+		trigger := t.triggers[i]
+		if t.fires(trigger, models.TriggerDisappears, hwnd) {
+			firings = append(firings, triggerFiring{trigger: trigger, condition: models.TriggerDisappears, hwnd: hwnd, pid: prev.pid, title: prev.title})
+		}
+		delete(trigger.lastFired, hwnd)
+	}
+	return firings
+}
+
+// fires reports whether a trigger reacts to condition and is not cooling down
+// for the window, and records the firing. t.mu must be held.
+func (t *triggerService) fires(trigger *compiledTrigger, condition models.TriggerCondition, hwnd syscall.Handle) bool {
+	if !trigger.on[condition] {
+		return false
+	}
+	now := time.Now()
+	cooldown := time.Duration(trigger.CooldownMS) * time.Millisecond
+	if last, ok := trigger.lastFired[hwnd]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+	trigger.lastFired[hwnd] = now
+	return true
+}
+
+// imageName returns the executable name of a process, cached by PID.
+// t.mu must be held.
+func (t *triggerService) imageName(pid int) string {
+	if name, ok := t.images[pid]; ok {
+		return name
+	}
+
+	name := processImageName(t.api, pid)
+	// PIDs are reused, so drop the cache before it goes stale
+	if len(t.images) >= 1024 {
+		t.images = make(map[int]string)
+	}
+	t.images[pid] = name
+	return name
+}
+
+// run executes the actions of a fired trigger in order
+func (t *triggerService) run(f triggerFiring) {
+	t.logger.Info("Window trigger fired", "trigger", f.trigger.Name, "condition", f.condition, "title", f.title)
+
+	for _, action := range f.trigger.Actions {
+		if err := t.runAction(action, f); err != nil {
+			t.logger.Warn("Window trigger action failed", "trigger", f.trigger.Name, "action", action.Type, "error", err)
+		}
+	}
+}
+
+// runAction executes a single trigger action
+func (t *triggerService) runAction(action models.TriggerAction, f triggerFiring) error {
+	switch action.Type {
+	case models.TriggerFocus:
+		return t.api.FocusWindow(f.hwnd)
+	case models.TriggerPlace:
+		return applyWindowTarget(t.windows, uintptr(f.hwnd), action.WindowTarget)
+	case models.TriggerFlash:
+		t.api.FlashWindow(f.hwnd, 0)
+		return nil
+	case models.TriggerRun:
+		_, err := t.api.ShellExecute(action.Command.Path, action.Command.Args, action.Command.WorkingDir)
+		return err
+	case models.TriggerNotify:
+		n := models.TriggerNotification{
+			Trigger:   f.trigger.Name,
+			Condition: f.condition,
+			Message:   strings.ReplaceAll(action.Message, "{title}", f.title),
+			Title:     f.title,
+			Handle:    uintptr(f.hwnd),
+		}
+		t.mu.Lock()
+		t.notifications = append(t.notifications, n)
+		if len(t.notifications) > notificationHistory {
+			t.notifications = t.notifications[len(t.notifications)-notificationHistory:]
+		}
+		t.mu.Unlock()
+		if t.notify != nil {
+			t.notify(n)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown action %q", action.Type)
+}
+
+// compileTrigger validates a trigger and compiles its match criteria
+func compileTrigger(trigger models.Trigger) (*compiledTrigger, error) {
+	if trigger.Match == (models.WindowMatch{}) {
+		return nil, errors.New("no match criteria")
+	}
+	matcher, err := compileWindowMatch(trigger.Match)
+	if err != nil {
+		return nil, fmt.Errorf("invalid match: %w", err)
+	}
+
+	on := make(map[models.TriggerCondition]bool, len(trigger.On))
+	for _, condition := range trigger.On {
+		switch condition {
+		case models.TriggerAppears, models.TriggerChanges, models.TriggerDisappears:
+			on[condition] = true
+		default:
+			return nil, fmt.Errorf("unknown condition %q", condition)
+		}
+	}
+	if len(on) == 0 {
+		return nil, errors.New("no conditions")
+	}
+
+	for i, action := range trigger.Actions {
+		switch action.Type {
+		case models.TriggerFocus, models.TriggerFlash, models.TriggerNotify:
+		case models.TriggerPlace:
+			if action.WindowTarget.Empty() {
+				return nil, fmt.Errorf("action %d: place needs a placement, rect or monitor", i)
+			}
+		case models.TriggerRun:
+			if action.Command == nil || action.Command.Path == "" {
+				return nil, fmt.Errorf("action %d: run needs a command path", i)
+			}
+		default:
+			return nil, fmt.Errorf("action %d: unknown type %q", i, action.Type)
+		}
+	}
+
+	return &compiledTrigger{
+		Trigger:   trigger,
+		matcher:   matcher,
+		on:        on,
+		lastFired: make(map[syscall.Handle]time.Time),
+	}, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"hptools/internal/models"
+)
+
+func TestCompileTrigger(t *testing.T) {
+	valid := func() models.Trigger {
+		return models.Trigger{
+			Name:  "build",
+			On:    []models.TriggerCondition{models.TriggerAppears},
+			Match: models.WindowMatch{TitleRegex: "failed"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		edit    func(*models.Trigger)
+		wantErr string
+	}{
+		{name: "valid", edit: func(*models.Trigger) {}},
+		{name: "no match criteria", edit: func(tr *models.Trigger) { tr.Match = models.WindowMatch{} }, wantErr: "no match criteria"},
+		{name: "invalid regex", edit: func(tr *models.Trigger) { tr.Match.TitleRegex = "(" }, wantErr: "invalid match"},
+		{name: "no conditions", edit: func(tr *models.Trigger) { tr.On = nil }, wantErr: "no conditions"},
+		{name: "unknown condition", edit: func(tr *models.Trigger) { tr.On = []models.TriggerCondition{"resized"} }, wantErr: `unknown condition "resized"`},
+		{
+			name: "place with a placement",
+			edit: func(tr *models.Trigger) {
+				tr.Actions = []models.TriggerAction{{Type: models.TriggerPlace, WindowTarget: models.WindowTarget{Placement: models.PlacementCenter}}}
+			},
+		},
+		{
+			name:    "place without a target",
+			edit:    func(tr *models.Trigger) { tr.Actions = []models.TriggerAction{{Type: models.TriggerPlace}} },
+			wantErr: "action 0: place needs a placement, rect or monitor",
+		},
+		{
+			name: "run without a command",
+			edit: func(tr *models.Trigger) {
+				tr.Actions = []models.TriggerAction{{Type: models.TriggerFocus}, {Type: models.TriggerRun}}
+			},
+			wantErr: "action 1: run needs a command path",
+		},
+		{
+			name:    "unknown action",
+			edit:    func(tr *models.Trigger) { tr.Actions = []models.TriggerAction{{Type: "beep"}} },
+			wantErr: `action 0: unknown type "beep"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger := valid()
+			tt.edit(&trigger)
+			_, err := compileTrigger(trigger)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("compileTrigger: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("compileTrigger() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
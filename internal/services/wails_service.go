@@ -1,17 +1,23 @@
 package services
 
 import (
+	"hptools/internal/geometry"
 	"hptools/internal/models"
 )
 
 // WailsWindowService is the concrete implementation for Wails
 type WailsWindowService struct {
-	service WindowService
+	service    WindowService
+	thumbnails ThumbnailManager
+	metrics    MetricsSampler
+	launcher   Launcher
+	workspaces WorkspaceManager
+	triggers   TriggerManager
 }
 
 // NewWailsWindowService creates a new Wails-compatible service
-func NewWailsWindowService(service WindowService) *WailsWindowService {
-	return &WailsWindowService{service: service}
+func NewWailsWindowService(service WindowService, thumbnails ThumbnailManager, metrics MetricsSampler, launcher Launcher, workspaces WorkspaceManager, triggers TriggerManager) *WailsWindowService {
+	return &WailsWindowService{service: service, thumbnails: thumbnails, metrics: metrics, launcher: launcher, workspaces: workspaces, triggers: triggers}
 }
 
 // GetApplicationProcesses returns only processes that have visible windows
@@ -19,11 +25,31 @@ func (w *WailsWindowService) GetApplicationProcesses() ([]models.ProcessInfo, er
 	return w.service.GetApplicationProcesses()
 }
 
+// GetProcessesForProfile returns processes with visible windows matching the named filter profile
+func (w *WailsWindowService) GetProcessesForProfile(profile string) ([]models.ProcessInfo, error) {
+	return w.service.GetProcessesForProfile(profile)
+}
+
+// ListFilterProfiles returns the names of all process filter profiles
+func (w *WailsWindowService) ListFilterProfiles() []string {
+	return w.service.ListFilterProfiles()
+}
+
 // GetAllProcessesWithWindows returns all processes that have visible windows (for debugging)
 func (w *WailsWindowService) GetAllProcessesWithWindows() ([]models.ProcessInfo, error) {
 	return w.service.GetAllProcessesWithWindows()
 }
 
+// GetProcessDetails returns executable path, command line, parent, user and architecture for a process
+func (w *WailsWindowService) GetProcessDetails(pid int) (*models.ProcessDetails, error) {
+	return w.service.GetProcessDetails(pid)
+}
+
+// GetProcessIcon returns the PNG-encoded icon of a process's executable
+func (w *WailsWindowService) GetProcessIcon(pid int) ([]byte, error) {
+	return w.service.GetProcessIcon(pid)
+}
+
 // SetWindowSize sets the size of a window by process PID, keeping current position
 func (w *WailsWindowService) SetWindowSize(pid int, width, height int) error {
 	return w.service.SetWindowSize(pid, width, height)
@@ -38,3 +64,150 @@ func (w *WailsWindowService) SetWindowPosition(pid int, x, y, width, height int)
 func (w *WailsWindowService) GetWindowInfo(pid int) (*models.WindowInfo, error) {
 	return w.service.GetWindowInfo(pid)
 }
+
+// ApplyPlacement moves a window to a predefined placement on its monitor
+func (w *WailsWindowService) ApplyPlacement(pid int, placement models.Placement) error {
+	return w.service.ApplyPlacement(pid, placement)
+}
+
+// CyclePlacement applies the next placement of a cycle on repeated calls
+func (w *WailsWindowService) CyclePlacement(pid int, cycle []models.Placement) error {
+	return w.service.CyclePlacement(pid, cycle)
+}
+
+// SwapWindows exchanges the rects of the main windows of two processes
+func (w *WailsWindowService) SwapWindows(pidA, pidB int) error {
+	return w.service.SwapWindows(pidA, pidB)
+}
+
+// AdjustWindow moves or resizes a window relative to its current rect
+func (w *WailsWindowService) AdjustWindow(pid int, adjust models.WindowAdjustment) error {
+	return w.service.AdjustWindow(pid, adjust)
+}
+
+// GetMonitors returns all connected monitors
+func (w *WailsWindowService) GetMonitors() ([]models.MonitorInfo, error) {
+	return w.service.GetMonitors()
+}
+
+// GetVirtualDesktops returns the virtual desktops in task view order
+func (w *WailsWindowService) GetVirtualDesktops() ([]models.VirtualDesktop, error) {
+	return w.service.GetVirtualDesktops()
+}
+
+// SetWindowSizeByHandle sets the size of a specific window, keeping current position
+func (w *WailsWindowService) SetWindowSizeByHandle(handle uintptr, width, height int) error {
+	return w.service.SetWindowSizeByHandle(handle, width, height)
+}
+
+// SetWindowPositionByHandle sets position and size of a specific window
+func (w *WailsWindowService) SetWindowPositionByHandle(handle uintptr, x, y, width, height int) error {
+	return w.service.SetWindowPositionByHandle(handle, x, y, width, height)
+}
+
+// GetWindowInfoByHandle gets the current size and position of a specific window
+func (w *WailsWindowService) GetWindowInfoByHandle(handle uintptr) (*models.WindowInfo, error) {
+	return w.service.GetWindowInfoByHandle(handle)
+}
+
+// ApplyPlacementByHandle moves a specific window to a predefined placement on its monitor
+func (w *WailsWindowService) ApplyPlacementByHandle(handle uintptr, placement models.Placement) error {
+	return w.service.ApplyPlacementByHandle(handle, placement)
+}
+
+// AdjustWindowByHandle moves or resizes a specific window relative to its current rect
+func (w *WailsWindowService) AdjustWindowByHandle(handle uintptr, adjust models.WindowAdjustment) error {
+	return w.service.AdjustWindowByHandle(handle, adjust)
+}
+
+// GetWindowThumbnail returns a PNG preview of a window scaled to fit maxWidth x maxHeight
+func (w *WailsWindowService) GetWindowThumbnail(handle uintptr, maxWidth, maxHeight int) ([]byte, error) {
+	return w.thumbnails.GetThumbnail(handle, maxWidth, maxHeight)
+}
+
+// HighlightWindow briefly draws a border around the window actions by PID target
+func (w *WailsWindowService) HighlightWindow(pid int) error {
+	return w.service.HighlightWindow(pid)
+}
+
+// HighlightWindowByHandle briefly draws a border around a specific window
+func (w *WailsWindowService) HighlightWindowByHandle(handle uintptr) error {
+	return w.service.HighlightWindowByHandle(handle)
+}
+
+// PickWindow waits for the user to click a window and returns it
+func (w *WailsWindowService) PickWindow() (*models.WindowEntry, error) {
+	return w.service.PickWindow()
+}
+
+// RescueOffscreenWindows moves every unreachable window onto the primary monitor
+func (w *WailsWindowService) RescueOffscreenWindows() ([]models.WindowEntry, error) {
+	return w.service.RescueOffscreenWindows()
+}
+
+// GetDisplayFingerprint returns the fingerprint of the current monitor setup,
+// as used to associate layouts with monitor setups
+func (w *WailsWindowService) GetDisplayFingerprint() (string, error) {
+	monitors, err := w.service.GetMonitors()
+	if err != nil {
+		return "", err
+	}
+	return geometry.Fingerprint(monitors), nil
+}
+
+// EndProcess closes the windows of a process and terminates it if it does not exit
+func (w *WailsWindowService) EndProcess(pid int, dryRun bool) (*models.ProcessActionResult, error) {
+	return w.service.EndProcess(pid, dryRun)
+}
+
+// SuspendProcess suspends every thread of a process
+func (w *WailsWindowService) SuspendProcess(pid int, dryRun bool) (*models.ProcessActionResult, error) {
+	return w.service.SuspendProcess(pid, dryRun)
+}
+
+// ResumeProcess resumes a suspended process
+func (w *WailsWindowService) ResumeProcess(pid int, dryRun bool) (*models.ProcessActionResult, error) {
+	return w.service.ResumeProcess(pid, dryRun)
+}
+
+// SetProcessPriority sets the priority class of a process
+func (w *WailsWindowService) SetProcessPriority(pid int, priority models.PriorityClass, dryRun bool) (*models.ProcessActionResult, error) {
+	return w.service.SetProcessPriority(pid, priority, dryRun)
+}
+
+// SetProcessAffinity restricts a process to the CPUs set in mask
+func (w *WailsWindowService) SetProcessAffinity(pid int, mask uint64, dryRun bool) (*models.ProcessActionResult, error) {
+	return w.service.SetProcessAffinity(pid, mask, dryRun)
+}
+
+// GetTopProcesses returns the n processes using the most of a resource
+func (w *WailsWindowService) GetTopProcesses(key models.MetricKey, n int) ([]models.ProcessMetrics, error) {
+	return w.metrics.Top(key, n)
+}
+
+// GetProcessMetricsHistory returns the recent resource samples of a process, oldest first
+func (w *WailsWindowService) GetProcessMetricsHistory(pid int) []models.ProcessMetrics {
+	return w.metrics.History(pid)
+}
+
+// LaunchApplication starts an application and places its main window once it appears
+func (w *WailsWindowService) LaunchApplication(request models.LaunchRequest) (*models.LaunchResult, error) {
+	return w.launcher.Launch(request)
+}
+
+// ListWorkspaces returns all configured workspaces
+func (w *WailsWindowService) ListWorkspaces() []models.Workspace {
+	return w.workspaces.ListWorkspaces()
+}
+
+// RunWorkspace launches the missing apps of a workspace, arranges all of them
+// and reports the outcome per app
+func (w *WailsWindowService) RunWorkspace(name string) (*models.WorkspaceResult, error) {
+	return w.workspaces.RunWorkspace(name)
+}
+
+// GetTriggerNotifications returns the recent notifications of window trigger
+// "notify" actions, oldest first; new ones arrive as window-trigger events
+func (w *WailsWindowService) GetTriggerNotifications() []models.TriggerNotification {
+	return w.triggers.Notifications()
+}
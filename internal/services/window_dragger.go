@@ -0,0 +1,394 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"syscall"
+
+	apperrors "hptools/internal/errors"
+	"hptools/internal/filter"
+	"hptools/internal/geometry"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// dragState is an Alt+drag in progress
+type dragState struct {
+	hwnd   syscall.Handle
+	resize bool
+	// down is the button-down message that started the drag, up the
+	// button-up message that ends it
+	down   uint32
+	up     uint32
+	anchor models.Anchor
+	startX int32
+	startY int32
+	// x and y are the latest cursor position
+	x, y int32
+	// ready is set once prepare has checked and measured the window; until
+	// then cursor moves are only recorded. cancelled is set when the window
+	// may not be dragged and the swallowed press was replayed.
+	ready     bool
+	cancelled bool
+	released  bool
+	// frame is the visible frame at the start, last the most recent one;
+	// inset is how far the window rect extends beyond the frame
+	frame models.Rect
+	last  models.Rect
+	inset models.RECT
+	// env is what the frame snaps to, filled in once collected
+	env geometry.SnapEnv
+}
+
+type windowDragger struct {
+	api      *windows.API
+	resolver *windowResolver
+	logger   *slog.Logger
+
+	// hookMu serializes installing and removing the hook; it is separate
+	// from mu because the hook callback takes mu
+	hookMu sync.Mutex
+
+	mu       sync.Mutex
+	options  models.DragOptions
+	paused   bool
+	excluded []*filter.Matcher
+	drag     *dragState
+	stopHook func()
+}
+
+// NewWindowDragger creates the Alt+drag controller; it is idle until enabled
+// with SetOptions
+func NewWindowDragger(api *windows.API, logger *slog.Logger) WindowDragger {
+	return &windowDragger{
+		api:      api,
+		resolver: newWindowResolver(api),
+		logger:   logger,
+	}
+}
+
+// SetOptions validates and applies the options, installing or removing the
+// mouse hook as needed. On error the current options are kept.
+func (d *windowDragger) SetOptions(options models.DragOptions) error {
+	if err := checkOverlapPolicy(options.Overlap); err != nil {
+		return err
+	}
+	excluded := make([]*filter.Matcher, 0, len(options.Exclude))
+	for i, match := range options.Exclude {
+		matcher, err := compileWindowMatch(match)
+		if err != nil {
+			return apperrors.NewConfigError(fmt.Sprintf("invalid drag exclusion %d", i), err)
+		}
+		excluded = append(excluded, matcher)
+	}
+
+	d.mu.Lock()
+	d.options = options
+	d.excluded = excluded
+	d.mu.Unlock()
+	return d.sync()
+}
+
+// SetPaused suspends or resumes dragging without changing the options
+func (d *windowDragger) SetPaused(paused bool) error {
+	d.mu.Lock()
+	d.paused = paused
+	d.mu.Unlock()
+	return d.sync()
+}
+
+// sync installs or removes the mouse hook to match the options and pause
+// state
+func (d *windowDragger) sync() error {
+	d.hookMu.Lock()
+	defer d.hookMu.Unlock()
+
+	d.mu.Lock()
+	options := d.options
+	active := options.Enabled && !d.paused
+	running := d.stopHook != nil
+	d.mu.Unlock()
+
+	switch {
+	case active && !running:
+		stop, err := d.api.WatchMouse(d.onMouse)
+		if err != nil {
+			return fmt.Errorf("installing mouse hook: %w", err)
+		}
+		d.mu.Lock()
+		d.stopHook = stop
+		d.mu.Unlock()
+		d.logger.Info("Window dragging enabled", "snapThreshold", options.SnapThreshold)
+	case !active && running:
+		d.removeHook()
+		d.logger.Info("Window dragging disabled")
+	}
+	return nil
+}
+
+// Stop removes the mouse hook and ends any drag in progress
+func (d *windowDragger) Stop() {
+	d.hookMu.Lock()
+	defer d.hookMu.Unlock()
+	d.removeHook()
+}
+
+// removeHook removes the mouse hook and ends any drag in progress.
+// d.hookMu must be held.
+func (d *windowDragger) removeHook() {
+	d.mu.Lock()
+	stop := d.stopHook
+	d.stopHook = nil
+	d.drag = nil
+	d.mu.Unlock()
+
+	// The hook callback takes d.mu, so remove the hook without holding it
+	if stop != nil {
+		stop()
+	}
+}
+
+// onMouse runs on the hook thread for every mouse event and reports whether
+// the event is swallowed
+func (d *windowDragger) onMouse(e windows.MouseEvent) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	drag := d.drag
+	switch e.Message {
+	case windows.WM_LBUTTONDOWN, windows.WM_RBUTTONDOWN:
+		if drag != nil {
+			return true
+		}
+		if !d.api.IsAltDown() {
+			return false
+		}
+		return d.begin(e)
+	case windows.WM_MOUSEMOVE:
+		if drag != nil {
+			drag.x, drag.y = e.X, e.Y
+			if drag.ready {
+				d.update(drag)
+			}
+		}
+		return false
+	case windows.WM_LBUTTONUP, windows.WM_RBUTTONUP:
+		if drag == nil {
+			return false
+		}
+		if e.Message == drag.up {
+			switch {
+			case drag.cancelled:
+				// The press was replayed, so the release follows it
+				d.api.SendMouseButton(e.Message)
+			case drag.ready:
+				d.finish(drag)
+			}
+			drag.released = true
+			d.drag = nil
+		}
+		return true
+	}
+	return false
+}
+
+// begin claims the press for a drag of the window under the cursor. Checking
+// the window can wait on other processes, so prepare does it off the hook
+// thread. d.mu must be held.
+func (d *windowDragger) begin(e windows.MouseEvent) bool {
+	hwnd := d.api.GetRootWindow(d.api.WindowFromPoint(e.X, e.Y))
+	if hwnd == 0 {
+		return false
+	}
+
+	drag := &dragState{
+		hwnd:   hwnd,
+		resize: e.Message == windows.WM_RBUTTONDOWN,
+		down:   e.Message,
+		up:     windows.WM_LBUTTONUP,
+		startX: e.X,
+		startY: e.Y,
+		x:      e.X,
+		y:      e.Y,
+	}
+	if drag.resize {
+		drag.up = windows.WM_RBUTTONUP
+	}
+
+	d.drag = drag
+	go d.prepare(drag)
+	return true
+}
+
+// update moves or resizes the dragged window to follow the cursor.
+// d.mu must be held.
+func (d *windowDragger) update(drag *dragState) {
+	dx, dy := int(drag.x-drag.startX), int(drag.y-drag.startY)
+	// Overlaps are resolved once on release so the window follows the cursor
+	options := models.SnapOptions{Threshold: d.options.SnapThreshold}
+
+	rect := drag.frame
+	if drag.resize {
+		rect = resizeFrom(drag.frame, drag.anchor, dx, dy)
+		if snapped, err := geometry.SnapResized(rect, drag.anchor, drag.env, options); err == nil {
+			rect = snapped
+		}
+	} else {
+		rect.X += dx
+		rect.Y += dy
+		rect = geometry.Snap(rect, drag.env, options)
+	}
+	d.move(drag, rect)
+}
+
+// finish applies the overlap policy to the dropped window. d.mu must be held.
+func (d *windowDragger) finish(drag *dragState) {
+	policy := d.options.Overlap
+	if drag.resize && policy == models.OverlapAvoid {
+		policy = models.OverlapShrink
+	}
+	if rect := geometry.ResolveOverlap(drag.last, drag.env, policy); rect != drag.last {
+		d.move(drag, rect)
+	}
+}
+
+// move sets the visible frame of the dragged window without waiting for the
+// window's thread. d.mu must be held.
+func (d *windowDragger) move(drag *dragState, frame models.Rect) {
+	flags := uint32(windows.SWP_NOZORDER | windows.SWP_NOACTIVATE | windows.SWP_ASYNCWINDOWPOS)
+	if frame.Width == drag.last.Width && frame.Height == drag.last.Height {
+		flags |= windows.SWP_NOSIZE
+	}
+	drag.last = frame
+
+	rect := fromFrame(frame, drag.inset)
+	d.api.SetWindowPos(drag.hwnd, rect.X, rect.Y, rect.Width, rect.Height, flags)
+}
+
+// prepare checks and measures the dragged window, then brings it to the front
+// and collects the edges it snaps to. It runs off the hook thread, which must
+// never wait on other windows.
+func (d *windowDragger) prepare(drag *dragState) {
+	d.mu.Lock()
+	excluded := d.excluded
+	d.mu.Unlock()
+
+	frameRect, err := d.api.GetWindowFrameRect(drag.hwnd)
+	if err != nil || !d.draggable(drag.hwnd, excluded) {
+		d.cancel(drag)
+		return
+	}
+	inset := frameInset(d.api, drag.hwnd)
+
+	d.mu.Lock()
+	// Stop may have ended the drag in the meantime
+	if d.drag != drag && !drag.released {
+		d.mu.Unlock()
+		return
+	}
+	drag.frame = frameRect.ToRect()
+	drag.last = drag.frame
+	drag.inset = inset
+	if drag.resize {
+		drag.anchor = resizeAnchor(drag.frame, int(drag.startX), int(drag.startY))
+	}
+	drag.ready = true
+	// Catch up with the cursor, and with the release if it came first
+	if drag.x != drag.startX || drag.y != drag.startY {
+		d.update(drag)
+	}
+	if drag.released {
+		d.finish(drag)
+	}
+	d.mu.Unlock()
+
+	d.api.SuppressAltMenu()
+	if err := d.api.FocusWindow(drag.hwnd); err != nil {
+		d.logger.Debug("Failed to focus dragged window", "hwnd", drag.hwnd, "error", err)
+	}
+
+	d.resolver.desktop.invalidate()
+	env, err := collectSnapEnv(d.api, d.resolver, drag.hwnd)
+	if err != nil {
+		d.logger.Debug("Failed to collect snap targets", "error", err)
+		return
+	}
+
+	d.mu.Lock()
+	drag.env = env
+	d.mu.Unlock()
+}
+
+// cancel gives up a drag of a window that may not be dragged and replays the
+// press it swallowed. A release that was swallowed too is replayed after it;
+// otherwise onMouse replays the release when it comes.
+func (d *windowDragger) cancel(drag *dragState) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.drag != drag && !drag.released {
+		return
+	}
+	drag.cancelled = true
+	d.api.SendMouseButton(drag.down)
+	if drag.released {
+		d.api.SendMouseButton(drag.up)
+	}
+}
+
+// draggable reports whether a window may be dragged: it must be a window that
+// could be a main window, not maximized and not excluded
+func (d *windowDragger) draggable(hwnd syscall.Handle, excluded []*filter.Matcher) bool {
+	c := d.resolver.describe(hwnd)
+	if scoreWindow(c) < 0 || d.api.IsZoomed(hwnd) {
+		return false
+	}
+	if len(excluded) == 0 {
+		return true
+	}
+
+	proc := models.ProcessInfo{
+		ImageName:   processImageName(d.api, d.resolver.windowPID(hwnd)),
+		WindowTitle: c.title,
+		WindowClass: c.class,
+	}
+	for _, matcher := range excluded {
+		if matcher.Match(proc) {
+			return false
+		}
+	}
+	return true
+}
+
+// resizeAnchor picks the corner opposite the quarter of the frame the drag
+// started in, so the nearest corner follows the cursor
+func resizeAnchor(frame models.Rect, x, y int) models.Anchor {
+	left := x < frame.X+frame.Width/2
+	top := y < frame.Y+frame.Height/2
+	switch {
+	case left && top:
+		return models.AnchorBottomRight
+	case left:
+		return models.AnchorTopRight
+	case top:
+		return models.AnchorBottomLeft
+	}
+	return models.AnchorTopLeft
+}
+
+// resizeFrom moves the corner opposite anchor by dx, dy, keeping a usable size
+func resizeFrom(frame models.Rect, anchor models.Anchor, dx, dy int) models.Rect {
+	left, top, right, bottom := frame.X, frame.Y, frame.Right(), frame.Bottom()
+	switch anchor {
+	case models.AnchorBottomRight:
+		left, top = min(left+dx, right-minMainWindowSize), min(top+dy, bottom-minMainWindowSize)
+	case models.AnchorTopRight:
+		left, bottom = min(left+dx, right-minMainWindowSize), max(bottom+dy, top+minMainWindowSize)
+	case models.AnchorBottomLeft:
+		right, top = max(right+dx, left+minMainWindowSize), min(top+dy, bottom-minMainWindowSize)
+	default:
+		right, bottom = max(right+dx, left+minMainWindowSize), max(bottom+dy, top+minMainWindowSize)
+	}
+	return models.Rect{X: left, Y: top, Width: right - left, Height: bottom - top}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"syscall"
+	"time"
+
+	apperrors "hptools/internal/errors"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+const (
+	// highlightDuration is how long the highlight border stays visible
+	highlightDuration = 1500 * time.Millisecond
+	// highlightThickness is the width of the highlight border in pixels
+	highlightThickness = 6
+	// pickTimeout is how long PickWindow waits for a click
+	pickTimeout = 30 * time.Second
+)
+
+type windowIdentifier struct {
+	api      *windows.API
+	resolver *windowResolver
+	logger   *slog.Logger
+}
+
+func newWindowIdentifier(api *windows.API, resolver *windowResolver, logger *slog.Logger) *windowIdentifier {
+	return &windowIdentifier{api: api, resolver: resolver, logger: logger}
+}
+
+// HighlightWindow briefly draws a border around the main window of a process,
+// which is the window every other action by PID targets
+func (i *windowIdentifier) HighlightWindow(pid int) error {
+	main, err := i.resolver.mainWindow(pid)
+	if err != nil {
+		return fmt.Errorf(errFindingWindowForPID, pid, err)
+	}
+	return i.highlight(main.hwnd)
+}
+
+// HighlightWindowByHandle briefly draws a border around a specific window,
+// e.g. one of several windows of a process
+func (i *windowIdentifier) HighlightWindowByHandle(handle uintptr) error {
+	if handle == 0 || !i.api.IsWindow(syscall.Handle(handle)) {
+		return apperrors.NewWindowError(fmt.Sprintf("window %#x no longer exists", handle), nil)
+	}
+	return i.highlight(syscall.Handle(handle))
+}
+
+// highlight draws the highlight border around a window's visible frame
+func (i *windowIdentifier) highlight(hwnd syscall.Handle) error {
+	rect, err := i.api.GetWindowFrameRect(hwnd)
+	if err != nil {
+		return fmt.Errorf("getting window frame: %w", err)
+	}
+
+	if err := i.api.ShowFrameOverlay(*rect, highlightThickness, highlightDuration); err != nil {
+		return apperrors.NewWindowError("failed to highlight window", err)
+	}
+	return nil
+}
+
+// PickWindow waits for the next click and returns the top-level window under
+// the cursor. A right click cancels the pick.
+func (i *windowIdentifier) PickWindow() (*models.WindowEntry, error) {
+	hwnd, err := i.api.PickWindow(pickTimeout)
+	switch {
+	case errors.Is(err, windows.ErrPickCancelled), errors.Is(err, windows.ErrPickTimeout):
+		return nil, apperrors.NewWindowError("no window picked", err)
+	case err != nil:
+		return nil, fmt.Errorf("picking window: %w", err)
+	}
+
+	entry := i.entry(hwnd)
+	i.logger.Debug("Window picked", "handle", entry.Handle, "pid", entry.PID, "title", entry.Title)
+	return &entry, nil
+}
+
+// entry describes a window for the frontend
+func (i *windowIdentifier) entry(hwnd syscall.Handle) models.WindowEntry {
+	c := i.resolver.describe(hwnd)
+	appID, err := i.resolver.appID(hwnd)
+	if err != nil {
+		i.logger.Debug("Failed to get window AppUserModelID", "handle", uintptr(hwnd), "error", err)
+	}
+	return models.WindowEntry{
+		Handle: uintptr(hwnd),
+		PID:    i.resolver.windowPID(hwnd),
+		Title:  c.title,
+		Class:  c.class,
+		AppID:  appID,
+	}
+}
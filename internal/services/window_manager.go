@@ -1,132 +1,423 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
-	"strings"
 	"syscall"
 
+	apperrors "hptools/internal/errors"
+	"hptools/internal/events"
+	"hptools/internal/geometry"
 	"hptools/internal/models"
 	"hptools/internal/windows"
 )
 
 const errFindingWindowForPID = "finding window for PID %d: %w"
 
+// recentWindowLimit is the number of recently managed windows to remember
+const recentWindowLimit = 5
+
 type windowManager struct {
-	api    *windows.API
-	logger *slog.Logger
+	api      *windows.API
+	resolver *windowResolver
+	bounds   *boundsGuard
+	logger   *slog.Logger
+
+	recent *events.RecentWindows
+	cycles *geometry.CycleTracker
 }
 
 // NewWindowManager creates a new window manager
 func NewWindowManager(api *windows.API, logger *slog.Logger) WindowManager {
+	resolver := newWindowResolver(api)
+	return newWindowManager(api, resolver, newBoundsGuard(api, resolver, logger), logger)
+}
+
+// newWindowManager creates a window manager that shares a window resolver and bounds guard
+func newWindowManager(api *windows.API, resolver *windowResolver, bounds *boundsGuard, logger *slog.Logger) *windowManager {
 	return &windowManager{
-		api:    api,
-		logger: logger,
+		api:      api,
+		resolver: resolver,
+		bounds:   bounds,
+		logger:   logger,
+		recent:   events.NewRecentWindows(recentWindowLimit),
+		cycles:   geometry.NewCycleTracker(),
 	}
 }
 
 // SetWindowSize sets the size of a window by process PID, keeping current position
+// unless snapping or the bounds policy moves it
 func (w *windowManager) SetWindowSize(pid int, width, height int) error {
 	hwnd, err := w.FindWindowByPID(pid)
 	if err != nil {
 		return fmt.Errorf(errFindingWindowForPID, pid, err)
 	}
+	return w.setWindowSize(pid, syscall.Handle(hwnd), width, height)
+}
+
+// SetWindowSizeByHandle sets the size of a specific window, keeping current position
+func (w *windowManager) SetWindowSizeByHandle(handle uintptr, width, height int) error {
+	pid, err := w.windowOwner(handle)
+	if err != nil {
+		return err
+	}
+	return w.setWindowSize(pid, syscall.Handle(handle), width, height)
+}
+
+// setWindowSize applies SetWindowSize to a resolved window
+func (w *windowManager) setWindowSize(pid int, hwnd syscall.Handle, width, height int) error {
+	rect, err := w.api.GetWindowRect(hwnd)
+	if err != nil {
+		return fmt.Errorf("getting window rect: %w", err)
+	}
+
+	requested := w.bounds.snapResize(hwnd, models.Rect{X: int(rect.Left), Y: int(rect.Top), Width: width, Height: height}, models.AnchorTopLeft)
+	target, err := w.bounds.confine(hwnd, requested)
+	if err != nil {
+		return err
+	}
 
 	err = w.api.SetWindowPos(
-		syscall.Handle(hwnd),
-		0, 0, width, height,
-		windows.SWP_NOMOVE|windows.SWP_NOZORDER|windows.SWP_NOACTIVATE,
+		hwnd,
+		target.X, target.Y, target.Width, target.Height,
+		windows.SWP_NOZORDER|windows.SWP_NOACTIVATE,
 	)
 	if err != nil {
 		return fmt.Errorf("setting window size: %w", err)
 	}
 
-	w.logger.Info("Window size changed", "pid", pid, "width", width, "height", height)
+	w.recordRecent(pid, uintptr(hwnd))
+	w.logger.Info("Window size changed", "pid", pid, "width", target.Width, "height", target.Height)
 	return nil
 }
 
-// SetWindowPosition sets both position and size of a window
+// SetWindowPosition sets both position and size of a window, subject to
+// snapping and the bounds policy
 func (w *windowManager) SetWindowPosition(pid int, x, y, width, height int) error {
 	hwnd, err := w.FindWindowByPID(pid)
 	if err != nil {
 		return fmt.Errorf(errFindingWindowForPID, pid, err)
 	}
+	return w.setWindowPosition(pid, syscall.Handle(hwnd), x, y, width, height)
+}
+
+// SetWindowPositionByHandle sets position and size of a specific window
+func (w *windowManager) SetWindowPositionByHandle(handle uintptr, x, y, width, height int) error {
+	pid, err := w.windowOwner(handle)
+	if err != nil {
+		return err
+	}
+	return w.setWindowPosition(pid, syscall.Handle(handle), x, y, width, height)
+}
+
+// setWindowPosition applies SetWindowPosition to a resolved window
+func (w *windowManager) setWindowPosition(pid int, hwnd syscall.Handle, x, y, width, height int) error {
+	requested := w.bounds.snapMove(hwnd, models.Rect{X: x, Y: y, Width: width, Height: height})
+	target, err := w.bounds.confine(hwnd, requested)
+	if err != nil {
+		return err
+	}
 
 	err = w.api.SetWindowPos(
-		syscall.Handle(hwnd),
-		x, y, width, height,
+		hwnd,
+		target.X, target.Y, target.Width, target.Height,
 		windows.SWP_NOZORDER|windows.SWP_NOACTIVATE,
 	)
 	if err != nil {
 		return fmt.Errorf("setting window position: %w", err)
 	}
 
-	w.logger.Info("Window position changed", "pid", pid, "x", x, "y", y, "width", width, "height", height)
+	w.recordRecent(pid, uintptr(hwnd))
+	w.logger.Info("Window position changed", "pid", pid, "x", target.X, "y", target.Y, "width", target.Width, "height", target.Height)
 	return nil
 }
 
-// GetWindowInfo gets the current size and position of a window
-func (w *windowManager) GetWindowInfo(pid int) (*models.WindowInfo, error) {
+// ApplyPlacement moves a window to a predefined placement on its monitor
+func (w *windowManager) ApplyPlacement(pid int, placement models.Placement) error {
 	hwnd, err := w.FindWindowByPID(pid)
 	if err != nil {
-		return nil, fmt.Errorf(errFindingWindowForPID, pid, err)
+		return fmt.Errorf(errFindingWindowForPID, pid, err)
+	}
+	return w.applyPlacement(pid, syscall.Handle(hwnd), placement)
+}
+
+// ApplyPlacementByHandle moves a specific window to a predefined placement on its monitor
+func (w *windowManager) ApplyPlacementByHandle(handle uintptr, placement models.Placement) error {
+	pid, err := w.windowOwner(handle)
+	if err != nil {
+		return err
+	}
+	return w.applyPlacement(pid, syscall.Handle(handle), placement)
+}
+
+// applyPlacement applies ApplyPlacement to a resolved window
+func (w *windowManager) applyPlacement(pid int, hwnd syscall.Handle, placement models.Placement) error {
+	rect, err := w.api.GetWindowRect(hwnd)
+	if err != nil {
+		return fmt.Errorf("getting window rect: %w", err)
+	}
+
+	if _, err := w.place(hwnd, placement, rect.ToRect()); err != nil {
+		return err
+	}
+
+	w.recordRecent(pid, uintptr(hwnd))
+	w.logger.Info("Window placement applied", "pid", pid, "placement", placement)
+	return nil
+}
+
+// CyclePlacement applies the next placement of a cycle. Repeated calls for
+// the same window step through the cycle as long as the window stays where
+// the previous step put it; otherwise the cycle starts over.
+func (w *windowManager) CyclePlacement(pid int, cycle []models.Placement) error {
+	if len(cycle) == 0 {
+		return errors.New("empty placement cycle")
+	}
+
+	hwnd, err := w.FindWindowByPID(pid)
+	if err != nil {
+		return fmt.Errorf(errFindingWindowForPID, pid, err)
 	}
 
 	rect, err := w.api.GetWindowRect(syscall.Handle(hwnd))
 	if err != nil {
-		return nil, fmt.Errorf("getting window rect: %w", err)
+		return fmt.Errorf("getting window rect: %w", err)
 	}
 
-	return &models.WindowInfo{
-		X:      int(rect.Left),
-		Y:      int(rect.Top),
-		Width:  int(rect.Right - rect.Left),
-		Height: int(rect.Bottom - rect.Top),
-	}, nil
+	index := w.cycles.Next(hwnd, cycle, rect.ToRect())
+	target, err := w.place(syscall.Handle(hwnd), cycle[index], rect.ToRect())
+	if err != nil {
+		return err
+	}
+	w.cycles.Record(hwnd, cycle, index, target)
+
+	w.recordRecent(pid, hwnd)
+	w.logger.Info("Window placement cycled", "pid", pid, "placement", cycle[index], "step", index)
+	return nil
 }
 
-// FindWindowByPID finds window handle by PID
-func (w *windowManager) FindWindowByPID(targetPID int) (uintptr, error) {
-	var foundWindows []syscall.Handle
+// place moves a window from current to a placement on its monitor and
+// returns the applied rect
+func (w *windowManager) place(hwnd syscall.Handle, placement models.Placement, current models.Rect) (models.Rect, error) {
+	monitors, err := w.api.EnumMonitors()
+	if err != nil {
+		return models.Rect{}, fmt.Errorf("enumerating monitors: %w", err)
+	}
 
-	// Callback function for EnumWindows
-	enumProc := syscall.NewCallback(func(hwnd syscall.Handle, lParam uintptr) uintptr {
-		// Check if window is visible
-		if !w.api.IsWindowVisible(hwnd) {
-			return 1 // Continue enumeration for invisible windows
-		}
+	hmon := w.api.MonitorFromWindow(hwnd)
+	target, err := geometry.Place(placement, current, monitors, geometry.FindMonitor(monitors, uintptr(hmon)))
+	if err != nil {
+		return models.Rect{}, fmt.Errorf("computing placement: %w", err)
+	}
+
+	err = w.api.SetWindowPos(
+		hwnd,
+		target.X, target.Y, target.Width, target.Height,
+		windows.SWP_NOZORDER|windows.SWP_NOACTIVATE,
+	)
+	if err != nil {
+		return models.Rect{}, fmt.Errorf("setting window position: %w", err)
+	}
+	return target, nil
+}
+
+// SwapWindows exchanges the rects of the main windows of two processes
+func (w *windowManager) SwapWindows(pidA, pidB int) error {
+	hwndA, err := w.FindWindowByPID(pidA)
+	if err != nil {
+		return fmt.Errorf(errFindingWindowForPID, pidA, err)
+	}
+	hwndB, err := w.FindWindowByPID(pidB)
+	if err != nil {
+		return fmt.Errorf(errFindingWindowForPID, pidB, err)
+	}
+	if hwndA == hwndB {
+		return errors.New("cannot swap a window with itself")
+	}
+
+	rectA, err := w.api.GetWindowRect(syscall.Handle(hwndA))
+	if err != nil {
+		return fmt.Errorf("getting window rect: %w", err)
+	}
+	rectB, err := w.api.GetWindowRect(syscall.Handle(hwndB))
+	if err != nil {
+		return fmt.Errorf("getting window rect: %w", err)
+	}
 
-		pid := w.api.GetWindowThreadProcessId(hwnd)
-		if int(pid) == targetPID {
-			title := w.api.GetWindowText(hwnd)
-			// Filter out system windows and empty titles
-			if title != "" && title != "Default IME" && title != "MSCTFIME UI" &&
-				!strings.Contains(title, "Program Manager") {
-				foundWindows = append(foundWindows, hwnd)
-			}
+	for _, move := range []struct {
+		hwnd uintptr
+		rect models.Rect
+	}{{hwndA, rectB.ToRect()}, {hwndB, rectA.ToRect()}} {
+		err := w.api.SetWindowPos(
+			syscall.Handle(move.hwnd),
+			move.rect.X, move.rect.Y, move.rect.Width, move.rect.Height,
+			windows.SWP_NOZORDER|windows.SWP_NOACTIVATE,
+		)
+		if err != nil {
+			return fmt.Errorf("setting window position: %w", err)
 		}
-		return 1 // Continue enumeration
+	}
+
+	w.recordRecent(pidB, hwndB)
+	w.recordRecent(pidA, hwndA)
+	w.logger.Info("Windows swapped", "pidA", pidA, "pidB", pidB)
+	return nil
+}
+
+// AdjustWindow moves or resizes a window relative to its current rect,
+// snapped to nearby edges and clamped to the work area of the monitor it is on
+func (w *windowManager) AdjustWindow(pid int, adjust models.WindowAdjustment) error {
+	hwnd, err := w.FindWindowByPID(pid)
+	if err != nil {
+		return fmt.Errorf(errFindingWindowForPID, pid, err)
+	}
+	return w.adjustWindow(pid, syscall.Handle(hwnd), adjust)
+}
+
+// AdjustWindowByHandle moves or resizes a specific window relative to its current rect
+func (w *windowManager) AdjustWindowByHandle(handle uintptr, adjust models.WindowAdjustment) error {
+	pid, err := w.windowOwner(handle)
+	if err != nil {
+		return err
+	}
+	return w.adjustWindow(pid, syscall.Handle(handle), adjust)
+}
+
+// adjustWindow applies AdjustWindow to a resolved window
+func (w *windowManager) adjustWindow(pid int, hwnd syscall.Handle, adjust models.WindowAdjustment) error {
+	rect, err := w.api.GetWindowRect(hwnd)
+	if err != nil {
+		return fmt.Errorf("getting window rect: %w", err)
+	}
+
+	hmon := w.api.MonitorFromWindow(hwnd)
+	monitor, err := w.api.GetMonitorInfo(hmon)
+	if err != nil {
+		return fmt.Errorf("getting monitor info: %w", err)
+	}
+
+	// The visible frame is clamped, so the invisible resize borders may
+	// extend past the work area like they do for a maximized window
+	inset := frameInset(w.api, hwnd)
+	frame, err := geometry.Adjust(adjust, toFrame(rect.ToRect(), inset), monitor.WorkArea)
+	if err != nil {
+		return fmt.Errorf("computing adjustment: %w", err)
+	}
+	target := fromFrame(frame, inset)
+
+	// Aspect fits keep their exact size; snapping an edge would break the ratio
+	switch adjust.Kind {
+	case models.AdjustMoveBy:
+		target = w.bounds.snapMove(hwnd, target)
+	case models.AdjustResizeBy, models.AdjustScale:
+		target = w.bounds.snapResize(hwnd, target, adjust.Anchor)
+	}
+
+	err = w.api.SetWindowPos(
+		hwnd,
+		target.X, target.Y, target.Width, target.Height,
+		windows.SWP_NOZORDER|windows.SWP_NOACTIVATE,
+	)
+	if err != nil {
+		return fmt.Errorf("setting window position: %w", err)
+	}
+
+	w.recordRecent(pid, uintptr(hwnd))
+	w.logger.Info("Window adjusted", "pid", pid, "kind", adjust.Kind, "x", target.X, "y", target.Y, "width", target.Width, "height", target.Height)
+	return nil
+}
+
+// GetMonitors returns all connected monitors
+func (w *windowManager) GetMonitors() ([]models.MonitorInfo, error) {
+	monitors, err := w.api.EnumMonitors()
+	if err != nil {
+		return nil, fmt.Errorf("enumerating monitors: %w", err)
+	}
+	return monitors, nil
+}
+
+// GetVirtualDesktops returns the virtual desktops in task view order
+func (w *windowManager) GetVirtualDesktops() ([]models.VirtualDesktop, error) {
+	desktops, err := w.api.GetVirtualDesktops()
+	if err != nil {
+		return nil, fmt.Errorf("listing virtual desktops: %w", err)
+	}
+	return desktops, nil
+}
+
+// RecentWindows returns the most recently managed windows, newest first
+func (w *windowManager) RecentWindows() []models.RecentWindow {
+	return w.recent.List()
+}
+
+// OnRecentWindowsChanged registers a listener called when the recent list changes.
+// It returns a function that removes the listener.
+func (w *windowManager) OnRecentWindowsChanged(fn func()) func() {
+	return w.recent.Subscribe(fn)
+}
+
+// recordRecent moves the window to the front of the recent list
+func (w *windowManager) recordRecent(pid int, hwnd uintptr) {
+	w.recent.Record(models.RecentWindow{
+		PID:   pid,
+		Title: w.api.GetWindowText(syscall.Handle(hwnd)),
 	})
+}
+
+// GetWindowInfo gets the current size and position of a window
+func (w *windowManager) GetWindowInfo(pid int) (*models.WindowInfo, error) {
+	hwnd, err := w.FindWindowByPID(pid)
+	if err != nil {
+		return nil, fmt.Errorf(errFindingWindowForPID, pid, err)
+	}
+	return w.getWindowInfo(pid, syscall.Handle(hwnd))
+}
 
-	if err := w.api.EnumWindows(enumProc); err != nil {
-		return 0, fmt.Errorf("enumerating windows: %w", err)
+// GetWindowInfoByHandle gets the current size and position of a specific window
+func (w *windowManager) GetWindowInfoByHandle(handle uintptr) (*models.WindowInfo, error) {
+	pid, err := w.windowOwner(handle)
+	if err != nil {
+		return nil, err
 	}
+	return w.getWindowInfo(pid, syscall.Handle(handle))
+}
 
-	if len(foundWindows) == 0 {
-		return 0, fmt.Errorf("no visible window found for PID %d", targetPID)
+// getWindowInfo applies GetWindowInfo to a resolved window
+func (w *windowManager) getWindowInfo(pid int, hwnd syscall.Handle) (*models.WindowInfo, error) {
+	rect, err := w.api.GetWindowRect(hwnd)
+	if err != nil {
+		return nil, fmt.Errorf("getting window rect: %w", err)
 	}
 
-	// If multiple windows, try to find the main window
-	if len(foundWindows) > 1 {
-		for _, hwnd := range foundWindows {
-			title := w.api.GetWindowText(hwnd)
-			// Prefer windows with meaningful titles (not just filenames)
-			if len(title) > 10 && !strings.HasSuffix(strings.ToLower(title), ".exe") {
-				return uintptr(hwnd), nil
-			}
-		}
+	desktopID, err := w.api.GetWindowDesktopID(hwnd)
+	if err != nil {
+		w.logger.Debug("Failed to get window desktop", "pid", pid, "error", err)
 	}
 
-	// Return the first window found
-	return uintptr(foundWindows[0]), nil
+	return &models.WindowInfo{
+		X:         int(rect.Left),
+		Y:         int(rect.Top),
+		Width:     int(rect.Right - rect.Left),
+		Height:    int(rect.Bottom - rect.Top),
+		DesktopID: desktopID,
+	}, nil
+}
+
+// windowOwner returns the process of a window given by handle, failing when
+// the window has been closed since the caller looked it up
+func (w *windowManager) windowOwner(handle uintptr) (int, error) {
+	if handle == 0 || !w.api.IsWindow(syscall.Handle(handle)) {
+		return 0, apperrors.NewWindowError(fmt.Sprintf("window %#x no longer exists", handle), nil)
+	}
+	return w.resolver.windowPID(syscall.Handle(handle)), nil
+}
+
+// FindWindowByPID finds the main window handle of a process
+func (w *windowManager) FindWindowByPID(targetPID int) (uintptr, error) {
+	main, err := w.resolver.mainWindow(targetPID)
+	if err != nil {
+		return 0, err
+	}
+	return uintptr(main.hwnd), nil
 }
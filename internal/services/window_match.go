@@ -0,0 +1,41 @@
+package services
+
+import (
+	"path/filepath"
+	"syscall"
+
+	"hptools/internal/filter"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// compileWindowMatch prepares window match criteria as a single include rule
+// of a filter profile, so they behave exactly like filter rules
+func compileWindowMatch(match models.WindowMatch) (*filter.Matcher, error) {
+	return filter.Compile(models.FilterProfile{
+		Name:    "match",
+		Default: models.FilterExclude,
+		Rules: []models.FilterRule{{
+			Action:      models.FilterInclude,
+			ImageGlob:   match.ImageGlob,
+			WindowClass: match.WindowClass,
+			TitleRegex:  match.TitleRegex,
+		}},
+	})
+}
+
+// processImageName returns the executable name of a process, or "" if the
+// process cannot be queried
+func processImageName(api *windows.API, pid int) string {
+	h, err := api.OpenProcess(pid)
+	if err != nil {
+		return ""
+	}
+	defer syscall.CloseHandle(h)
+
+	path, err := api.QueryFullProcessImageName(h)
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(path)
+}
@@ -10,12 +10,19 @@ import (
 type combinedWindowService struct {
 	ProcessManager
 	WindowManager
+	WindowIdentifier
+	BoundsGuard
 }
 
-// NewWindowService creates a new combined window service
+// NewWindowService creates a new combined window service.
+// All parts share one window resolver and its desktop snapshot cache.
 func NewWindowService(api *windows.API, logger *slog.Logger) WindowService {
+	resolver := newWindowResolver(api)
+	bounds := newBoundsGuard(api, resolver, logger)
 	return &combinedWindowService{
-		ProcessManager: NewProcessManager(api, logger),
-		WindowManager:  NewWindowManager(api, logger),
+		ProcessManager:   newProcessManager(api, resolver, logger),
+		WindowManager:    newWindowManager(api, resolver, bounds, logger),
+		WindowIdentifier: newWindowIdentifier(api, resolver, logger),
+		BoundsGuard:      bounds,
 	}
 }
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"hptools/internal/geometry"
+	"hptools/internal/models"
+)
+
+// applyWindowTarget moves a window to a target
+func applyWindowTarget(windows WindowManager, handle uintptr, target models.WindowTarget) error {
+	if target.Empty() {
+		return errors.New("target has neither rect, placement nor monitor")
+	}
+
+	if r := target.Rect; r != nil {
+		return windows.SetWindowPositionByHandle(handle, r.X, r.Y, r.Width, r.Height)
+	}
+
+	if target.Monitor != nil {
+		if err := moveToMonitor(windows, handle, *target.Monitor); err != nil {
+			return err
+		}
+	}
+	if target.Placement != "" {
+		return windows.ApplyPlacementByHandle(handle, target.Placement)
+	}
+	return nil
+}
+
+// moveToMonitor moves a window to the monitor with the given index, keeping
+// its position and size relative to the work area
+func moveToMonitor(windows WindowManager, handle uintptr, monitor int) error {
+	monitors, err := windows.GetMonitors()
+	if err != nil {
+		return err
+	}
+	if monitor < 0 || monitor >= len(monitors) {
+		return fmt.Errorf("monitor %d does not exist", monitor)
+	}
+
+	info, err := windows.GetWindowInfoByHandle(handle)
+	if err != nil {
+		return err
+	}
+	current := models.Rect{X: info.X, Y: info.Y, Width: info.Width, Height: info.Height}
+
+	from := geometry.MonitorAt(monitors, current)
+	if from == monitor {
+		return nil
+	}
+	target := geometry.Translate(current, monitors[from].WorkArea, monitors[monitor].WorkArea)
+	return windows.SetWindowPositionByHandle(handle, target.X, target.Y, target.Width, target.Height)
+}
+
+// mainWindowOf returns the main window of a listed process. Entries split by
+// AppUserModelID carry their own windows; otherwise the PID is resolved.
+func mainWindowOf(windows WindowManager, proc models.ProcessInfo) (uintptr, error) {
+	if len(proc.Windows) > 0 {
+		return proc.Windows[0].Handle, nil
+	}
+	return windows.FindWindowByPID(proc.PID)
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"hptools/internal/events"
+	"hptools/internal/models"
+	"hptools/internal/windows"
+)
+
+// windowEventBuffer is how many events may queue up before new ones are dropped
+const windowEventBuffer = 256
+
+// winEventTypes maps hooked WinEvents to the window event they report
+var winEventTypes = map[uint32]models.WindowEventType{
+	windows.EVENT_OBJECT_CREATE:         models.WindowCreated,
+	windows.EVENT_OBJECT_DESTROY:        models.WindowDestroyed,
+	windows.EVENT_OBJECT_SHOW:           models.WindowShown,
+	windows.EVENT_OBJECT_HIDE:           models.WindowHidden,
+	windows.EVENT_OBJECT_LOCATIONCHANGE: models.WindowMoved,
+	windows.EVENT_OBJECT_NAMECHANGE:     models.WindowTitleChanged,
+	windows.EVENT_SYSTEM_FOREGROUND:     models.WindowFocused,
+	windows.EVENT_SYSTEM_MINIMIZESTART:  models.WindowMinimized,
+	windows.EVENT_SYSTEM_MINIMIZEEND:    models.WindowRestored,
+}
+
+// winEventRanges are the hooked [min, max] event ranges
+var winEventRanges = [][2]uint32{
+	{windows.EVENT_SYSTEM_FOREGROUND, windows.EVENT_SYSTEM_FOREGROUND},
+	{windows.EVENT_SYSTEM_MINIMIZESTART, windows.EVENT_SYSTEM_MINIMIZEEND},
+	{windows.EVENT_OBJECT_CREATE, windows.EVENT_OBJECT_HIDE},
+	{windows.EVENT_OBJECT_LOCATIONCHANGE, windows.EVENT_OBJECT_NAMECHANGE},
+}
+
+type windowWatcher struct {
+	api    *windows.API
+	logger *slog.Logger
+	feed   *events.Feed[models.WindowEvent]
+
+	mu     sync.Mutex
+	stop   func()
+	queue  chan models.WindowEvent
+	closed chan struct{}
+}
+
+// NewWindowWatcher creates a watcher for top-level window changes
+func NewWindowWatcher(api *windows.API, logger *slog.Logger) WindowWatcher {
+	return &windowWatcher{
+		api:    api,
+		logger: logger,
+		feed:   events.NewFeed[models.WindowEvent](),
+	}
+}
+
+// Start installs the event hooks. Calling Start on a running watcher is a no-op.
+func (w *windowWatcher) Start() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stop != nil {
+		return nil
+	}
+
+	queue := make(chan models.WindowEvent, windowEventBuffer)
+	closed := make(chan struct{})
+
+	stop, err := w.api.WatchWinEvents(winEventRanges, func(e windows.WinEvent) {
+		if e.ObjectID != windows.OBJID_WINDOW || e.ChildID != windows.CHILDID_SELF || e.Hwnd == 0 {
+			return
+		}
+		eventType, ok := winEventTypes[e.Event]
+		if !ok {
+			return
+		}
+		// Destroyed windows have no ancestor left to check
+		if eventType != models.WindowDestroyed && w.api.GetRootWindow(e.Hwnd) != e.Hwnd {
+			return
+		}
+
+		// Never block the hook thread; a full queue drops the event
+		select {
+		case queue <- models.WindowEvent{Type: eventType, Handle: uintptr(e.Hwnd)}:
+		default:
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("installing window event hooks: %w", err)
+	}
+
+	go func() {
+		defer close(closed)
+		for event := range queue {
+			w.feed.Send(event)
+		}
+	}()
+
+	w.stop = stop
+	w.queue = queue
+	w.closed = closed
+	w.logger.Debug("Window watcher started")
+	return nil
+}
+
+// Stop removes the event hooks and waits for queued events to be delivered
+func (w *windowWatcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stop == nil {
+		return
+	}
+
+	w.stop()
+	close(w.queue)
+	<-w.closed
+	w.stop, w.queue, w.closed = nil, nil, nil
+	w.logger.Debug("Window watcher stopped")
+}
+
+// OnWindowEvent registers fn for window events and returns a function that
+// removes it again. fn runs on the watcher goroutine.
+func (w *windowWatcher) OnWindowEvent(fn func(models.WindowEvent)) func() {
+	return w.feed.Subscribe(fn)
+}
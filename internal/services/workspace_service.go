@@ -0,0 +1,196 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"hptools/internal/events"
+	"hptools/internal/filter"
+	"hptools/internal/models"
+)
+
+type workspaceService struct {
+	windows  WindowService
+	launcher Launcher
+	logger   *slog.Logger
+
+	mu         sync.RWMutex
+	workspaces []models.Workspace
+	changed    *events.Notifier
+}
+
+// NewWorkspaceService creates a new workspace manager for the given workspaces
+func NewWorkspaceService(windows WindowService, launcher Launcher, workspaces []models.Workspace, logger *slog.Logger) WorkspaceManager {
+	return &workspaceService{
+		windows:    windows,
+		launcher:   launcher,
+		logger:     logger,
+		workspaces: workspaces,
+		changed:    events.NewNotifier(),
+	}
+}
+
+// ListWorkspaces returns all configured workspaces
+func (w *workspaceService) ListWorkspaces() []models.Workspace {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return append([]models.Workspace(nil), w.workspaces...)
+}
+
+// SetWorkspaces replaces the workspaces, e.g. after a config reload
+func (w *workspaceService) SetWorkspaces(workspaces []models.Workspace) {
+	w.mu.Lock()
+	w.workspaces = workspaces
+	w.mu.Unlock()
+
+	w.changed.Notify()
+}
+
+// OnWorkspacesChanged registers a listener called when the workspaces change.
+// It returns a function that removes the listener.
+func (w *workspaceService) OnWorkspacesChanged(fn func()) func() {
+	return w.changed.Subscribe(fn)
+}
+
+// RunWorkspace handles the apps of a workspace in order: a running process
+// matching the app is reused, otherwise the app is launched, and its main
+// window is moved to the app's target. Failures are reported per app; the
+// error is only set when the workspace cannot be run at all.
+func (w *workspaceService) RunWorkspace(name string) (*models.WorkspaceResult, error) {
+	workspace, ok := w.findWorkspace(name)
+	if !ok {
+		return nil, fmt.Errorf("workspace %q not found", name)
+	}
+
+	result := &models.WorkspaceResult{Workspace: name}
+	// claimed keeps two apps with similar criteria from sharing one process
+	claimed := make(map[int]bool)
+	for i, app := range workspace.Apps {
+		status := w.runApp(app, claimed)
+		if status.PID != 0 {
+			claimed[status.PID] = true
+		}
+		result.Apps = append(result.Apps, status)
+
+		if app.DelayMS > 0 && i < len(workspace.Apps)-1 {
+			time.Sleep(time.Duration(app.DelayMS) * time.Millisecond)
+		}
+	}
+
+	w.logger.Info("Workspace run", "workspace", name, "apps", len(result.Apps))
+	return result, nil
+}
+
+// runApp reuses or launches one app and arranges its window
+func (w *workspaceService) runApp(app models.WorkspaceApp, claimed map[int]bool) models.WorkspaceAppStatus {
+	status := models.WorkspaceAppStatus{Name: appName(app)}
+	fail := func(err error) models.WorkspaceAppStatus {
+		status.State = models.WorkspaceAppFailed
+		status.Error = err.Error()
+		w.logger.Warn("Workspace app failed", "app", status.Name, "error", err)
+		return status
+	}
+
+	pid, hwnd, err := w.findRunning(app.Match, claimed)
+	if err != nil {
+		return fail(err)
+	}
+
+	if pid == 0 {
+		if app.Launch == nil {
+			status.State = models.WorkspaceAppSkipped
+			return status
+		}
+		launched, err := w.launcher.Launch(models.LaunchRequest{
+			LaunchTarget: *app.Launch,
+			WindowTarget: app.WindowTarget,
+			TimeoutMS:    app.TimeoutMS,
+		})
+		if launched != nil {
+			status.PID = launched.PID
+		}
+		if err != nil {
+			return fail(err)
+		}
+		status.State = models.WorkspaceAppLaunched
+		return status
+	}
+
+	status.PID = pid
+	// An app without a target is only brought up, not moved
+	if !app.WindowTarget.Empty() {
+		if err := applyWindowTarget(w.windows, hwnd, app.WindowTarget); err != nil {
+			return fail(err)
+		}
+	}
+	status.State = models.WorkspaceAppReused
+	return status
+}
+
+// findRunning returns the first unclaimed application process matching the
+// criteria together with its matching window, or 0 if there is none
+func (w *workspaceService) findRunning(match models.WindowMatch, claimed map[int]bool) (int, uintptr, error) {
+	if match == (models.WindowMatch{}) {
+		return 0, 0, nil
+	}
+
+	matcher, err := compileWindowMatch(match)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid match: %w", err)
+	}
+
+	processes, err := w.windows.GetApplicationProcesses()
+	if err != nil {
+		return 0, 0, fmt.Errorf("getting application processes: %w", err)
+	}
+	for _, proc := range processes {
+		if claimed[proc.PID] {
+			continue
+		}
+		if hwnd, ok := matchingWindow(matcher, proc); ok {
+			return proc.PID, hwnd, nil
+		}
+	}
+	return 0, 0, nil
+}
+
+// matchingWindow returns the first window of proc whose title and class
+// match, so title and class criteria pick the window they describe rather
+// than the main window of its process
+func matchingWindow(matcher *filter.Matcher, proc models.ProcessInfo) (uintptr, bool) {
+	for _, win := range proc.Windows {
+		candidate := proc
+		candidate.WindowTitle, candidate.WindowClass = win.Title, win.Class
+		if matcher.Match(candidate) {
+			return win.Handle, true
+		}
+	}
+	return 0, false
+}
+
+// findWorkspace looks up a workspace by name
+func (w *workspaceService) findWorkspace(name string) (models.Workspace, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, workspace := range w.workspaces {
+		if workspace.Name == name {
+			return workspace, true
+		}
+	}
+	return models.Workspace{}, false
+}
+
+// appName returns the name used to report on an app
+func appName(app models.WorkspaceApp) string {
+	switch {
+	case app.Name != "":
+		return app.Name
+	case app.Match.ImageGlob != "":
+		return app.Match.ImageGlob
+	case app.Launch != nil:
+		return app.Launch.Path
+	}
+	return "unnamed"
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"log/slog"
+	"testing"
+
+	"hptools/internal/models"
+)
+
+// fakeWindowService lists fixed processes and records the windows that are
+// moved; other WindowService methods are not implemented
+type fakeWindowService struct {
+	WindowService
+	processes []models.ProcessInfo
+	placed    []uintptr
+}
+
+func (f *fakeWindowService) GetApplicationProcesses() ([]models.ProcessInfo, error) {
+	return f.processes, nil
+}
+
+func (f *fakeWindowService) ApplyPlacementByHandle(handle uintptr, placement models.Placement) error {
+	f.placed = append(f.placed, handle)
+	return nil
+}
+
+func (f *fakeWindowService) SetWindowPositionByHandle(handle uintptr, x, y, width, height int) error {
+	f.placed = append(f.placed, handle)
+	return nil
+}
+
+func TestRunWorkspaceReusedApps(t *testing.T) {
+	windows := &fakeWindowService{processes: []models.ProcessInfo{
+		{PID: 10, ImageName: "editor.exe", Windows: []models.WindowEntry{{Handle: 100, Title: "notes.txt"}}},
+		{PID: 20, ImageName: "terminal.exe", Windows: []models.WindowEntry{{Handle: 200, Title: "shell"}}},
+	}}
+	workspaces := []models.Workspace{{Name: "dev", Apps: []models.WorkspaceApp{
+		{Match: models.WindowMatch{ImageGlob: "editor.exe"}},
+		{Match: models.WindowMatch{ImageGlob: "terminal.exe"}, WindowTarget: models.WindowTarget{Placement: models.PlacementRightHalf}},
+	}}}
+	service := NewWorkspaceService(windows, nil, workspaces, slog.New(slog.DiscardHandler))
+
+	result, err := service.RunWorkspace("dev")
+	if err != nil {
+		t.Fatalf("RunWorkspace: %v", err)
+	}
+
+	want := []models.WorkspaceAppStatus{
+		{Name: "editor.exe", State: models.WorkspaceAppReused, PID: 10},
+		{Name: "terminal.exe", State: models.WorkspaceAppReused, PID: 20},
+	}
+	if len(result.Apps) != len(want) {
+		t.Fatalf("RunWorkspace apps = %+v, want %+v", result.Apps, want)
+	}
+	for i := range want {
+		if result.Apps[i] != want[i] {
+			t.Errorf("app %d = %+v, want %+v", i, result.Apps[i], want[i])
+		}
+	}
+
+	// The editor has no target and stays where it is
+	if len(windows.placed) != 1 || windows.placed[0] != 200 {
+		t.Errorf("moved windows = %v, want only the terminal window 200", windows.placed)
+	}
+}
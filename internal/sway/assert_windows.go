@@ -0,0 +1,8 @@
+//go:build windows
+
+package sway
+
+import "hptools/internal/services"
+
+// The services package only builds on Windows, so the interface check lives here
+var _ services.WindowManager = (*WindowManager)(nil)
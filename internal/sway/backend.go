@@ -0,0 +1,66 @@
+package sway
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"hptools/internal/x11"
+)
+
+// Backend names a window manager that speaks the i3/sway IPC protocol
+type Backend string
+
+const (
+	BackendSway Backend = "sway"
+	BackendI3   Backend = "i3"
+)
+
+// PIDResolver looks up the process that owns an X11 window. i3 trees only
+// carry X11 window IDs; sway reports PIDs itself and needs none.
+type PIDResolver interface {
+	WindowPID(window uint32) (int, error)
+}
+
+// DetectBackend picks sway when $SWAYSOCK is set and i3 when $I3SOCK is set
+func DetectBackend() (Backend, error) {
+	switch {
+	case os.Getenv("SWAYSOCK") != "":
+		return BackendSway, nil
+	case os.Getenv("I3SOCK") != "":
+		return BackendI3, nil
+	}
+	return "", errors.New("neither SWAYSOCK nor I3SOCK is set")
+}
+
+// Connect creates a window manager for a backend; an empty backend is
+// detected from the environment. The i3 backend also connects to the X
+// server for PIDs. The returned function closes that connection.
+func Connect(backend Backend, logger *slog.Logger) (*WindowManager, func(), error) {
+	if backend == "" {
+		detected, err := DetectBackend()
+		if err != nil {
+			return nil, nil, err
+		}
+		backend = detected
+	}
+	if backend != BackendSway && backend != BackendI3 {
+		return nil, nil, fmt.Errorf("unknown window manager backend %q", backend)
+	}
+
+	socket, err := SocketPath(backend)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if backend == BackendSway {
+		return NewWindowManager(NewClient(socket), nil, logger), func() {}, nil
+	}
+
+	conn, err := x11.Open()
+	if err != nil {
+		return nil, nil, fmt.Errorf("i3 needs the X server for PIDs: %w", err)
+	}
+	return NewWindowManager(NewClient(socket), conn, logger), conn.Close, nil
+}
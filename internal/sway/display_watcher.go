@@ -0,0 +1,164 @@
+package sway
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"hptools/internal/geometry"
+	"hptools/internal/models"
+	"hptools/internal/x11"
+)
+
+// displaySettleDelay is how long the outputs must stay quiet before they are
+// fingerprinted. A dock or undock raises a burst of RandR notifications, and
+// the window manager rearranges its outputs after the last one.
+const displaySettleDelay = 3 * time.Second
+
+// DisplayWatcher applies the layout associated with an output setup whenever
+// that setup becomes active. Changes are reported by the RandR extension of
+// the X server; under sway that is XWayland, so $DISPLAY must be set.
+type DisplayWatcher struct {
+	wm     *WindowManager
+	logger *slog.Logger
+	// watch subscribes to display changes; it is x11.WatchDisplayChanges
+	// outside of tests
+	watch       func(onChange func()) (func(), error)
+	settleDelay time.Duration
+
+	mu           sync.Mutex
+	layouts      []models.Layout
+	associations []models.DisplayLayout
+	fingerprint  string
+	settle       *time.Timer
+	stop         func()
+}
+
+// NewDisplayWatcher creates a watcher for the layouts and the output setup to
+// layout associations of the config
+func NewDisplayWatcher(wm *WindowManager, layouts []models.Layout, associations []models.DisplayLayout, logger *slog.Logger) *DisplayWatcher {
+	return &DisplayWatcher{
+		wm:           wm,
+		logger:       logger,
+		watch:        x11.WatchDisplayChanges,
+		settleDelay:  displaySettleDelay,
+		layouts:      layouts,
+		associations: associations,
+	}
+}
+
+// Start records the current output setup and begins watching for changes.
+// The layout of the current setup is not applied on start.
+func (d *DisplayWatcher) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop != nil {
+		return nil
+	}
+
+	fingerprint, err := d.CurrentFingerprint()
+	if err != nil {
+		return err
+	}
+	d.fingerprint = fingerprint
+
+	stop, err := d.watch(d.scheduleCheck)
+	if err != nil {
+		return fmt.Errorf("watching display changes: %w", err)
+	}
+	d.stop = stop
+
+	d.logger.Info("Display watcher started", "fingerprint", fingerprint)
+	return nil
+}
+
+// Stop ends watching and cancels a pending check
+func (d *DisplayWatcher) Stop() {
+	d.mu.Lock()
+	stop := d.stop
+	d.stop = nil
+	if d.settle != nil {
+		d.settle.Stop()
+		d.settle = nil
+	}
+	d.mu.Unlock()
+
+	// Called outside the lock; the event goroutine may be waiting for it in scheduleCheck
+	if stop != nil {
+		stop()
+	}
+}
+
+// CurrentFingerprint returns the fingerprint of the active outputs, as used
+// in the display layout associations
+func (d *DisplayWatcher) CurrentFingerprint() (string, error) {
+	monitors, err := d.wm.GetMonitors()
+	if err != nil {
+		return "", err
+	}
+	return geometry.Fingerprint(monitors), nil
+}
+
+// scheduleCheck (re)starts the settle timer; it runs on the event goroutine
+func (d *DisplayWatcher) scheduleCheck() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop == nil {
+		return
+	}
+	if d.settle != nil {
+		d.settle.Reset(d.settleDelay)
+		return
+	}
+	d.settle = time.AfterFunc(d.settleDelay, d.check)
+}
+
+// check fingerprints the settled output setup and applies its layout if the
+// setup changed since the last check
+func (d *DisplayWatcher) check() {
+	fingerprint, err := d.CurrentFingerprint()
+	if err != nil {
+		d.logger.Error("Failed to fingerprint displays", "error", err)
+		return
+	}
+
+	d.mu.Lock()
+	changed := fingerprint != d.fingerprint
+	d.fingerprint = fingerprint
+	layout, ok := d.layoutFor(fingerprint)
+	d.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	d.logger.Info("Display setup changed", "fingerprint", fingerprint)
+	if !ok {
+		return
+	}
+
+	if err := d.wm.ApplyLayout(layout); err != nil {
+		d.logger.Error("Failed to apply display layout", "layout", layout.Name, "fingerprint", fingerprint, "error", err)
+		return
+	}
+	d.logger.Info("Display layout applied", "layout", layout.Name)
+}
+
+// layoutFor returns the layout associated with a fingerprint; d.mu must be held
+func (d *DisplayWatcher) layoutFor(fingerprint string) (models.Layout, bool) {
+	for _, a := range d.associations {
+		if a.Fingerprint != fingerprint {
+			continue
+		}
+		for _, layout := range d.layouts {
+			if layout.Name == a.Layout {
+				return layout, true
+			}
+		}
+		d.logger.Warn("Display layout not found", "layout", a.Layout, "fingerprint", fingerprint)
+		return models.Layout{}, false
+	}
+	return models.Layout{}, false
+}
@@ -0,0 +1,138 @@
+package sway
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hptools/internal/models"
+)
+
+// testImages names the processes of testTree
+func testImages(pid int) (string, error) {
+	switch pid {
+	case 42:
+		return "Editor", nil
+	case 7:
+		return "alacritty", nil
+	}
+	return "", fmt.Errorf("no process %d", pid)
+}
+
+// runCommands returns the payloads of the RUN_COMMAND requests received so far
+func runCommands(server *fakeServer) []string {
+	var commands []string
+	for _, r := range server.received() {
+		if r.msgType == msgRunCommand {
+			commands = append(commands, r.payload)
+		}
+	}
+	return commands
+}
+
+func TestWindowManagerApplyLayout(t *testing.T) {
+	server := newFakeServer(t, map[uint32]string{
+		msgGetTree:       testTree,
+		msgGetOutputs:    testOutputs,
+		msgGetWorkspaces: testWorkspaces,
+		msgRunCommand:    `[{"success": true}]`,
+	})
+	wm := NewWindowManager(server.client(), nil, slog.New(slog.DiscardHandler))
+	wm.imageName = testImages
+
+	missing := 3
+	layout := models.Layout{Name: "work", Windows: []models.LayoutWindow{
+		{ImageName: "editor.exe", WindowTarget: models.WindowTarget{Placement: models.PlacementRightHalf}},
+		{ImageName: "alacritty", WindowTarget: models.WindowTarget{Rect: &models.Rect{X: 10, Y: 40, Width: 800, Height: 600}}},
+		{ImageName: "firefox", WindowTarget: models.WindowTarget{Placement: models.PlacementLeftHalf}},
+		{ImageName: "alacritty", WindowTarget: models.WindowTarget{Monitor: &missing}},
+	}}
+
+	err := wm.ApplyLayout(layout)
+	if err == nil || !strings.Contains(err.Error(), "monitor 3 does not exist") {
+		t.Errorf("ApplyLayout error = %v, want the missing monitor reported", err)
+	}
+
+	want := []string{
+		"[con_id=11] floating enable, resize set width 960 px height 1050 px, move absolute position 960 px 30 px",
+		"[con_id=12] floating enable, resize set width 800 px height 600 px, move absolute position 10 px 40 px",
+	}
+	if got := runCommands(server); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// fakeDisplayChanges stands in for the RandR watch
+type fakeDisplayChanges struct {
+	mu       sync.Mutex
+	onChange func()
+	stopped  bool
+}
+
+func (f *fakeDisplayChanges) watch(onChange func()) (func(), error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.onChange = onChange
+	return func() {
+		f.mu.Lock()
+		f.stopped = true
+		f.mu.Unlock()
+	}, nil
+}
+
+func TestDisplayWatcher(t *testing.T) {
+	server := newFakeServer(t, map[uint32]string{
+		msgGetTree:       testTree,
+		msgGetOutputs:    testOutputs,
+		msgGetWorkspaces: testWorkspaces,
+		msgRunCommand:    `[{"success": true}]`,
+	})
+	wm := NewWindowManager(server.client(), nil, slog.New(slog.DiscardHandler))
+	wm.imageName = testImages
+
+	layouts := []models.Layout{{Name: "docked", Windows: []models.LayoutWindow{
+		{ImageName: "editor", WindowTarget: models.WindowTarget{Placement: models.PlacementLeftHalf}},
+	}}}
+	associations := []models.DisplayLayout{{Fingerprint: "2560x1440@0,0*", Layout: "docked"}}
+
+	changes := &fakeDisplayChanges{}
+	d := NewDisplayWatcher(wm, layouts, associations, slog.New(slog.DiscardHandler))
+	d.watch = changes.watch
+	d.settleDelay = 10 * time.Millisecond
+
+	if err := d.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer d.Stop()
+
+	// A burst of notifications for the dock applies the layout once
+	server.setReply(msgGetOutputs, `[{"name": "DP-1", "active": true, "primary": true,
+		"rect": {"x": 0, "y": 0, "width": 2560, "height": 1440}}]`)
+	changes.onChange()
+	changes.onChange()
+
+	want := "[con_id=11] floating enable, resize set width 1280 px height 1440 px, move absolute position 0 px 0 px"
+	deadline := time.Now().Add(2 * time.Second)
+	for len(runCommands(server)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// A notification without a change applies nothing
+	changes.onChange()
+	time.Sleep(50 * time.Millisecond)
+
+	if got := runCommands(server); len(got) != 1 || got[0] != want {
+		t.Errorf("commands = %q, want [%q]", got, want)
+	}
+
+	d.Stop()
+	changes.mu.Lock()
+	stopped := changes.stopped
+	changes.mu.Unlock()
+	if !stopped {
+		t.Error("Stop did not end the display watch")
+	}
+}
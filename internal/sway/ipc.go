@@ -0,0 +1,140 @@
+// Package sway implements window management for the sway and i3 window
+// managers over their shared IPC protocol. It is the backend of the Linux
+// command line tool; the Windows application does not use it. i3 layout trees
+// carry X11 window IDs instead of PIDs, so the i3 backend reads PIDs from the
+// X server. The client only sends requests; it does not subscribe to events
+// such as output changes.
+package sway
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+)
+
+// Message types of the i3/sway IPC protocol
+const (
+	msgRunCommand    uint32 = 0
+	msgGetWorkspaces uint32 = 1
+	msgGetOutputs    uint32 = 3
+	msgGetTree       uint32 = 4
+)
+
+// ipcMagic starts every IPC message and reply
+const ipcMagic = "i3-ipc"
+
+// ipcTimeout bounds a single request/reply round trip
+const ipcTimeout = 2 * time.Second
+
+// Client sends requests to the window manager IPC socket
+type Client struct {
+	socketPath string
+}
+
+// NewClient creates a client for the given IPC socket path
+func NewClient(socketPath string) *Client {
+	return &Client{socketPath: socketPath}
+}
+
+// SocketPath returns the IPC socket of a backend, from $SWAYSOCK for sway
+// and $I3SOCK for i3
+func SocketPath(backend Backend) (string, error) {
+	variable := "SWAYSOCK"
+	if backend == BackendI3 {
+		variable = "I3SOCK"
+	}
+	if path := os.Getenv(variable); path != "" {
+		return path, nil
+	}
+	return "", fmt.Errorf("%s is not set", variable)
+}
+
+// request sends one message and decodes the JSON reply into reply
+func (c *Client) request(msgType uint32, payload string, reply any) error {
+	conn, err := net.DialTimeout("unix", c.socketPath, ipcTimeout)
+	if err != nil {
+		return fmt.Errorf("connecting to IPC socket: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ipcTimeout))
+
+	header := make([]byte, len(ipcMagic)+8)
+	copy(header, ipcMagic)
+	binary.LittleEndian.PutUint32(header[len(ipcMagic):], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(header[len(ipcMagic)+4:], msgType)
+	if _, err := conn.Write(append(header, payload...)); err != nil {
+		return fmt.Errorf("writing IPC message: %w", err)
+	}
+
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("reading IPC reply header: %w", err)
+	}
+	if string(header[:len(ipcMagic)]) != ipcMagic {
+		return errors.New("invalid IPC reply magic")
+	}
+	if got := binary.LittleEndian.Uint32(header[len(ipcMagic)+4:]); got != msgType {
+		return fmt.Errorf("unexpected IPC reply type %d for request %d", got, msgType)
+	}
+
+	body := make([]byte, binary.LittleEndian.Uint32(header[len(ipcMagic):]))
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return fmt.Errorf("reading IPC reply: %w", err)
+	}
+
+	if err := json.Unmarshal(body, reply); err != nil {
+		return fmt.Errorf("parsing IPC reply: %w", err)
+	}
+	return nil
+}
+
+// commandResult is one entry of a RUN_COMMAND reply
+type commandResult struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
+
+// RunCommand runs a window manager command, e.g. "[con_id=5] floating enable"
+func (c *Client) RunCommand(command string) error {
+	var results []commandResult
+	if err := c.request(msgRunCommand, command, &results); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if !r.Success {
+			return fmt.Errorf("command %q failed: %s", command, r.Error)
+		}
+	}
+	return nil
+}
+
+// GetTree returns the layout tree
+func (c *Client) GetTree() (*Node, error) {
+	var root Node
+	if err := c.request(msgGetTree, "", &root); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// GetOutputs returns the connected outputs
+func (c *Client) GetOutputs() ([]Output, error) {
+	var outputs []Output
+	if err := c.request(msgGetOutputs, "", &outputs); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// GetWorkspaces returns all workspaces
+func (c *Client) GetWorkspaces() ([]Workspace, error) {
+	var workspaces []Workspace
+	if err := c.request(msgGetWorkspaces, "", &workspaces); err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
@@ -0,0 +1,413 @@
+package sway
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"hptools/internal/models"
+)
+
+// ipcRequest is a message received by the fake server
+type ipcRequest struct {
+	msgType uint32
+	payload string
+}
+
+// fakeServer answers IPC requests on a unix socket with canned replies
+type fakeServer struct {
+	t        *testing.T
+	listener net.Listener
+	// replies maps a message type to its JSON reply
+	replies map[uint32]string
+	// replyType overrides the type of every reply when set
+	replyType *uint32
+	magic     string
+
+	mu       sync.Mutex
+	requests []ipcRequest
+}
+
+func newFakeServer(t *testing.T, replies map[uint32]string) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "sway.sock"))
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	s := &fakeServer{t: t, listener: listener, replies: replies, magic: ipcMagic}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeServer) client() *Client {
+	return NewClient(s.listener.Addr().String())
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	header := make([]byte, len(ipcMagic)+8)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	if string(header[:len(ipcMagic)]) != ipcMagic {
+		s.t.Errorf("request magic = %q", header[:len(ipcMagic)])
+		return
+	}
+	payload := make([]byte, binary.LittleEndian.Uint32(header[len(ipcMagic):]))
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return
+	}
+	msgType := binary.LittleEndian.Uint32(header[len(ipcMagic)+4:])
+
+	s.mu.Lock()
+	s.requests = append(s.requests, ipcRequest{msgType: msgType, payload: string(payload)})
+	reply := s.replies[msgType]
+	s.mu.Unlock()
+
+	if s.replyType != nil {
+		msgType = *s.replyType
+	}
+	out := make([]byte, len(s.magic)+8)
+	copy(out, s.magic)
+	binary.LittleEndian.PutUint32(out[len(s.magic):], uint32(len(reply)))
+	binary.LittleEndian.PutUint32(out[len(s.magic)+4:], msgType)
+	conn.Write(append(out, reply...))
+}
+
+// setReply replaces the reply to a message type while the server runs
+func (s *fakeServer) setReply(msgType uint32, reply string) {
+	s.mu.Lock()
+	s.replies[msgType] = reply
+	s.mu.Unlock()
+}
+
+func (s *fakeServer) received() []ipcRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ipcRequest(nil), s.requests...)
+}
+
+// testTree has two windows of PID 42 on workspace 1; the focused one is smaller
+const testTree = `{
+	"id": 1, "type": "root", "nodes": [{
+		"id": 2, "type": "output", "name": "eDP-1", "nodes": [{
+			"id": 3, "type": "workspace", "name": "1", "nodes": [
+				{"id": 10, "type": "con", "name": "editor", "pid": 42, "app_id": "editor",
+				 "rect": {"x": 0, "y": 0, "width": 1200, "height": 800}},
+				{"id": 11, "type": "con", "name": "editor dialog", "pid": 42, "focused": true,
+				 "rect": {"x": 100, "y": 100, "width": 400, "height": 300}}
+			],
+			"floating_nodes": [
+				{"id": 12, "type": "floating_con", "name": "terminal", "pid": 7,
+				 "rect": {"x": 50, "y": 50, "width": 600, "height": 400}}
+			]
+		}]
+	}]
+}`
+
+// testOutputs is a single 1920x1080 output whose workspace leaves room for a bar
+const (
+	testOutputs    = `[{"name": "eDP-1", "active": true, "rect": {"x": 0, "y": 0, "width": 1920, "height": 1080}}]`
+	testWorkspaces = `[{"num": 1, "name": "1", "visible": true, "output": "eDP-1",
+		"rect": {"x": 0, "y": 30, "width": 1920, "height": 1050}}]`
+)
+
+func TestClientGetTree(t *testing.T) {
+	server := newFakeServer(t, map[uint32]string{msgGetTree: testTree})
+
+	tree, err := server.client().GetTree()
+	if err != nil {
+		t.Fatalf("GetTree: %v", err)
+	}
+
+	windows := tree.windows()
+	if len(windows) != 3 {
+		t.Fatalf("found %d windows, want 3", len(windows))
+	}
+	for _, w := range windows {
+		if w.workspace != "1" {
+			t.Errorf("window %d on workspace %q, want 1", w.node.ID, w.workspace)
+		}
+	}
+
+	win, ok := mainWindow(windows, 42)
+	if !ok || win.node.ID != 11 {
+		t.Errorf("mainWindow(42) = %d, %v; want the focused window 11", win.node.ID, ok)
+	}
+
+	requests := server.received()
+	if len(requests) != 1 || requests[0].msgType != msgGetTree || requests[0].payload != "" {
+		t.Errorf("requests = %+v, want one empty GET_TREE", requests)
+	}
+}
+
+func TestClientRunCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr string
+	}{
+		{"success", `[{"success": true}]`, ""},
+		{"failure", `[{"success": true}, {"success": false, "error": "No matching node"}]`, "No matching node"},
+		{"malformed reply", `{"success": true`, "parsing IPC reply"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeServer(t, map[uint32]string{msgRunCommand: tt.reply})
+
+			err := server.client().RunCommand("[con_id=10] floating enable")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("RunCommand: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("RunCommand error = %v, want it to contain %q", err, tt.wantErr)
+			}
+
+			requests := server.received()
+			if len(requests) != 1 || requests[0].msgType != msgRunCommand || requests[0].payload != "[con_id=10] floating enable" {
+				t.Errorf("requests = %+v, want the command as RUN_COMMAND payload", requests)
+			}
+		})
+	}
+}
+
+func TestClientRequestErrors(t *testing.T) {
+	t.Run("wrong reply type", func(t *testing.T) {
+		server := newFakeServer(t, map[uint32]string{msgGetTree: testTree})
+		other := msgGetOutputs
+		server.replyType = &other
+		if _, err := server.client().GetTree(); err == nil || !strings.Contains(err.Error(), "unexpected IPC reply type") {
+			t.Errorf("GetTree error = %v, want unexpected reply type", err)
+		}
+	})
+
+	t.Run("wrong magic", func(t *testing.T) {
+		server := newFakeServer(t, map[uint32]string{msgGetTree: testTree})
+		server.magic = "i4-ipc"
+		if _, err := server.client().GetTree(); err == nil || !strings.Contains(err.Error(), "magic") {
+			t.Errorf("GetTree error = %v, want invalid magic", err)
+		}
+	})
+
+	t.Run("no socket", func(t *testing.T) {
+		client := NewClient(filepath.Join(t.TempDir(), "missing.sock"))
+		if _, err := client.GetTree(); err == nil || !strings.Contains(err.Error(), "connecting") {
+			t.Errorf("GetTree error = %v, want a connection error", err)
+		}
+	})
+}
+
+func TestWindowManagerSetWindowPosition(t *testing.T) {
+	server := newFakeServer(t, map[uint32]string{
+		msgGetTree:       testTree,
+		msgGetOutputs:    testOutputs,
+		msgGetWorkspaces: testWorkspaces,
+		msgRunCommand:    `[{"success": true}]`,
+	})
+	wm := NewWindowManager(server.client(), nil, slog.New(slog.DiscardHandler))
+
+	if err := wm.SetWindowPosition(42, 10, 20, 800, 600); err != nil {
+		t.Fatalf("SetWindowPosition: %v", err)
+	}
+	if err := wm.SetWindowPositionByHandle(12, 0, 0, 640, 480); err != nil {
+		t.Fatalf("SetWindowPositionByHandle: %v", err)
+	}
+
+	var commands []string
+	for _, r := range server.received() {
+		if r.msgType == msgRunCommand {
+			commands = append(commands, r.payload)
+		}
+	}
+	want := []string{
+		"[con_id=11] floating enable, resize set width 800 px height 600 px, move absolute position 10 px 20 px",
+		"[con_id=12] floating enable, resize set width 640 px height 480 px, move absolute position 0 px 0 px",
+	}
+	if strings.Join(commands, "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands =\n%s\nwant\n%s", strings.Join(commands, "\n"), strings.Join(want, "\n"))
+	}
+
+	recent := wm.RecentWindows()
+	if len(recent) != 2 || recent[0] != (models.RecentWindow{PID: 7, Title: "terminal"}) {
+		t.Errorf("RecentWindows() = %v, want the terminal first", recent)
+	}
+
+	if err := wm.SetWindowPosition(99, 0, 0, 100, 100); err == nil {
+		t.Error("SetWindowPosition for a PID without windows succeeded")
+	}
+	if err := wm.SetWindowPositionByHandle(99, 0, 0, 100, 100); err == nil {
+		t.Error("SetWindowPositionByHandle for an unknown container succeeded")
+	}
+}
+
+func TestWindowManagerBoundsPolicy(t *testing.T) {
+	tests := []struct {
+		policy  models.BoundsPolicy
+		rect    models.Rect
+		want    string
+		wantErr bool
+	}{
+		{models.BoundsReject, models.Rect{X: 100, Y: 100, Width: 800, Height: 600},
+			"[con_id=11] floating enable, resize set width 800 px height 600 px, move absolute position 100 px 100 px", false},
+		{models.BoundsReject, models.Rect{X: 5000, Y: 100, Width: 800, Height: 600}, "", true},
+		{models.BoundsClamp, models.Rect{X: 1500, Y: 0, Width: 800, Height: 600},
+			"[con_id=11] floating enable, resize set width 800 px height 600 px, move absolute position 1120 px 30 px", false},
+		{models.BoundsAllow, models.Rect{X: 5000, Y: 100, Width: 800, Height: 600},
+			"[con_id=11] floating enable, resize set width 800 px height 600 px, move absolute position 5000 px 100 px", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			server := newFakeServer(t, map[uint32]string{
+				msgGetTree:       testTree,
+				msgGetOutputs:    testOutputs,
+				msgGetWorkspaces: testWorkspaces,
+				msgRunCommand:    `[{"success": true}]`,
+			})
+			wm := NewWindowManager(server.client(), nil, slog.New(slog.DiscardHandler))
+			if err := wm.SetBoundsPolicy(tt.policy); err != nil {
+				t.Fatalf("SetBoundsPolicy: %v", err)
+			}
+
+			err := wm.SetWindowPosition(42, tt.rect.X, tt.rect.Y, tt.rect.Width, tt.rect.Height)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("SetWindowPosition error = %v, want error %v", err, tt.wantErr)
+			}
+
+			var commands []string
+			for _, r := range server.received() {
+				if r.msgType == msgRunCommand {
+					commands = append(commands, r.payload)
+				}
+			}
+			if tt.want == "" && len(commands) != 0 {
+				t.Errorf("commands = %q, want none", commands)
+			}
+			if tt.want != "" && (len(commands) != 1 || commands[0] != tt.want) {
+				t.Errorf("commands = %q, want %q", commands, tt.want)
+			}
+		})
+	}
+
+	if err := NewWindowManager(nil, nil, slog.New(slog.DiscardHandler)).SetBoundsPolicy("hide"); err == nil {
+		t.Error("SetBoundsPolicy(\"hide\") succeeded")
+	}
+}
+
+// testI3Tree is testTree as i3 reports it: X11 window IDs and no PIDs
+const testI3Tree = `{
+	"id": 1, "type": "root", "nodes": [{
+		"id": 2, "type": "output", "name": "eDP-1", "nodes": [{
+			"id": 3, "type": "workspace", "name": "1", "nodes": [
+				{"id": 10, "type": "con", "name": "editor", "window": 4194307,
+				 "rect": {"x": 0, "y": 0, "width": 1200, "height": 800}},
+				{"id": 11, "type": "con", "name": "editor dialog", "window": 4194320, "focused": true,
+				 "rect": {"x": 100, "y": 100, "width": 400, "height": 300}}
+			],
+			"floating_nodes": [
+				{"id": 12, "type": "floating_con", "name": "remote terminal", "window": 6291459,
+				 "rect": {"x": 50, "y": 50, "width": 600, "height": 400}}
+			]
+		}]
+	}]
+}`
+
+// fakePIDs maps X11 windows to PIDs; windows it does not know have no _NET_WM_PID
+type fakePIDs map[uint32]int
+
+func (f fakePIDs) WindowPID(window uint32) (int, error) {
+	if pid, ok := f[window]; ok {
+		return pid, nil
+	}
+	return 0, errors.New("property is not set")
+}
+
+func TestWindowManagerI3PIDs(t *testing.T) {
+	server := newFakeServer(t, map[uint32]string{
+		msgGetTree:       testI3Tree,
+		msgGetOutputs:    testOutputs,
+		msgGetWorkspaces: testWorkspaces,
+		msgRunCommand:    `[{"success": true}]`,
+	})
+	wm := NewWindowManager(server.client(), fakePIDs{4194307: 42, 4194320: 42}, slog.New(slog.DiscardHandler))
+
+	handle, err := wm.FindWindowByPID(42)
+	if err != nil {
+		t.Fatalf("FindWindowByPID: %v", err)
+	}
+	if handle != 11 {
+		t.Errorf("FindWindowByPID(42) = %d, want the focused window 11", handle)
+	}
+
+	if err := wm.ApplyPlacementByHandle(12, models.PlacementLeftHalf); err != nil {
+		t.Fatalf("ApplyPlacementByHandle for a window without a PID: %v", err)
+	}
+	recent := wm.RecentWindows()
+	if len(recent) != 1 || recent[0] != (models.RecentWindow{PID: 0, Title: "remote terminal"}) {
+		t.Errorf("RecentWindows() = %v, want the remote terminal without a PID", recent)
+	}
+
+	if _, err := NewWindowManager(server.client(), nil, slog.New(slog.DiscardHandler)).FindWindowByPID(42); err == nil {
+		t.Error("FindWindowByPID without a PID resolver found an i3 window")
+	}
+}
+
+func TestConnect(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  Backend
+		swaysock string
+		i3sock   string
+		wantErr  string
+	}{
+		{"detects sway", "", "/run/user/1000/sway-ipc.sock", "", ""},
+		{"sway preferred over i3", "", "/run/user/1000/sway-ipc.sock", "/run/user/1000/i3/ipc.sock", ""},
+		{"explicit sway", BackendSway, "/run/user/1000/sway-ipc.sock", "", ""},
+		{"nothing to detect", "", "", "", "neither SWAYSOCK nor I3SOCK"},
+		{"sway socket missing", BackendSway, "", "/run/user/1000/i3/ipc.sock", "SWAYSOCK is not set"},
+		{"i3 socket missing", BackendI3, "/run/user/1000/sway-ipc.sock", "", "I3SOCK is not set"},
+		{"unknown backend", "xmonad", "", "", "unknown window manager backend"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SWAYSOCK", tt.swaysock)
+			t.Setenv("I3SOCK", tt.i3sock)
+
+			wm, closeConn, err := Connect(tt.backend, slog.New(slog.DiscardHandler))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Connect error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer closeConn()
+			if wm.client.socketPath != tt.swaysock || wm.pids != nil {
+				t.Errorf("Connect() socket = %q, pids = %v; want %q without a PID resolver", wm.client.socketPath, wm.pids, tt.swaysock)
+			}
+		})
+	}
+}
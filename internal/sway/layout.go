@@ -0,0 +1,117 @@
+package sway
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"hptools/internal/geometry"
+	"hptools/internal/models"
+)
+
+// ApplyLayout moves the main windows of running applications according to a
+// layout. Entries are matched by executable name, case-insensitively; the
+// Windows ".exe" suffix is ignored so layouts can be shared. Applications
+// that are not running are skipped, since launch targets are Windows-only.
+func (w *WindowManager) ApplyLayout(layout models.Layout) error {
+	windows, err := w.windows()
+	if err != nil {
+		return err
+	}
+
+	byImage := make(map[string]int)
+	for _, win := range windows {
+		pid := win.node.PID
+		if pid <= 0 {
+			continue
+		}
+		name, err := w.imageName(pid)
+		if err != nil {
+			w.logger.Debug("Failed to get image name", "pid", pid, "error", err)
+			continue
+		}
+		byImage[layoutKey(name)] = pid
+	}
+
+	var errs []error
+	for _, entry := range layout.Windows {
+		pid, ok := byImage[layoutKey(entry.ImageName)]
+		if !ok {
+			w.logger.Debug("Layout window not running", "layout", layout.Name, "imageName", entry.ImageName)
+			continue
+		}
+		win, _ := mainWindow(windows, pid)
+		if err := w.applyWindowTarget(win, entry.WindowTarget); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.ImageName, err))
+		}
+	}
+
+	w.logger.Info("Layout applied", "layout", layout.Name, "failures", len(errs))
+	return errors.Join(errs...)
+}
+
+// applyWindowTarget moves a window to a target. A monitor and a placement are
+// combined into one move, so the window does not flash on the way.
+func (w *WindowManager) applyWindowTarget(win windowNode, target models.WindowTarget) error {
+	if target.Empty() {
+		return errors.New("target has neither rect, placement nor monitor")
+	}
+	if r := target.Rect; r != nil {
+		return w.setWindowPosition(win, r.X, r.Y, r.Width, r.Height)
+	}
+
+	monitors, err := w.GetMonitors()
+	if err != nil {
+		return err
+	}
+	current := win.node.Rect.toModel()
+	monitor := geometry.MonitorAt(monitors, current)
+	if monitor < 0 {
+		return errors.New("no active outputs")
+	}
+
+	if target.Monitor != nil {
+		to := *target.Monitor
+		if to < 0 || to >= len(monitors) {
+			return fmt.Errorf("monitor %d does not exist", to)
+		}
+		current = geometry.Translate(current, monitors[monitor].WorkArea, monitors[to].WorkArea)
+		monitor = to
+	}
+	if target.Placement != "" {
+		current, err = geometry.Place(target.Placement, current, monitors, monitor)
+		if err != nil {
+			return fmt.Errorf("computing placement: %w", err)
+		}
+	}
+
+	if err := w.moveResize(win.node.ID, current); err != nil {
+		return fmt.Errorf("setting window position: %w", err)
+	}
+	w.recordRecent(win.node.PID, win.node.Name)
+	w.logger.Info("Window target applied", "pid", win.node.PID, "x", current.X, "y", current.Y, "width", current.Width, "height", current.Height)
+	return nil
+}
+
+// layoutKey normalizes an executable name for matching layout entries
+func layoutKey(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".exe")
+}
+
+// procImageName returns the executable name of a process. The exe link of
+// processes owned by other users cannot be read; their command name, which
+// the kernel truncates to 15 bytes, is used instead.
+func procImageName(pid int) (string, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	if exe, err := os.Readlink(filepath.Join(dir, "exe")); err == nil {
+		return filepath.Base(exe), nil
+	}
+	comm, err := os.ReadFile(filepath.Join(dir, "comm"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(comm)), nil
+}
@@ -0,0 +1,103 @@
+package sway
+
+import "hptools/internal/models"
+
+// Rect is a rectangle as reported by the IPC protocol
+type Rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// toModel converts the IPC rect to a models.Rect
+func (r Rect) toModel() models.Rect {
+	return models.Rect{X: r.X, Y: r.Y, Width: r.Width, Height: r.Height}
+}
+
+// Node is a container in the layout tree
+type Node struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Focused bool   `json:"focused"`
+	Rect    Rect   `json:"rect"`
+	// PID is only reported by sway; for i3 it is read from the X11 window
+	PID           int     `json:"pid"`
+	AppID         string  `json:"app_id"`
+	Window        int64   `json:"window"`
+	Nodes         []*Node `json:"nodes"`
+	FloatingNodes []*Node `json:"floating_nodes"`
+}
+
+// Output is a display as reported by GET_OUTPUTS
+type Output struct {
+	Name             string `json:"name"`
+	Active           bool   `json:"active"`
+	Primary          bool   `json:"primary"`
+	Rect             Rect   `json:"rect"`
+	CurrentWorkspace string `json:"current_workspace"`
+}
+
+// Workspace is a workspace as reported by GET_WORKSPACES
+type Workspace struct {
+	Num     int    `json:"num"`
+	Name    string `json:"name"`
+	Visible bool   `json:"visible"`
+	Focused bool   `json:"focused"`
+	Rect    Rect   `json:"rect"`
+	Output  string `json:"output"`
+}
+
+// windowNode is an application window together with its workspace
+type windowNode struct {
+	node      *Node
+	workspace string
+}
+
+// isWindow reports whether a node holds an application window
+func (n *Node) isWindow() bool {
+	return (n.Type == "con" || n.Type == "floating_con") && (n.PID > 0 || n.Window > 0)
+}
+
+// windows returns all application windows below the node
+func (n *Node) windows() []windowNode {
+	var found []windowNode
+	var walk func(node *Node, workspace string)
+	walk = func(node *Node, workspace string) {
+		if node.Type == "workspace" {
+			workspace = node.Name
+		}
+		if node.isWindow() {
+			found = append(found, windowNode{node: node, workspace: workspace})
+		}
+		for _, child := range node.Nodes {
+			walk(child, workspace)
+		}
+		for _, child := range node.FloatingNodes {
+			walk(child, workspace)
+		}
+	}
+	walk(n, "")
+	return found
+}
+
+// mainWindow picks the window of a process to act on: the focused one,
+// otherwise the largest
+func mainWindow(windows []windowNode, pid int) (windowNode, bool) {
+	var best windowNode
+	found := false
+	for _, w := range windows {
+		if w.node.PID != pid {
+			continue
+		}
+		if w.node.Focused {
+			return w, true
+		}
+		area := w.node.Rect.Width * w.node.Rect.Height
+		if !found || area > best.node.Rect.Width*best.node.Rect.Height {
+			best, found = w, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,540 @@
+package sway
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	apperrors "hptools/internal/errors"
+	"hptools/internal/events"
+	"hptools/internal/geometry"
+	"hptools/internal/models"
+)
+
+// recentWindowLimit is the number of recently managed windows to remember
+const recentWindowLimit = 5
+
+// WindowManager implements the services.WindowManager operations on sway
+// and i3. Windows are made floating before they are moved or resized,
+// because tiled window geometry is controlled by the layout.
+type WindowManager struct {
+	client *Client
+	pids   PIDResolver
+	logger *slog.Logger
+	// imageName returns the executable name of a process, for layouts
+	imageName func(pid int) (string, error)
+
+	recent *events.RecentWindows
+	cycles *geometry.CycleTracker
+
+	mu     sync.Mutex
+	policy models.BoundsPolicy
+}
+
+// NewWindowManager creates a window manager that talks to the given IPC
+// client. pids fills in the PIDs of X11 windows for i3 and may be nil for sway.
+func NewWindowManager(client *Client, pids PIDResolver, logger *slog.Logger) *WindowManager {
+	return &WindowManager{
+		client:    client,
+		pids:      pids,
+		logger:    logger,
+		imageName: procImageName,
+		recent:    events.NewRecentWindows(recentWindowLimit),
+		cycles:    geometry.NewCycleTracker(),
+		policy:    models.BoundsReject,
+	}
+}
+
+// SetBoundsPolicy sets how rects outside the outputs are handled by
+// SetWindowSize and SetWindowPosition
+func (w *WindowManager) SetBoundsPolicy(policy models.BoundsPolicy) error {
+	switch policy {
+	case models.BoundsReject, models.BoundsClamp, models.BoundsAllow:
+	case "":
+		policy = models.BoundsReject
+	default:
+		return apperrors.NewConfigError(fmt.Sprintf("unknown bounds policy %q", policy), nil)
+	}
+
+	w.mu.Lock()
+	w.policy = policy
+	w.mu.Unlock()
+	return nil
+}
+
+// SetWindowSize sets the size of a window by process PID, keeping current position
+func (w *WindowManager) SetWindowSize(pid int, width, height int) error {
+	win, err := w.findWindow(pid)
+	if err != nil {
+		return err
+	}
+	return w.setWindowSize(win, width, height)
+}
+
+// SetWindowSizeByHandle sets the size of the window with the given container ID
+func (w *WindowManager) SetWindowSizeByHandle(handle uintptr, width, height int) error {
+	win, err := w.findWindowByID(int64(handle))
+	if err != nil {
+		return err
+	}
+	return w.setWindowSize(win, width, height)
+}
+
+// setWindowSize applies SetWindowSize to a resolved window
+func (w *WindowManager) setWindowSize(win windowNode, width, height int) error {
+	target, err := w.confine(models.Rect{X: win.node.Rect.X, Y: win.node.Rect.Y, Width: width, Height: height})
+	if err != nil {
+		return err
+	}
+
+	if err := w.moveResize(win.node.ID, target); err != nil {
+		return fmt.Errorf("setting window size: %w", err)
+	}
+
+	w.recordRecent(win.node.PID, win.node.Name)
+	w.logger.Info("Window size changed", "pid", win.node.PID, "width", target.Width, "height", target.Height)
+	return nil
+}
+
+// SetWindowPosition sets both position and size of a window
+func (w *WindowManager) SetWindowPosition(pid int, x, y, width, height int) error {
+	win, err := w.findWindow(pid)
+	if err != nil {
+		return err
+	}
+	return w.setWindowPosition(win, x, y, width, height)
+}
+
+// SetWindowPositionByHandle sets position and size of the window with the given container ID
+func (w *WindowManager) SetWindowPositionByHandle(handle uintptr, x, y, width, height int) error {
+	win, err := w.findWindowByID(int64(handle))
+	if err != nil {
+		return err
+	}
+	return w.setWindowPosition(win, x, y, width, height)
+}
+
+// setWindowPosition applies SetWindowPosition to a resolved window
+func (w *WindowManager) setWindowPosition(win windowNode, x, y, width, height int) error {
+	target, err := w.confine(models.Rect{X: x, Y: y, Width: width, Height: height})
+	if err != nil {
+		return err
+	}
+
+	if err := w.moveResize(win.node.ID, target); err != nil {
+		return fmt.Errorf("setting window position: %w", err)
+	}
+
+	w.recordRecent(win.node.PID, win.node.Name)
+	w.logger.Info("Window position changed", "pid", win.node.PID, "x", target.X, "y", target.Y, "width", target.Width, "height", target.Height)
+	return nil
+}
+
+// confine applies the bounds policy to a requested rect. Sway draws no
+// invisible borders, so the container rect is the visible frame.
+func (w *WindowManager) confine(rect models.Rect) (models.Rect, error) {
+	w.mu.Lock()
+	policy := w.policy
+	w.mu.Unlock()
+
+	if policy == models.BoundsAllow {
+		return rect, nil
+	}
+
+	monitors, err := w.GetMonitors()
+	if err != nil {
+		return models.Rect{}, err
+	}
+
+	target, err := geometry.Confine(rect, monitors, policy)
+	if errors.Is(err, geometry.ErrUnreachable) {
+		return models.Rect{}, apperrors.NewBoundsError(
+			fmt.Sprintf("%dx%d at %d,%d would be off screen", rect.Width, rect.Height, rect.X, rect.Y), err)
+	}
+	if err != nil {
+		return models.Rect{}, err
+	}
+
+	if target != rect {
+		w.logger.Debug("Window rect clamped to work area", "requested", rect, "applied", target)
+	}
+	return target, nil
+}
+
+// GetWindowInfo gets the current size and position of a window.
+// DesktopID is the name of the workspace the window is on.
+func (w *WindowManager) GetWindowInfo(pid int) (*models.WindowInfo, error) {
+	win, err := w.findWindow(pid)
+	if err != nil {
+		return nil, err
+	}
+	return w.getWindowInfo(win)
+}
+
+// GetWindowInfoByHandle gets the current size and position of the window with the given container ID
+func (w *WindowManager) GetWindowInfoByHandle(handle uintptr) (*models.WindowInfo, error) {
+	win, err := w.findWindowByID(int64(handle))
+	if err != nil {
+		return nil, err
+	}
+	return w.getWindowInfo(win)
+}
+
+// getWindowInfo applies GetWindowInfo to a resolved window
+func (w *WindowManager) getWindowInfo(win windowNode) (*models.WindowInfo, error) {
+	r := win.node.Rect
+	return &models.WindowInfo{
+		X:         r.X,
+		Y:         r.Y,
+		Width:     r.Width,
+		Height:    r.Height,
+		DesktopID: win.workspace,
+	}, nil
+}
+
+// FindWindowByPID returns the container ID of the main window of a process
+func (w *WindowManager) FindWindowByPID(pid int) (uintptr, error) {
+	win, err := w.findWindow(pid)
+	if err != nil {
+		return 0, err
+	}
+	return uintptr(win.node.ID), nil
+}
+
+// ApplyPlacement moves a window to a predefined placement on its output
+func (w *WindowManager) ApplyPlacement(pid int, placement models.Placement) error {
+	win, err := w.findWindow(pid)
+	if err != nil {
+		return err
+	}
+	return w.applyPlacement(win, placement)
+}
+
+// ApplyPlacementByHandle moves the window with the given container ID to a predefined placement
+func (w *WindowManager) ApplyPlacementByHandle(handle uintptr, placement models.Placement) error {
+	win, err := w.findWindowByID(int64(handle))
+	if err != nil {
+		return err
+	}
+	return w.applyPlacement(win, placement)
+}
+
+// applyPlacement applies ApplyPlacement to a resolved window
+func (w *WindowManager) applyPlacement(win windowNode, placement models.Placement) error {
+	if _, err := w.place(win, placement); err != nil {
+		return err
+	}
+
+	w.recordRecent(win.node.PID, win.node.Name)
+	w.logger.Info("Window placement applied", "pid", win.node.PID, "placement", placement)
+	return nil
+}
+
+// CyclePlacement applies the next placement of a cycle. Repeated calls for
+// the same window step through the cycle as long as the window stays where
+// the previous step put it; otherwise the cycle starts over.
+func (w *WindowManager) CyclePlacement(pid int, cycle []models.Placement) error {
+	if len(cycle) == 0 {
+		return errors.New("empty placement cycle")
+	}
+
+	win, err := w.findWindow(pid)
+	if err != nil {
+		return err
+	}
+
+	id := uintptr(win.node.ID)
+	index := w.cycles.Next(id, cycle, win.node.Rect.toModel())
+	target, err := w.place(win, cycle[index])
+	if err != nil {
+		return err
+	}
+	w.cycles.Record(id, cycle, index, target)
+
+	w.recordRecent(pid, win.node.Name)
+	w.logger.Info("Window placement cycled", "pid", pid, "placement", cycle[index], "step", index)
+	return nil
+}
+
+// place moves a window to a placement on its output and returns the applied rect
+func (w *WindowManager) place(win windowNode, placement models.Placement) (models.Rect, error) {
+	monitors, err := w.GetMonitors()
+	if err != nil {
+		return models.Rect{}, err
+	}
+
+	current := win.node.Rect.toModel()
+	target, err := geometry.Place(placement, current, monitors, geometry.MonitorAt(monitors, current))
+	if err != nil {
+		return models.Rect{}, fmt.Errorf("computing placement: %w", err)
+	}
+
+	if err := w.moveResize(win.node.ID, target); err != nil {
+		return models.Rect{}, fmt.Errorf("setting window position: %w", err)
+	}
+	return target, nil
+}
+
+// SwapWindows exchanges the rects of the main windows of two processes.
+// Both windows end up floating.
+func (w *WindowManager) SwapWindows(pidA, pidB int) error {
+	winA, err := w.findWindow(pidA)
+	if err != nil {
+		return err
+	}
+	winB, err := w.findWindow(pidB)
+	if err != nil {
+		return err
+	}
+	if winA.node.ID == winB.node.ID {
+		return errors.New("cannot swap a window with itself")
+	}
+
+	if err := w.moveResize(winA.node.ID, winB.node.Rect.toModel()); err != nil {
+		return fmt.Errorf("setting window position: %w", err)
+	}
+	if err := w.moveResize(winB.node.ID, winA.node.Rect.toModel()); err != nil {
+		return fmt.Errorf("setting window position: %w", err)
+	}
+
+	w.recordRecent(pidB, winB.node.Name)
+	w.recordRecent(pidA, winA.node.Name)
+	w.logger.Info("Windows swapped", "pidA", pidA, "pidB", pidB)
+	return nil
+}
+
+// AdjustWindow moves or resizes a window relative to its current rect,
+// clamped to the work area of the output it is on
+func (w *WindowManager) AdjustWindow(pid int, adjust models.WindowAdjustment) error {
+	win, err := w.findWindow(pid)
+	if err != nil {
+		return err
+	}
+	return w.adjustWindow(win, adjust)
+}
+
+// AdjustWindowByHandle moves or resizes the window with the given container ID
+func (w *WindowManager) AdjustWindowByHandle(handle uintptr, adjust models.WindowAdjustment) error {
+	win, err := w.findWindowByID(int64(handle))
+	if err != nil {
+		return err
+	}
+	return w.adjustWindow(win, adjust)
+}
+
+// adjustWindow applies AdjustWindow to a resolved window
+func (w *WindowManager) adjustWindow(win windowNode, adjust models.WindowAdjustment) error {
+	monitors, err := w.GetMonitors()
+	if err != nil {
+		return err
+	}
+
+	current := win.node.Rect.toModel()
+	idx := geometry.MonitorAt(monitors, current)
+	if idx < 0 {
+		return errors.New("no active output")
+	}
+
+	target, err := geometry.Adjust(adjust, current, monitors[idx].WorkArea)
+	if err != nil {
+		return fmt.Errorf("computing adjustment: %w", err)
+	}
+
+	if err := w.moveResize(win.node.ID, target); err != nil {
+		return fmt.Errorf("setting window position: %w", err)
+	}
+
+	w.recordRecent(win.node.PID, win.node.Name)
+	w.logger.Info("Window adjusted", "pid", win.node.PID, "kind", adjust.Kind, "x", target.X, "y", target.Y, "width", target.Width, "height", target.Height)
+	return nil
+}
+
+// GetMonitors returns the active outputs. The work area is the rect of the
+// visible workspace, which excludes bars.
+func (w *WindowManager) GetMonitors() ([]models.MonitorInfo, error) {
+	outputs, err := w.client.GetOutputs()
+	if err != nil {
+		return nil, fmt.Errorf("getting outputs: %w", err)
+	}
+	workspaces, err := w.client.GetWorkspaces()
+	if err != nil {
+		return nil, fmt.Errorf("getting workspaces: %w", err)
+	}
+
+	var monitors []models.MonitorInfo
+	for i, out := range outputs {
+		if !out.Active {
+			continue
+		}
+		monitor := models.MonitorInfo{
+			Handle:   uintptr(i + 1),
+			Name:     out.Name,
+			Primary:  out.Primary,
+			Bounds:   out.Rect.toModel(),
+			WorkArea: out.Rect.toModel(),
+		}
+		for _, ws := range workspaces {
+			if ws.Output == out.Name && ws.Visible {
+				monitor.WorkArea = ws.Rect.toModel()
+			}
+		}
+		monitors = append(monitors, monitor)
+	}
+	return monitors, nil
+}
+
+// GetVirtualDesktops returns the workspaces, which are the sway equivalent
+func (w *WindowManager) GetVirtualDesktops() ([]models.VirtualDesktop, error) {
+	workspaces, err := w.client.GetWorkspaces()
+	if err != nil {
+		return nil, fmt.Errorf("getting workspaces: %w", err)
+	}
+
+	desktops := make([]models.VirtualDesktop, len(workspaces))
+	for i, ws := range workspaces {
+		desktops[i] = models.VirtualDesktop{
+			ID:      ws.Name,
+			Index:   i,
+			Name:    ws.Name,
+			Current: ws.Focused,
+		}
+	}
+	return desktops, nil
+}
+
+// MoveWindowToDesktop moves a window to the workspace with the given index
+func (w *WindowManager) MoveWindowToDesktop(pid int, desktop int) error {
+	win, err := w.findWindow(pid)
+	if err != nil {
+		return err
+	}
+	return w.moveWindowToDesktop(win, desktop)
+}
+
+// MoveWindowToDesktopByHandle moves the window with the given container ID to a workspace
+func (w *WindowManager) MoveWindowToDesktopByHandle(handle uintptr, desktop int) error {
+	win, err := w.findWindowByID(int64(handle))
+	if err != nil {
+		return err
+	}
+	return w.moveWindowToDesktop(win, desktop)
+}
+
+// moveWindowToDesktop applies MoveWindowToDesktop to a resolved window
+func (w *WindowManager) moveWindowToDesktop(win windowNode, desktop int) error {
+	desktops, err := w.GetVirtualDesktops()
+	if err != nil {
+		return err
+	}
+	if desktop < 0 || desktop >= len(desktops) {
+		return fmt.Errorf("workspace %d does not exist", desktop)
+	}
+
+	cmd := fmt.Sprintf("[con_id=%d] move container to workspace %s", win.node.ID, quote(desktops[desktop].Name))
+	if err := w.client.RunCommand(cmd); err != nil {
+		return fmt.Errorf("moving window to workspace: %w", err)
+	}
+
+	w.recordRecent(win.node.PID, win.node.Name)
+	w.logger.Info("Window moved to workspace", "pid", win.node.PID, "workspace", desktops[desktop].Name)
+	return nil
+}
+
+// X11Window returns the X11 window ID of the main window of a process, for
+// capturing it. Native Wayland windows under sway have none.
+func (w *WindowManager) X11Window(pid int) (uint32, error) {
+	win, err := w.findWindow(pid)
+	if err != nil {
+		return 0, err
+	}
+	if win.node.Window <= 0 {
+		return 0, fmt.Errorf("window of PID %d is not an X11 window", pid)
+	}
+	return uint32(win.node.Window), nil
+}
+
+// RecentWindows returns the most recently managed windows, newest first
+func (w *WindowManager) RecentWindows() []models.RecentWindow {
+	return w.recent.List()
+}
+
+// OnRecentWindowsChanged registers a listener called when the recent list changes.
+// It returns a function that removes the listener.
+func (w *WindowManager) OnRecentWindowsChanged(fn func()) func() {
+	return w.recent.Subscribe(fn)
+}
+
+// windows returns the application windows of the current tree, with the
+// PIDs of X11 windows looked up when the tree does not report them
+func (w *WindowManager) windows() ([]windowNode, error) {
+	tree, err := w.client.GetTree()
+	if err != nil {
+		return nil, fmt.Errorf("getting tree: %w", err)
+	}
+
+	windows := tree.windows()
+	if w.pids == nil {
+		return windows, nil
+	}
+	for _, win := range windows {
+		if win.node.PID > 0 || win.node.Window <= 0 {
+			continue
+		}
+		pid, err := w.pids.WindowPID(uint32(win.node.Window))
+		if err != nil {
+			w.logger.Debug("Failed to get window PID", "window", win.node.Window, "error", err)
+			continue
+		}
+		win.node.PID = pid
+	}
+	return windows, nil
+}
+
+// findWindow looks up the main window of a process in the current tree
+func (w *WindowManager) findWindow(pid int) (windowNode, error) {
+	windows, err := w.windows()
+	if err != nil {
+		return windowNode{}, err
+	}
+
+	win, ok := mainWindow(windows, pid)
+	if !ok {
+		return windowNode{}, fmt.Errorf("no window found for PID %d", pid)
+	}
+	return win, nil
+}
+
+// findWindowByID looks up a window by container ID in the current tree
+func (w *WindowManager) findWindowByID(id int64) (windowNode, error) {
+	windows, err := w.windows()
+	if err != nil {
+		return windowNode{}, err
+	}
+
+	for _, win := range windows {
+		if win.node.ID == id {
+			return win, nil
+		}
+	}
+	return windowNode{}, fmt.Errorf("no window with container ID %d", id)
+}
+
+// moveResize makes a container floating and gives it an absolute rect
+func (w *WindowManager) moveResize(id int64, r models.Rect) error {
+	return w.client.RunCommand(fmt.Sprintf(
+		"[con_id=%d] floating enable, resize set width %d px height %d px, move absolute position %d px %d px",
+		id, r.Width, r.Height, r.X, r.Y,
+	))
+}
+
+// recordRecent moves the window to the front of the recent list
+func (w *WindowManager) recordRecent(pid int, title string) {
+	w.recent.Record(models.RecentWindow{PID: pid, Title: title})
+}
+
+// quote quotes a command argument for the sway and i3 command parsers
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
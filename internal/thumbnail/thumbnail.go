@@ -0,0 +1,45 @@
+// Package thumbnail scales window captures down to preview size. It is shared
+// by the Win32 and X11 capture paths.
+package thumbnail
+
+import "image"
+
+// Downscale shrinks img to fit maxWidth x maxHeight, keeping the aspect ratio.
+// Each target pixel averages its source box. Smaller images are returned as is.
+func Downscale(img *image.NRGBA, maxWidth, maxHeight int) *image.NRGBA {
+	srcW, srcH := img.Rect.Dx(), img.Rect.Dy()
+	if srcW <= maxWidth && srcH <= maxHeight {
+		return img
+	}
+
+	dstW, dstH := maxWidth, srcH*maxWidth/srcW
+	if dstH > maxHeight {
+		dstW, dstH = srcW*maxHeight/srcH, maxHeight
+	}
+	dstW, dstH = max(dstW, 1), max(dstH, 1)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, max((y+1)*srcH/dstH, y*srcH/dstH+1)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, max((x+1)*srcW/dstW, x*srcW/dstW+1)
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := img.Pix[sy*img.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					b += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0], d[1], d[2], d[3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+	return dst
+}
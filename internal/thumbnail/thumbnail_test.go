@@ -0,0 +1,38 @@
+package thumbnail
+
+import (
+	"image"
+	"testing"
+)
+
+func TestDownscale(t *testing.T) {
+	tests := []struct {
+		name                string
+		width, height       int
+		maxWidth, maxHeight int
+		wantW, wantH        int
+	}{
+		{"fits already", 320, 180, 320, 180, 320, 180},
+		{"width bound", 1920, 1080, 320, 320, 320, 180},
+		{"height bound", 1080, 1920, 320, 180, 101, 180},
+		{"never below one pixel", 4000, 10, 100, 100, 100, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewNRGBA(image.Rect(0, 0, tt.width, tt.height))
+			got := Downscale(img, tt.maxWidth, tt.maxHeight)
+			if got.Rect.Dx() != tt.wantW || got.Rect.Dy() != tt.wantH {
+				t.Errorf("Downscale() = %dx%d, want %dx%d", got.Rect.Dx(), got.Rect.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+
+	// Each target pixel averages its source box
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	copy(img.Pix, []byte{0, 100, 200, 255, 100, 200, 0, 255})
+	got := Downscale(img, 1, 1)
+	if want := []byte{50, 150, 100, 255}; string(got.Pix) != string(want) {
+		t.Errorf("Downscale() pixel = %v, want %v", got.Pix, want)
+	}
+}
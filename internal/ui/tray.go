@@ -1,16 +1,66 @@
 package ui
 
 import (
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 
 	"hptools/internal/config"
+	"hptools/internal/models"
+	"hptools/internal/services"
 )
 
+// SystrayDeps holds the services the tray menu is built from
+type SystrayDeps struct {
+	Windows      services.WindowService
+	Layouts      services.LayoutManager
+	Workspaces   services.WorkspaceManager
+	Tiler        services.Tiler
+	Scripts      services.ScriptEngine
+	Rules        services.RulesPauser
+	PauseRules   func(paused bool) error
+	ReloadConfig func() error
+	Logger       *slog.Logger
+}
+
+// trayPlacements are the quick placements offered for each recent window
+var trayPlacements = []struct {
+	label     string
+	placement models.Placement
+}{
+	{"Left Half", models.PlacementLeftHalf},
+	{"Right Half", models.PlacementRightHalf},
+	{"Center", models.PlacementCenter},
+	{"Next Monitor", models.PlacementNextMonitor},
+	{"Previous Monitor", models.PlacementPreviousMonitor},
+}
+
+// trayCycles are the placement cycles offered for each recent window
+var trayCycles = []struct {
+	label string
+	cycle []models.Placement
+}{
+	{"Cycle Left", models.CycleLeft},
+	{"Cycle Right", models.CycleRight},
+}
+
+// trayTilingLayouts are the tiling layouts offered in the tray
+var trayTilingLayouts = []struct {
+	label  string
+	layout models.TilingLayout
+}{
+	{"Master and Stack", models.TilingMasterStack},
+	{"Columns", models.TilingColumns},
+	{"Grid", models.TilingGrid},
+	{"Monocle", models.TilingMonocle},
+}
+
 // SetupSystray initializes the system tray, menu and attaches window behavior.
-// It returns a cleanup function that can be called on shutdown (currently no-op but left for future use).
-func SetupSystray(app *application.App, win application.Window, cfg config.SystrayConfig) func() {
+// The menu is rebuilt whenever the saved layouts, workspaces, tiling state, scripts or recently managed windows change.
+// It returns a cleanup function that removes the listeners and the tray icon.
+func SetupSystray(app *application.App, win application.Window, cfg config.SystrayConfig, deps SystrayDeps) func() {
 	systray := app.SystemTray.New()
 	systray.SetLabel(cfg.Label)
 
@@ -19,20 +69,212 @@ func SetupSystray(app *application.App, win application.Window, cfg config.Systr
 	systray.WindowOffset(cfg.WindowOffset)
 	systray.WindowDebounce(time.Duration(cfg.DebounceMS) * time.Millisecond)
 
-	// Build menu
+	rebuild := func() {
+		application.InvokeAsync(func() {
+			systray.SetMenu(buildTrayMenu(app, win, deps))
+		})
+	}
+
+	systray.SetMenu(buildTrayMenu(app, win, deps))
+
+	unsubscribe := []func(){
+		deps.Layouts.OnLayoutsChanged(rebuild),
+		deps.Workspaces.OnWorkspacesChanged(rebuild),
+		deps.Tiler.OnTilingChanged(rebuild),
+		deps.Scripts.OnScriptsChanged(rebuild),
+		deps.Windows.OnRecentWindowsChanged(rebuild),
+	}
+
+	return func() {
+		for _, fn := range unsubscribe {
+			fn()
+		}
+		systray.Destroy()
+	}
+}
+
+// buildTrayMenu creates the tray menu from the current layouts, workspaces, scripts and recent windows
+func buildTrayMenu(app *application.App, win application.Window, deps SystrayDeps) *application.Menu {
 	menu := application.NewMenu()
 	menu.Add("Open").OnClick(func(*application.Context) {
 		win.Show()
 	})
+	menu.AddSeparator()
+
+	layoutsMenu := menu.AddSubmenu("Layouts")
+	layouts := deps.Layouts.ListLayouts()
+	if len(layouts) == 0 {
+		layoutsMenu.Add("No saved layouts").SetEnabled(false)
+	}
+	for _, layout := range layouts {
+		name := layout.Name
+		layoutsMenu.Add(name).OnClick(func(*application.Context) {
+			// Layout entries may launch apps and wait for their windows
+			go func() {
+				if err := deps.Layouts.ApplyLayout(name); err != nil {
+					deps.Logger.Error("Failed to apply layout", "layout", name, "error", err)
+				}
+			}()
+		})
+	}
+
+	workspacesMenu := menu.AddSubmenu("Workspaces")
+	workspaces := deps.Workspaces.ListWorkspaces()
+	if len(workspaces) == 0 {
+		workspacesMenu.Add("No workspaces").SetEnabled(false)
+	}
+	for _, workspace := range workspaces {
+		name := workspace.Name
+		workspacesMenu.Add(name).OnClick(func(*application.Context) {
+			// Launches wait for windows to appear, so keep the menu responsive
+			go func() {
+				result, err := deps.Workspaces.RunWorkspace(name)
+				if err != nil {
+					deps.Logger.Error("Failed to run workspace", "workspace", name, "error", err)
+					return
+				}
+				for _, status := range result.Apps {
+					deps.Logger.Info("Workspace app", "workspace", name, "app", status.Name, "state", status.State, "error", status.Error)
+				}
+			}()
+		})
+	}
+
+	scriptsMenu := menu.AddSubmenu("Scripts")
+	scripts := deps.Scripts.Scripts()
+	if len(scripts) == 0 {
+		scriptsMenu.Add("No scripts").SetEnabled(false)
+	}
+	for _, name := range scripts {
+		scriptsMenu.Add(name).OnClick(func(*application.Context) {
+			// Scripts may run until their timeout, so keep the menu responsive
+			go func() {
+				if err := deps.Scripts.RunScript(name); err != nil {
+					deps.Logger.Error("Failed to run script", "script", name, "error", err)
+				}
+			}()
+		})
+	}
+
+	addTilingMenu(menu, deps)
+
+	recentMenu := menu.AddSubmenu("Recent Windows")
+	recent := deps.Windows.RecentWindows()
+	if len(recent) == 0 {
+		recentMenu.Add("No recent windows").SetEnabled(false)
+	}
+	for _, rw := range recent {
+		pid := rw.PID
+		windowMenu := recentMenu.AddSubmenu(fmt.Sprintf("%s (%d)", rw.Title, rw.PID))
+		for _, p := range trayPlacements {
+			placement := p.placement
+			windowMenu.Add(p.label).OnClick(func(*application.Context) {
+				if err := deps.Windows.ApplyPlacement(pid, placement); err != nil {
+					deps.Logger.Error("Failed to apply placement", "pid", pid, "placement", placement, "error", err)
+				}
+			})
+		}
+		for _, c := range trayCycles {
+			cycle := c.cycle
+			windowMenu.Add(c.label).OnClick(func(*application.Context) {
+				if err := deps.Windows.CyclePlacement(pid, cycle); err != nil {
+					deps.Logger.Error("Failed to cycle placement", "pid", pid, "error", err)
+				}
+			})
+		}
+		if len(recent) > 1 {
+			swapMenu := windowMenu.AddSubmenu("Swap With")
+			for _, other := range recent {
+				if other.PID == pid {
+					continue
+				}
+				otherPID := other.PID
+				swapMenu.Add(fmt.Sprintf("%s (%d)", other.Title, other.PID)).OnClick(func(*application.Context) {
+					if err := deps.Windows.SwapWindows(pid, otherPID); err != nil {
+						deps.Logger.Error("Failed to swap windows", "pid", pid, "other", otherPID, "error", err)
+					}
+				})
+			}
+		}
+	}
+
+	menu.AddSeparator()
+	pauseItem := menu.AddCheckbox("Pause Rules", deps.Rules.Paused())
+	pauseItem.OnClick(func(ctx *application.Context) {
+		paused := ctx.ClickedMenuItem().Checked()
+		if err := deps.PauseRules(paused); err != nil {
+			deps.Logger.Error("Failed to pause rules", "paused", paused, "error", err)
+		}
+		pauseItem.SetChecked(deps.Rules.Paused())
+	})
+	menu.Add("Rescue Offscreen Windows").OnClick(func(*application.Context) {
+		rescued, err := deps.Windows.RescueOffscreenWindows()
+		if err != nil {
+			deps.Logger.Error("Failed to rescue offscreen windows", "error", err)
+			return
+		}
+		deps.Logger.Info("Offscreen windows rescued", "count", len(rescued))
+	})
+	menu.Add("Reload Config").OnClick(func(*application.Context) {
+		if err := deps.ReloadConfig(); err != nil {
+			deps.Logger.Error("Failed to reload config", "error", err)
+		}
+		// The reloaded config may pause or resume the rules
+		pauseItem.SetChecked(deps.Rules.Paused())
+	})
 
 	menu.Add("Quit").OnClick(func(*application.Context) {
 		app.Quit()
 	})
 
-	systray.SetMenu(menu)
+	return menu
+}
 
-	// Return a cleanup function placeholder
-	return func() {
-		// Future cleanup like removing icons, saving state, etc.
+// addTilingMenu adds the tiling submenu: toggling tiling, the layouts and
+// master ratio and gap adjustments
+func addTilingMenu(menu *application.Menu, deps SystrayDeps) {
+	tilingMenu := menu.AddSubmenu("Tiling")
+	options := deps.Tiler.Options()
+
+	label := "Enable Tiling"
+	if options.Enabled {
+		label = "Disable Tiling"
+	}
+	tilingMenu.Add(label).OnClick(func(*application.Context) {
+		options := deps.Tiler.Options()
+		options.Enabled = !options.Enabled
+		if err := deps.Tiler.SetOptions(options); err != nil {
+			deps.Logger.Error("Failed to toggle tiling", "error", err)
+		}
+	})
+	tilingMenu.AddSeparator()
+
+	for _, l := range trayTilingLayouts {
+		layout := l.layout
+		tilingMenu.Add(l.label).OnClick(func(*application.Context) {
+			if err := deps.Tiler.SetLayout(layout); err != nil {
+				deps.Logger.Error("Failed to set tiling layout", "layout", layout, "error", err)
+			}
+		})
+	}
+	tilingMenu.AddSeparator()
+
+	adjustments := []struct {
+		label string
+		apply func() error
+	}{
+		{"Grow Master", func() error { return deps.Tiler.AdjustMasterRatio(0.05) }},
+		{"Shrink Master", func() error { return deps.Tiler.AdjustMasterRatio(-0.05) }},
+		{"Increase Gaps", func() error { return deps.Tiler.AdjustGap(4) }},
+		{"Decrease Gaps", func() error { return deps.Tiler.AdjustGap(-4) }},
+		{"Retile", deps.Tiler.Retile},
+	}
+	for _, a := range adjustments {
+		apply := a.apply
+		tilingMenu.Add(a.label).OnClick(func(*application.Context) {
+			if err := apply(); err != nil {
+				deps.Logger.Error("Failed to adjust tiling", "error", err)
+			}
+		})
 	}
 }
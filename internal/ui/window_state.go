@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+
+	"hptools/internal/config"
+	"hptools/internal/geometry"
+	"hptools/internal/models"
+)
+
+// windowStateSaveDelay is how long moves and resizes must stop before the
+// window state is written, so dragging does not write on every event
+const windowStateSaveDelay = time.Second
+
+// MainWindowOptions builds the main window options from the config and the
+// saved state. The size always respects the configured limits. The saved
+// position is only used if its monitor is still connected and the window
+// can be reached there; otherwise the window starts centered.
+func MainWindowOptions(cfg config.WindowConfig, state *config.WindowState, monitors []models.MonitorInfo) application.WebviewWindowOptions {
+	opts := application.WebviewWindowOptions{
+		Title:     cfg.Title,
+		Width:     cfg.Width,
+		Height:    cfg.Height,
+		MinWidth:  cfg.MinWidth,
+		MinHeight: cfg.MinHeight,
+		MaxWidth:  cfg.MaxWidth,
+		MaxHeight: cfg.MaxHeight,
+		Mac: application.MacWindow{
+			InvisibleTitleBarHeight: cfg.Mac.InvisibleTitleBarHeight,
+			Backdrop:                cfg.Mac.Backdrop,
+			TitleBar:                cfg.Mac.TitleBar,
+		},
+		BackgroundColour: cfg.BackgroundColour,
+		URL:              "/",
+	}
+
+	if state != nil {
+		opts.Hidden = state.Hidden
+		if state.Width > 0 && state.Height > 0 {
+			opts.Width, opts.Height = state.Width, state.Height
+		}
+	}
+	opts.Width = clampSize(opts.Width, cfg.MinWidth, cfg.MaxWidth)
+	opts.Height = clampSize(opts.Height, cfg.MinHeight, cfg.MaxHeight)
+
+	if state != nil && onMonitor(state, opts.Width, opts.Height, monitors) {
+		opts.X, opts.Y = state.X, state.Y
+		opts.InitialPosition = application.WindowXY
+	}
+	return opts
+}
+
+// clampSize limits size to [minSize, maxSize]; a zero limit means no limit
+func clampSize(size, minSize, maxSize int) int {
+	if maxSize > 0 {
+		size = min(size, maxSize)
+	}
+	return max(size, minSize)
+}
+
+// onMonitor reports whether the saved position is still usable: a monitor
+// with the saved bounds is connected and the window would be reachable there
+func onMonitor(state *config.WindowState, width, height int, monitors []models.MonitorInfo) bool {
+	rect := models.Rect{X: state.X, Y: state.Y, Width: width, Height: height}
+	for _, m := range monitors {
+		if m.Bounds == state.MonitorBounds {
+			return geometry.Reachable(rect, []models.MonitorInfo{m})
+		}
+	}
+	return false
+}
+
+// TrackWindowState records the position, size, monitor and visibility of win
+// and saves them to statePath shortly after they change. monitors is used to
+// find the monitor the window is on. The returned function stops tracking
+// and writes any state not saved yet.
+func TrackWindowState(win application.Window, statePath string, monitors func() ([]models.MonitorInfo, error), logger *slog.Logger) func() {
+	var (
+		mu    sync.Mutex
+		state config.WindowState
+		dirty bool
+		timer *time.Timer
+	)
+
+	save := func() {
+		mu.Lock()
+		if !dirty {
+			mu.Unlock()
+			return
+		}
+		snapshot := state
+		dirty = false
+		mu.Unlock()
+
+		if err := config.SaveWindowState(snapshot, statePath); err != nil {
+			logger.Warn("Failed to save window state", "error", err)
+		}
+	}
+
+	record := func(*application.WindowEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		state.Hidden = !win.IsVisible()
+		// Minimized and maximized rects are not what the window should restore to
+		if !state.Hidden && !win.IsMinimised() && !win.IsMaximised() {
+			state.X, state.Y = win.Position()
+			state.Width, state.Height = win.Size()
+			if all, err := monitors(); err == nil {
+				rect := models.Rect{X: state.X, Y: state.Y, Width: state.Width, Height: state.Height}
+				if idx := geometry.MonitorAt(all, rect); idx >= 0 {
+					state.MonitorBounds = all[idx].Bounds
+				}
+			}
+		}
+		dirty = true
+
+		if timer == nil {
+			timer = time.AfterFunc(windowStateSaveDelay, save)
+		} else {
+			timer.Reset(windowStateSaveDelay)
+		}
+	}
+
+	var unsubscribe []func()
+	for _, event := range []events.WindowEventType{
+		events.Common.WindowDidMove,
+		events.Common.WindowDidResize,
+		events.Common.WindowShow,
+		events.Common.WindowHide,
+	} {
+		unsubscribe = append(unsubscribe, win.OnWindowEvent(event, record))
+	}
+
+	return func() {
+		for _, fn := range unsubscribe {
+			fn()
+		}
+		mu.Lock()
+		if timer != nil {
+			timer.Stop()
+		}
+		mu.Unlock()
+		save()
+	}
+}
@@ -1,6 +1,7 @@
 package windows
 
 import (
+	"sync"
 	"syscall"
 	"unsafe"
 
@@ -10,6 +11,12 @@ import (
 // API wraps the Windows API calls for better testability and maintainability
 type API struct {
 	user32                       *syscall.LazyDLL
+	kernel32                     *syscall.LazyDLL
+	ntdll                        *syscall.LazyDLL
+	gdi32                        *syscall.LazyDLL
+	shell32                      *syscall.LazyDLL
+	dwmapi                       *syscall.LazyDLL
+	ole32                        *syscall.LazyDLL
 	procFindWindow               *syscall.LazyProc
 	procSetWindowPos             *syscall.LazyProc
 	procGetWindowRect            *syscall.LazyProc
@@ -17,13 +24,143 @@ type API struct {
 	procGetWindowThreadProcessId *syscall.LazyProc
 	procIsWindowVisible          *syscall.LazyProc
 	procGetWindowTextW           *syscall.LazyProc
+	procGetClassNameW            *syscall.LazyProc
+	procGetWindow                *syscall.LazyProc
+	procGetWindowLongW           *syscall.LazyProc
+	procDwmGetWindowAttribute    *syscall.LazyProc
+	procEnumChildWindows         *syscall.LazyProc
+	procMonitorFromWindow        *syscall.LazyProc
+	procGetMonitorInfoW          *syscall.LazyProc
+	procEnumDisplayMonitors      *syscall.LazyProc
+	procGetIconInfo              *syscall.LazyProc
+	procDestroyIcon              *syscall.LazyProc
+
+	procQueryFullProcessImageNameW *syscall.LazyProc
+	procIsWow64Process2            *syscall.LazyProc
+	procNtQueryInformationProcess  *syscall.LazyProc
+	procExtractIconExW             *syscall.LazyProc
+	procGetObjectW                 *syscall.LazyProc
+	procCreateCompatibleDC         *syscall.LazyProc
+	procGetDIBits                  *syscall.LazyProc
+	procDeleteObject               *syscall.LazyProc
+	procDeleteDC                   *syscall.LazyProc
+
+	procGetApplicationUserModelId   *syscall.LazyProc
+	procSHGetPropertyStoreForWindow *syscall.LazyProc
+	procPropVariantClear            *syscall.LazyProc
+	procCoInitializeEx              *syscall.LazyProc
+	procCoUninitialize              *syscall.LazyProc
+	procCoCreateInstance            *syscall.LazyProc
+	procProcessIdToSessionId        *syscall.LazyProc
+
+	procGetDC                  *syscall.LazyProc
+	procReleaseDC              *syscall.LazyProc
+	procGetWindowDC            *syscall.LazyProc
+	procPrintWindow            *syscall.LazyProc
+	procCreateCompatibleBitmap *syscall.LazyProc
+	procSelectObject           *syscall.LazyProc
+	procBitBlt                 *syscall.LazyProc
+	procSetWinEventHook        *syscall.LazyProc
+	procUnhookWinEvent         *syscall.LazyProc
+	procGetMessageW            *syscall.LazyProc
+	procPostThreadMessageW     *syscall.LazyProc
+	procGetCurrentThreadId     *syscall.LazyProc
+	procGetAncestor            *syscall.LazyProc
+
+	procRegisterClassExW           *syscall.LazyProc
+	procCreateWindowExW            *syscall.LazyProc
+	procDefWindowProcW             *syscall.LazyProc
+	procDestroyWindow              *syscall.LazyProc
+	procShowWindow                 *syscall.LazyProc
+	procSetLayeredWindowAttributes *syscall.LazyProc
+	procSetWindowRgn               *syscall.LazyProc
+	procTranslateMessage           *syscall.LazyProc
+	procDispatchMessageW           *syscall.LazyProc
+	procSetWindowsHookExW          *syscall.LazyProc
+	procUnhookWindowsHookEx        *syscall.LazyProc
+	procCallNextHookEx             *syscall.LazyProc
+	procWindowFromPoint            *syscall.LazyProc
+	procCreateSolidBrush           *syscall.LazyProc
+	procCreateRectRgn              *syscall.LazyProc
+	procCombineRgn                 *syscall.LazyProc
+	procGetModuleHandleW           *syscall.LazyProc
+
+	procIsIconic *syscall.LazyProc
+
+	procNtSuspendProcess       *syscall.LazyProc
+	procNtResumeProcess        *syscall.LazyProc
+	procGetPriorityClass       *syscall.LazyProc
+	procSetPriorityClass       *syscall.LazyProc
+	procGetProcessAffinityMask *syscall.LazyProc
+	procSetProcessAffinityMask *syscall.LazyProc
+	procPostMessageW           *syscall.LazyProc
+
+	procK32GetProcessMemoryInfo *syscall.LazyProc
+	procGetProcessHandleCount   *syscall.LazyProc
+	procGetGuiResources         *syscall.LazyProc
+
+	procShellExecuteExW *syscall.LazyProc
+	procGetProcessId    *syscall.LazyProc
+
+	procSetForegroundWindow *syscall.LazyProc
+	procFlashWindowEx       *syscall.LazyProc
+
+	procGetForegroundWindow *syscall.LazyProc
+	procGetCursorPos        *syscall.LazyProc
+	procGetAsyncKeyState    *syscall.LazyProc
+	procAttachThreadInput   *syscall.LazyProc
+
+	procGetGUIThreadInfo *syscall.LazyProc
+
+	procKeybdEvent *syscall.LazyProc
+	procMouseEvent *syscall.LazyProc
+	procIsZoomed   *syscall.LazyProc
+
+	procBeginDeferWindowPos *syscall.LazyProc
+	procDeferWindowPos      *syscall.LazyProc
+	procEndDeferWindowPos   *syscall.LazyProc
+
+	procIsWindow *syscall.LazyProc
+
+	procAttachConsole *syscall.LazyProc
+
+	// monitorMu guards monitorHandles while EnumDisplayMonitors runs.
+	// The callback is created once because Go limits the number of callbacks.
+	monitorMu       sync.Mutex
+	monitorOnce     sync.Once
+	monitorCallback uintptr
+	monitorHandles  []syscall.Handle
+
+	// windowMu guards windowHandles while EnumWindows runs
+	windowMu       sync.Mutex
+	windowOnce     sync.Once
+	windowCallback uintptr
+	windowHandles  []syscall.Handle
+
+	// childMu guards childHandles while EnumChildWindows runs
+	childMu       sync.Mutex
+	childOnce     sync.Once
+	childCallback uintptr
+	childHandles  []syscall.Handle
 }
 
 // NewAPI creates a new Windows API wrapper
 func NewAPI() *API {
 	user32 := syscall.NewLazyDLL("user32.dll")
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	ntdll := syscall.NewLazyDLL("ntdll.dll")
+	gdi32 := syscall.NewLazyDLL("gdi32.dll")
+	shell32 := syscall.NewLazyDLL("shell32.dll")
+	dwmapi := syscall.NewLazyDLL("dwmapi.dll")
+	ole32 := syscall.NewLazyDLL("ole32.dll")
 	return &API{
 		user32:                       user32,
+		kernel32:                     kernel32,
+		ntdll:                        ntdll,
+		gdi32:                        gdi32,
+		shell32:                      shell32,
+		dwmapi:                       dwmapi,
+		ole32:                        ole32,
 		procFindWindow:               user32.NewProc("FindWindowW"),
 		procSetWindowPos:             user32.NewProc("SetWindowPos"),
 		procGetWindowRect:            user32.NewProc("GetWindowRect"),
@@ -31,16 +168,125 @@ func NewAPI() *API {
 		procGetWindowThreadProcessId: user32.NewProc("GetWindowThreadProcessId"),
 		procIsWindowVisible:          user32.NewProc("IsWindowVisible"),
 		procGetWindowTextW:           user32.NewProc("GetWindowTextW"),
+		procGetClassNameW:            user32.NewProc("GetClassNameW"),
+		procGetWindow:                user32.NewProc("GetWindow"),
+		procGetWindowLongW:           user32.NewProc("GetWindowLongW"),
+		procDwmGetWindowAttribute:    dwmapi.NewProc("DwmGetWindowAttribute"),
+		procEnumChildWindows:         user32.NewProc("EnumChildWindows"),
+		procMonitorFromWindow:        user32.NewProc("MonitorFromWindow"),
+		procGetMonitorInfoW:          user32.NewProc("GetMonitorInfoW"),
+		procEnumDisplayMonitors:      user32.NewProc("EnumDisplayMonitors"),
+		procGetIconInfo:              user32.NewProc("GetIconInfo"),
+		procDestroyIcon:              user32.NewProc("DestroyIcon"),
+
+		procQueryFullProcessImageNameW: kernel32.NewProc("QueryFullProcessImageNameW"),
+		procIsWow64Process2:            kernel32.NewProc("IsWow64Process2"),
+		procNtQueryInformationProcess:  ntdll.NewProc("NtQueryInformationProcess"),
+		procExtractIconExW:             shell32.NewProc("ExtractIconExW"),
+		procGetObjectW:                 gdi32.NewProc("GetObjectW"),
+		procCreateCompatibleDC:         gdi32.NewProc("CreateCompatibleDC"),
+		procGetDIBits:                  gdi32.NewProc("GetDIBits"),
+		procDeleteObject:               gdi32.NewProc("DeleteObject"),
+		procDeleteDC:                   gdi32.NewProc("DeleteDC"),
+
+		procGetApplicationUserModelId:   kernel32.NewProc("GetApplicationUserModelId"),
+		procSHGetPropertyStoreForWindow: shell32.NewProc("SHGetPropertyStoreForWindow"),
+		procPropVariantClear:            ole32.NewProc("PropVariantClear"),
+		procCoInitializeEx:              ole32.NewProc("CoInitializeEx"),
+		procCoUninitialize:              ole32.NewProc("CoUninitialize"),
+		procCoCreateInstance:            ole32.NewProc("CoCreateInstance"),
+		procProcessIdToSessionId:        kernel32.NewProc("ProcessIdToSessionId"),
+
+		procGetDC:                  user32.NewProc("GetDC"),
+		procReleaseDC:              user32.NewProc("ReleaseDC"),
+		procGetWindowDC:            user32.NewProc("GetWindowDC"),
+		procPrintWindow:            user32.NewProc("PrintWindow"),
+		procCreateCompatibleBitmap: gdi32.NewProc("CreateCompatibleBitmap"),
+		procSelectObject:           gdi32.NewProc("SelectObject"),
+		procBitBlt:                 gdi32.NewProc("BitBlt"),
+		procSetWinEventHook:        user32.NewProc("SetWinEventHook"),
+		procUnhookWinEvent:         user32.NewProc("UnhookWinEvent"),
+		procGetMessageW:            user32.NewProc("GetMessageW"),
+		procPostThreadMessageW:     user32.NewProc("PostThreadMessageW"),
+		procGetCurrentThreadId:     kernel32.NewProc("GetCurrentThreadId"),
+		procGetAncestor:            user32.NewProc("GetAncestor"),
+
+		procRegisterClassExW:           user32.NewProc("RegisterClassExW"),
+		procCreateWindowExW:            user32.NewProc("CreateWindowExW"),
+		procDefWindowProcW:             user32.NewProc("DefWindowProcW"),
+		procDestroyWindow:              user32.NewProc("DestroyWindow"),
+		procShowWindow:                 user32.NewProc("ShowWindow"),
+		procSetLayeredWindowAttributes: user32.NewProc("SetLayeredWindowAttributes"),
+		procSetWindowRgn:               user32.NewProc("SetWindowRgn"),
+		procTranslateMessage:           user32.NewProc("TranslateMessage"),
+		procDispatchMessageW:           user32.NewProc("DispatchMessageW"),
+		procSetWindowsHookExW:          user32.NewProc("SetWindowsHookExW"),
+		procUnhookWindowsHookEx:        user32.NewProc("UnhookWindowsHookEx"),
+		procCallNextHookEx:             user32.NewProc("CallNextHookEx"),
+		procWindowFromPoint:            user32.NewProc("WindowFromPoint"),
+		procCreateSolidBrush:           gdi32.NewProc("CreateSolidBrush"),
+		procCreateRectRgn:              gdi32.NewProc("CreateRectRgn"),
+		procCombineRgn:                 gdi32.NewProc("CombineRgn"),
+		procGetModuleHandleW:           kernel32.NewProc("GetModuleHandleW"),
+
+		procIsIconic: user32.NewProc("IsIconic"),
+
+		procNtSuspendProcess:       ntdll.NewProc("NtSuspendProcess"),
+		procNtResumeProcess:        ntdll.NewProc("NtResumeProcess"),
+		procGetPriorityClass:       kernel32.NewProc("GetPriorityClass"),
+		procSetPriorityClass:       kernel32.NewProc("SetPriorityClass"),
+		procGetProcessAffinityMask: kernel32.NewProc("GetProcessAffinityMask"),
+		procSetProcessAffinityMask: kernel32.NewProc("SetProcessAffinityMask"),
+		procPostMessageW:           user32.NewProc("PostMessageW"),
+
+		procK32GetProcessMemoryInfo: kernel32.NewProc("K32GetProcessMemoryInfo"),
+		procGetProcessHandleCount:   kernel32.NewProc("GetProcessHandleCount"),
+		procGetGuiResources:         user32.NewProc("GetGuiResources"),
+
+		procShellExecuteExW: shell32.NewProc("ShellExecuteExW"),
+		procGetProcessId:    kernel32.NewProc("GetProcessId"),
+
+		procSetForegroundWindow: user32.NewProc("SetForegroundWindow"),
+		procFlashWindowEx:       user32.NewProc("FlashWindowEx"),
+
+		procGetForegroundWindow: user32.NewProc("GetForegroundWindow"),
+		procGetCursorPos:        user32.NewProc("GetCursorPos"),
+		procGetAsyncKeyState:    user32.NewProc("GetAsyncKeyState"),
+		procAttachThreadInput:   user32.NewProc("AttachThreadInput"),
+
+		procGetGUIThreadInfo: user32.NewProc("GetGUIThreadInfo"),
+
+		procKeybdEvent: user32.NewProc("keybd_event"),
+		procMouseEvent: user32.NewProc("mouse_event"),
+		procIsZoomed:   user32.NewProc("IsZoomed"),
+
+		procBeginDeferWindowPos: user32.NewProc("BeginDeferWindowPos"),
+		procDeferWindowPos:      user32.NewProc("DeferWindowPos"),
+		procEndDeferWindowPos:   user32.NewProc("EndDeferWindowPos"),
+
+		procIsWindow: user32.NewProc("IsWindow"),
+
+		procAttachConsole: kernel32.NewProc("AttachConsole"),
 	}
 }
 
-// EnumWindows enumerates all windows with the provided callback
-func (api *API) EnumWindows(callback uintptr) error {
-	ret, _, _ := api.procEnumWindows.Call(callback, 0)
+// EnumWindows returns all top-level windows in z-order, topmost first
+func (api *API) EnumWindows() ([]syscall.Handle, error) {
+	api.windowOnce.Do(func() {
+		api.windowCallback = syscall.NewCallback(func(hwnd syscall.Handle, lParam uintptr) uintptr {
+			api.windowHandles = append(api.windowHandles, hwnd)
+			return 1 // Continue enumeration
+		})
+	})
+
+	api.windowMu.Lock()
+	defer api.windowMu.Unlock()
+	api.windowHandles = nil
+	ret, _, _ := api.procEnumWindows.Call(api.windowCallback, 0)
 	if ret == 0 {
-		return syscall.GetLastError()
+		return nil, syscall.GetLastError()
 	}
-	return nil
+	return api.windowHandles, nil
 }
 
 // IsWindowVisible checks if a window is visible
@@ -49,6 +295,18 @@ func (api *API) IsWindowVisible(hwnd syscall.Handle) bool {
 	return ret != 0
 }
 
+// IsWindow checks if a handle identifies an existing window
+func (api *API) IsWindow(hwnd syscall.Handle) bool {
+	ret, _, _ := api.procIsWindow.Call(uintptr(hwnd))
+	return ret != 0
+}
+
+// IsIconic checks if a window is minimized
+func (api *API) IsIconic(hwnd syscall.Handle) bool {
+	ret, _, _ := api.procIsIconic.Call(uintptr(hwnd))
+	return ret != 0
+}
+
 // GetWindowThreadProcessId gets the process ID for a window
 func (api *API) GetWindowThreadProcessId(hwnd syscall.Handle) uint32 {
 	var pid uint32
@@ -67,6 +325,55 @@ func (api *API) GetWindowText(hwnd syscall.Handle) string {
 	return ""
 }
 
+// GetClassName gets the window class name
+func (api *API) GetClassName(hwnd syscall.Handle) string {
+	const maxLength = 256
+	buf := make([]uint16, maxLength)
+	ret, _, _ := api.procGetClassNameW.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&buf[0])), maxLength)
+	if ret > 0 {
+		return syscall.UTF16ToString(buf[:ret])
+	}
+	return ""
+}
+
+// EnumChildWindows returns all child windows of a window
+func (api *API) EnumChildWindows(hwnd syscall.Handle) []syscall.Handle {
+	api.childOnce.Do(func() {
+		api.childCallback = syscall.NewCallback(func(child syscall.Handle, lParam uintptr) uintptr {
+			api.childHandles = append(api.childHandles, child)
+			return 1 // Continue enumeration
+		})
+	})
+
+	api.childMu.Lock()
+	defer api.childMu.Unlock()
+	api.childHandles = nil
+	api.procEnumChildWindows.Call(uintptr(hwnd), api.childCallback, 0)
+	return api.childHandles
+}
+
+// GetOwner gets the owner window, or 0 for unowned windows
+func (api *API) GetOwner(hwnd syscall.Handle) syscall.Handle {
+	ret, _, _ := api.procGetWindow.Call(uintptr(hwnd), GW_OWNER)
+	return syscall.Handle(ret)
+}
+
+// GetWindowStyle gets the window style (GWL_STYLE)
+func (api *API) GetWindowStyle(hwnd syscall.Handle) uint32 {
+	return api.getWindowLong(hwnd, GWL_STYLE)
+}
+
+// GetWindowExStyle gets the extended window style (GWL_EXSTYLE)
+func (api *API) GetWindowExStyle(hwnd syscall.Handle) uint32 {
+	return api.getWindowLong(hwnd, GWL_EXSTYLE)
+}
+
+// getWindowLong reads a 32-bit window value; index is sign-extended as Windows expects
+func (api *API) getWindowLong(hwnd syscall.Handle, index int32) uint32 {
+	ret, _, _ := api.procGetWindowLongW.Call(uintptr(hwnd), uintptr(index))
+	return uint32(ret)
+}
+
 // SetWindowPos sets the window position and size
 func (api *API) SetWindowPos(hwnd syscall.Handle, x, y, width, height int, flags uint32) error {
 	ret, _, _ := api.procSetWindowPos.Call(
@@ -100,3 +407,20 @@ const (
 	SWP_NOZORDER   = 0x0004
 	SWP_NOACTIVATE = 0x0010
 )
+
+// Window relationship, style and attribute constants
+const (
+	GW_OWNER = 4
+
+	GWL_STYLE   = -16
+	GWL_EXSTYLE = -20
+
+	WS_POPUP   = 0x80000000
+	WS_CAPTION = 0x00C00000
+
+	WS_EX_TOOLWINDOW = 0x00000080
+	WS_EX_APPWINDOW  = 0x00040000
+	WS_EX_NOACTIVATE = 0x08000000
+
+	DWMWA_CLOAKED = 14
+)
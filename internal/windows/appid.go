@@ -0,0 +1,116 @@
+package windows
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	VT_LPWSTR = 31
+
+	// applicationUserModelIDMaxLength includes the terminating null
+	applicationUserModelIDMaxLength = 130
+	appmodelErrorNoApplication      = 15703
+)
+
+var iidIPropertyStore = syscall.GUID{
+	Data1: 0x886d8eeb, Data2: 0x8cf2, Data3: 0x4446,
+	Data4: [8]byte{0x8d, 0x02, 0xcd, 0xba, 0x1d, 0xbd, 0xcf, 0x99},
+}
+
+// propertyKey mirrors the Win32 PROPERTYKEY structure
+type propertyKey struct {
+	fmtid syscall.GUID
+	pid   uint32
+}
+
+// pkeyAppUserModelID is PKEY_AppUserModel_ID
+var pkeyAppUserModelID = propertyKey{
+	fmtid: syscall.GUID{
+		Data1: 0x9f4c2855, Data2: 0x9f79, Data3: 0x4b39,
+		Data4: [8]byte{0xa8, 0xd0, 0xe1, 0xd4, 0x2d, 0xe1, 0xd5, 0xf3},
+	},
+	pid: 5,
+}
+
+// propVariant mirrors the Win32 PROPVARIANT structure for string values
+type propVariant struct {
+	vt       uint16
+	reserved [3]uint16
+	val      *uint16
+	_        uintptr
+}
+
+// propertyStore is the COM IPropertyStore interface
+type propertyStore struct {
+	vtbl *propertyStoreVtbl
+}
+
+type propertyStoreVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+	GetCount       uintptr
+	GetAt          uintptr
+	GetValue       uintptr
+	SetValue       uintptr
+	Commit         uintptr
+}
+
+// GetWindowAppUserModelID gets the AppUserModelID assigned to a window, if any.
+// Browsers use it to tell apart windows of different profiles.
+func (api *API) GetWindowAppUserModelID(hwnd syscall.Handle) (string, error) {
+	var appID string
+	err := api.withCOM(func() error {
+		var err error
+		appID, err = api.windowAppUserModelID(hwnd)
+		return err
+	})
+	return appID, err
+}
+
+// windowAppUserModelID reads PKEY_AppUserModel_ID; COM must be initialized
+func (api *API) windowAppUserModelID(hwnd syscall.Handle) (string, error) {
+	var store *propertyStore
+	hr, _, _ := api.procSHGetPropertyStoreForWindow.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&iidIPropertyStore)), uintptr(unsafe.Pointer(&store)))
+	if int32(hr) < 0 || store == nil {
+		return "", hresultError("SHGetPropertyStoreForWindow", hr)
+	}
+	defer syscall.SyscallN(store.vtbl.Release, uintptr(unsafe.Pointer(store)))
+
+	var value propVariant
+	hr, _, _ = syscall.SyscallN(store.vtbl.GetValue, uintptr(unsafe.Pointer(store)), uintptr(unsafe.Pointer(&pkeyAppUserModelID)), uintptr(unsafe.Pointer(&value)))
+	if int32(hr) < 0 {
+		return "", hresultError("IPropertyStore.GetValue", hr)
+	}
+	defer api.procPropVariantClear.Call(uintptr(unsafe.Pointer(&value)))
+
+	if value.vt != VT_LPWSTR || value.val == nil {
+		return "", nil
+	}
+	return utf16PtrToString(value.val), nil
+}
+
+// GetProcessAppUserModelID gets the AppUserModelID of a packaged (UWP/MSIX) process.
+// It returns an empty string for unpackaged processes.
+func (api *API) GetProcessAppUserModelID(h syscall.Handle) (string, error) {
+	buf := make([]uint16, applicationUserModelIDMaxLength)
+	size := uint32(len(buf))
+	ret, _, _ := api.procGetApplicationUserModelId.Call(uintptr(h), uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&buf[0])))
+	switch ret {
+	case 0:
+		return syscall.UTF16ToString(buf), nil
+	case appmodelErrorNoApplication:
+		return "", nil
+	}
+	return "", syscall.Errno(ret)
+}
+
+// utf16PtrToString converts a null-terminated UTF-16 string owned by Windows
+func utf16PtrToString(p *uint16) string {
+	n := 0
+	for ptr := unsafe.Pointer(p); *(*uint16)(ptr) != 0; n++ {
+		ptr = unsafe.Add(ptr, 2)
+	}
+	return syscall.UTF16ToString(unsafe.Slice(p, n))
+}
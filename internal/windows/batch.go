@@ -0,0 +1,44 @@
+package windows
+
+import "syscall"
+
+// WindowMove is one window of a batched move
+type WindowMove struct {
+	Hwnd   syscall.Handle
+	X, Y   int
+	Width  int
+	Height int
+}
+
+// MoveWindows moves and resizes several windows in one step, so they are
+// redrawn together instead of one after another
+func (api *API) MoveWindows(moves []WindowMove) error {
+	if len(moves) == 0 {
+		return nil
+	}
+
+	hdwp, _, _ := api.procBeginDeferWindowPos.Call(uintptr(len(moves)))
+	if hdwp == 0 {
+		return syscall.GetLastError()
+	}
+	for _, m := range moves {
+		// DeferWindowPos frees the batch itself when it fails
+		hdwp, _, _ = api.procDeferWindowPos.Call(hdwp, uintptr(m.Hwnd), 0,
+			uintptr(m.X), uintptr(m.Y), uintptr(m.Width), uintptr(m.Height),
+			SWP_NOZORDER|SWP_NOACTIVATE)
+		if hdwp == 0 {
+			return syscall.GetLastError()
+		}
+	}
+
+	ret, _, _ := api.procEndDeferWindowPos.Call(hdwp)
+	if ret == 0 {
+		return syscall.GetLastError()
+	}
+	return nil
+}
+
+// RestoreWindow restores a minimized or maximized window to its normal state
+func (api *API) RestoreWindow(hwnd syscall.Handle) {
+	api.procShowWindow.Call(uintptr(hwnd), SW_RESTORE)
+}
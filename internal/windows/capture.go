@@ -0,0 +1,62 @@
+package windows
+
+import (
+	"errors"
+	"image"
+	"syscall"
+)
+
+const (
+	// PW_RENDERFULLCONTENT lets PrintWindow capture DirectComposition content (Windows 8.1+)
+	PW_RENDERFULLCONTENT = 0x00000002
+	SRCCOPY              = 0x00CC0020
+)
+
+// CaptureWindow captures the contents of a window, including its frame.
+// PrintWindow is tried first so covered windows are captured correctly;
+// BitBlt from the window DC is the fallback for windows that ignore it.
+func (api *API) CaptureWindow(hwnd syscall.Handle) (*image.NRGBA, error) {
+	rect, err := api.GetWindowRect(hwnd)
+	if err != nil {
+		return nil, err
+	}
+	width, height := int(rect.Right-rect.Left), int(rect.Bottom-rect.Top)
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("window has no area")
+	}
+
+	screenDC, _, _ := api.procGetDC.Call(0)
+	if screenDC == 0 {
+		return nil, errors.New("GetDC failed")
+	}
+	defer api.procReleaseDC.Call(0, screenDC)
+
+	memDC, _, _ := api.procCreateCompatibleDC.Call(screenDC)
+	if memDC == 0 {
+		return nil, errors.New("CreateCompatibleDC failed")
+	}
+	defer api.procDeleteDC.Call(memDC)
+
+	hbm, _, _ := api.procCreateCompatibleBitmap.Call(screenDC, uintptr(width), uintptr(height))
+	if hbm == 0 {
+		return nil, errors.New("CreateCompatibleBitmap failed")
+	}
+	defer api.procDeleteObject.Call(hbm)
+
+	old, _, _ := api.procSelectObject.Call(memDC, hbm)
+	ok, _, _ := api.procPrintWindow.Call(uintptr(hwnd), memDC, PW_RENDERFULLCONTENT)
+	if ok == 0 {
+		windowDC, _, _ := api.procGetWindowDC.Call(uintptr(hwnd))
+		if windowDC != 0 {
+			ok, _, _ = api.procBitBlt.Call(memDC, 0, 0, uintptr(width), uintptr(height), windowDC, 0, 0, SRCCOPY)
+			api.procReleaseDC.Call(uintptr(hwnd), windowDC)
+		}
+	}
+	// GetDIBits requires the bitmap to be deselected from any DC
+	api.procSelectObject.Call(memDC, old)
+	if ok == 0 {
+		return nil, errors.New("capturing window failed")
+	}
+
+	return api.bitmapToImage(syscall.Handle(hbm), true)
+}
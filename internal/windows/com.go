@@ -0,0 +1,57 @@
+package windows
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// COM constants
+const (
+	COINIT_MULTITHREADED = 0x0
+	CLSCTX_ALL           = 0x17
+
+	E_ACCESSDENIED = 0x80070005
+)
+
+// iUnknownVtbl is the vtable prefix shared by all COM interfaces
+type iUnknownVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+}
+
+// withCOM runs fn on a locked OS thread with COM initialized.
+// COM state is per thread, so the goroutine must not migrate while fn runs.
+func (api *API) withCOM(fn func() error) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	hr, _, _ := api.procCoInitializeEx.Call(0, COINIT_MULTITHREADED)
+	// S_OK and S_FALSE must be balanced; RPC_E_CHANGED_MODE means COM is already usable
+	if int32(hr) >= 0 {
+		defer api.procCoUninitialize.Call()
+	}
+
+	return fn()
+}
+
+// coCreateInstance creates a COM object and returns the requested interface pointer
+func (api *API) coCreateInstance(clsid, iid *syscall.GUID) (unsafe.Pointer, error) {
+	var obj unsafe.Pointer
+	hr, _, _ := api.procCoCreateInstance.Call(uintptr(unsafe.Pointer(clsid)), 0, CLSCTX_ALL, uintptr(unsafe.Pointer(iid)), uintptr(unsafe.Pointer(&obj)))
+	if int32(hr) < 0 {
+		return nil, hresultError("CoCreateInstance", hr)
+	}
+	return obj, nil
+}
+
+// hresultError converts a failed HRESULT to an error. E_ACCESSDENIED wraps
+// syscall.ERROR_ACCESS_DENIED so callers can detect it with errors.Is.
+func hresultError(op string, hr uintptr) error {
+	if uint32(hr) == E_ACCESSDENIED {
+		return fmt.Errorf("%s: %w", op, syscall.ERROR_ACCESS_DENIED)
+	}
+	return fmt.Errorf("%s: HRESULT 0x%08x", op, uint32(hr))
+}
@@ -0,0 +1,42 @@
+package windows
+
+import (
+	"os"
+	"syscall"
+)
+
+// ATTACH_PARENT_PROCESS attaches to the console of the parent process
+const ATTACH_PARENT_PROCESS = 0xFFFFFFFF
+
+// AttachParentConsole points stdout and stderr at the console of the parent
+// process. GUI subsystem builds start without one, so output written from a
+// command prompt would otherwise be lost. Streams the parent redirected are
+// left alone.
+func (api *API) AttachParentConsole() error {
+	stdout, stderr := hasStdHandle(syscall.Stdout), hasStdHandle(syscall.Stderr)
+	if stdout && stderr {
+		return nil
+	}
+
+	ret, _, err := api.procAttachConsole.Call(ATTACH_PARENT_PROCESS)
+	if ret == 0 {
+		return err
+	}
+
+	conout, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if !stdout {
+		os.Stdout = conout
+	}
+	if !stderr {
+		os.Stderr = conout
+	}
+	return nil
+}
+
+// hasStdHandle reports whether a standard handle refers to something
+func hasStdHandle(h syscall.Handle) bool {
+	return h != 0 && h != syscall.InvalidHandle
+}
@@ -0,0 +1,132 @@
+package windows
+
+import (
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Display change notification constants
+const (
+	WM_DISPLAYCHANGE     = 0x007E
+	WM_SETTINGCHANGE     = 0x001A
+	WM_DEVICECHANGE      = 0x0219
+	DBT_DEVNODES_CHANGED = 0x0007
+	SPI_SETWORKAREA      = 0x002F
+)
+
+// displayWatchers routes window procedure calls to the handler of the hidden
+// window they belong to. The procedure is shared because Go limits the
+// number of callbacks.
+var displayWatchers = struct {
+	sync.Mutex
+	once      sync.Once
+	className *uint16
+	err       error
+	handlers  map[uintptr]func()
+}{handlers: make(map[uintptr]func())}
+
+// registerDisplayClass registers the class of the hidden notification windows
+func (api *API) registerDisplayClass() error {
+	displayWatchers.once.Do(func() {
+		wndProc := syscall.NewCallback(func(hwnd, message, wParam, lParam uintptr) uintptr {
+			if isDisplayChange(uint32(message), wParam) {
+				displayWatchers.Lock()
+				fn := displayWatchers.handlers[hwnd]
+				displayWatchers.Unlock()
+				if fn != nil {
+					fn()
+				}
+			}
+			ret, _, _ := api.procDefWindowProcW.Call(hwnd, message, wParam, lParam)
+			return ret
+		})
+
+		name, _ := syscall.UTF16PtrFromString("hptoolsDisplayWatcher")
+		instance, _, _ := api.procGetModuleHandleW.Call(0)
+		wc := wndClassEx{wndProc: wndProc, instance: instance, className: name}
+		wc.size = uint32(unsafe.Sizeof(wc))
+
+		if ret, _, err := api.procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); ret == 0 {
+			displayWatchers.err = err
+			return
+		}
+		displayWatchers.className = name
+	})
+	return displayWatchers.err
+}
+
+// isDisplayChange reports whether a message signals a change of the monitor
+// topology: resolution or arrangement, attached devices or the work area
+func isDisplayChange(message uint32, wParam uintptr) bool {
+	switch message {
+	case WM_DISPLAYCHANGE:
+		return true
+	case WM_DEVICECHANGE:
+		return wParam == DBT_DEVNODES_CHANGED
+	case WM_SETTINGCHANGE:
+		return wParam == SPI_SETWORKAREA
+	}
+	return false
+}
+
+// WatchDisplayChanges calls handler whenever the monitor topology may have
+// changed. The notifications are broadcast to top-level windows only, so a
+// hidden window with its own message loop thread receives them. handler runs
+// on that thread, is called several times per change and must return quickly.
+// The returned function stops watching.
+func (api *API) WatchDisplayChanges(handler func()) (func(), error) {
+	if err := api.registerDisplayClass(); err != nil {
+		return nil, err
+	}
+
+	started := make(chan error, 1)
+	done := make(chan struct{})
+	var threadID uintptr
+
+	go func() {
+		defer close(done)
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		threadID, _, _ = api.procGetCurrentThreadId.Call()
+
+		// Hold the lock until the handler is registered so no message is missed
+		displayWatchers.Lock()
+		hwnd, _, err := api.procCreateWindowExW.Call(
+			WS_EX_TOOLWINDOW, uintptr(unsafe.Pointer(displayWatchers.className)), 0, WS_POPUP,
+			0, 0, 0, 0, 0, 0, 0, 0,
+		)
+		if hwnd == 0 {
+			displayWatchers.Unlock()
+			started <- err
+			return
+		}
+		displayWatchers.handlers[hwnd] = handler
+		displayWatchers.Unlock()
+
+		defer func() {
+			displayWatchers.Lock()
+			delete(displayWatchers.handlers, hwnd)
+			displayWatchers.Unlock()
+			api.procDestroyWindow.Call(hwnd)
+		}()
+
+		started <- nil
+		api.pumpMessages()
+	}()
+
+	if err := <-started; err != nil {
+		<-done
+		return nil, err
+	}
+
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			api.procPostThreadMessageW.Call(threadID, WM_QUIT, 0, 0)
+			<-done
+		})
+	}, nil
+}
@@ -0,0 +1,155 @@
+package windows
+
+import (
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"hptools/internal/models"
+)
+
+const (
+	SW_RESTORE = 9
+
+	GW_HWNDPREV = 3
+
+	SWP_NOSIZE = 0x0001
+
+	VK_LBUTTON = 0x01
+	VK_RBUTTON = 0x02
+	VK_MBUTTON = 0x04
+
+	FLASHW_ALL       = 0x00000003
+	FLASHW_TIMERNOFG = 0x0000000C
+)
+
+// HWND_TOP places a window at the top of the z-order
+const HWND_TOP = syscall.Handle(0)
+
+// flashWInfo mirrors FLASHWINFO
+type flashWInfo struct {
+	cbSize    uint32
+	hwnd      syscall.Handle
+	dwFlags   uint32
+	uCount    uint32
+	dwTimeout uint32
+}
+
+// FocusWindow restores a minimized window and brings it to the foreground.
+// The calling thread is attached to the input of the current foreground
+// window so that Windows allows the change from a background process;
+// Windows may still refuse it, and the window then flashes in the taskbar.
+func (api *API) FocusWindow(hwnd syscall.Handle) error {
+	if api.IsIconic(hwnd) {
+		api.procShowWindow.Call(uintptr(hwnd), SW_RESTORE)
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	current, _, _ := api.procGetCurrentThreadId.Call()
+	if fg := api.GetForegroundWindow(); fg != 0 {
+		fgThread, _, _ := api.procGetWindowThreadProcessId.Call(uintptr(fg), 0)
+		if fgThread != 0 && fgThread != current {
+			if ret, _, _ := api.procAttachThreadInput.Call(current, fgThread, 1); ret != 0 {
+				defer api.procAttachThreadInput.Call(current, fgThread, 0)
+			}
+		}
+	}
+
+	ret, _, _ := api.procSetForegroundWindow.Call(uintptr(hwnd))
+	if ret == 0 {
+		return syscall.GetLastError()
+	}
+	return nil
+}
+
+// FlashWindow flashes the caption and taskbar button of a window count
+// times, or until it comes to the foreground if count is 0
+func (api *API) FlashWindow(hwnd syscall.Handle, count uint32) {
+	info := flashWInfo{
+		hwnd:    hwnd,
+		dwFlags: FLASHW_ALL,
+		uCount:  count,
+	}
+	if count == 0 {
+		info.dwFlags |= FLASHW_TIMERNOFG
+	}
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	api.procFlashWindowEx.Call(uintptr(unsafe.Pointer(&info)))
+}
+
+// GetForegroundWindow returns the window the user is currently working with
+func (api *API) GetForegroundWindow() syscall.Handle {
+	ret, _, _ := api.procGetForegroundWindow.Call()
+	return syscall.Handle(ret)
+}
+
+// GetCursorPos returns the cursor position in screen coordinates
+func (api *API) GetCursorPos() (x, y int32, err error) {
+	var pt [2]int32 // POINT
+	ret, _, _ := api.procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+	if ret == 0 {
+		return 0, 0, syscall.GetLastError()
+	}
+	return pt[0], pt[1], nil
+}
+
+// IsMouseButtonDown reports whether the left, right or middle button is held
+func (api *API) IsMouseButtonDown() bool {
+	for _, vk := range []uintptr{VK_LBUTTON, VK_RBUTTON, VK_MBUTTON} {
+		if state, _, _ := api.procGetAsyncKeyState.Call(vk); state&0x8000 != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// GetWindowAbove returns the window directly above hwnd in the z-order, or 0
+// if hwnd is at the top
+func (api *API) GetWindowAbove(hwnd syscall.Handle) syscall.Handle {
+	ret, _, _ := api.procGetWindow.Call(uintptr(hwnd), GW_HWNDPREV)
+	return syscall.Handle(ret)
+}
+
+// SetZOrder places hwnd directly below insertAfter, or at the top for
+// HWND_TOP, without moving, resizing or activating it
+func (api *API) SetZOrder(hwnd, insertAfter syscall.Handle) error {
+	ret, _, _ := api.procSetWindowPos.Call(uintptr(hwnd), uintptr(insertAfter), 0, 0, 0, 0, SWP_NOMOVE|SWP_NOSIZE|SWP_NOACTIVATE)
+	if ret == 0 {
+		return syscall.GetLastError()
+	}
+	return nil
+}
+
+// guiThreadInfo mirrors GUITHREADINFO
+type guiThreadInfo struct {
+	cbSize        uint32
+	flags         uint32
+	hwndActive    syscall.Handle
+	hwndFocus     syscall.Handle
+	hwndCapture   syscall.Handle
+	hwndMenuOwner syscall.Handle
+	hwndMoveSize  syscall.Handle
+	hwndCaret     syscall.Handle
+	rcCaret       models.RECT
+}
+
+// GUI thread states that must not be interrupted by a focus change
+const (
+	GUI_INMOVESIZE    = 0x00000002
+	GUI_INMENUMODE    = 0x00000004
+	GUI_POPUPMENUMODE = 0x00000010
+)
+
+// IsForegroundBusy reports whether the foreground thread has a menu open, is
+// moving or sizing a window, or has captured the mouse
+func (api *API) IsForegroundBusy() bool {
+	info := guiThreadInfo{}
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	ret, _, _ := api.procGetGUIThreadInfo.Call(0, uintptr(unsafe.Pointer(&info)))
+	if ret == 0 {
+		return false
+	}
+	return info.flags&(GUI_INMOVESIZE|GUI_INMENUMODE|GUI_POPUPMENUMODE) != 0 || info.hwndCapture != 0
+}
@@ -0,0 +1,127 @@
+package windows
+
+import (
+	"errors"
+	"image"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	BI_RGB         = 0
+	DIB_RGB_COLORS = 0
+)
+
+// iconInfo mirrors the Win32 ICONINFO structure
+type iconInfo struct {
+	fIcon    int32
+	xHotspot uint32
+	yHotspot uint32
+	hbmMask  syscall.Handle
+	hbmColor syscall.Handle
+}
+
+// bitmap mirrors the Win32 BITMAP structure
+type bitmap struct {
+	bmType       int32
+	bmWidth      int32
+	bmHeight     int32
+	bmWidthBytes int32
+	bmPlanes     uint16
+	bmBitsPixel  uint16
+	bmBits       uintptr
+}
+
+// bitmapInfo mirrors BITMAPINFO with a BITMAPINFOHEADER and an unused color table
+type bitmapInfo struct {
+	biSize          uint32
+	biWidth         int32
+	biHeight        int32
+	biPlanes        uint16
+	biBitCount      uint16
+	biCompression   uint32
+	biSizeImage     uint32
+	biXPelsPerMeter int32
+	biYPelsPerMeter int32
+	biClrUsed       uint32
+	biClrImportant  uint32
+	bmiColors       [4]byte
+}
+
+// ExtractIcon extracts the large icon of an executable as an image
+func (api *API) ExtractIcon(path string) (*image.NRGBA, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var hicon syscall.Handle
+	ret, _, _ := api.procExtractIconExW.Call(uintptr(unsafe.Pointer(pathPtr)), 0, uintptr(unsafe.Pointer(&hicon)), 0, 1)
+	if ret == 0 || hicon == 0 {
+		return nil, errors.New("executable has no icon")
+	}
+	defer api.procDestroyIcon.Call(uintptr(hicon))
+
+	var info iconInfo
+	ret, _, _ = api.procGetIconInfo.Call(uintptr(hicon), uintptr(unsafe.Pointer(&info)))
+	if ret == 0 {
+		return nil, syscall.GetLastError()
+	}
+	defer api.procDeleteObject.Call(uintptr(info.hbmMask))
+	if info.hbmColor == 0 {
+		return nil, errors.New("monochrome icons are not supported")
+	}
+	defer api.procDeleteObject.Call(uintptr(info.hbmColor))
+
+	return api.bitmapToImage(info.hbmColor, false)
+}
+
+// bitmapToImage reads the pixels of a bitmap as 32-bit BGRA and converts them to NRGBA.
+// With opaque set the alpha channel is ignored, as for screen captures.
+func (api *API) bitmapToImage(hbm syscall.Handle, opaque bool) (*image.NRGBA, error) {
+	var bm bitmap
+	ret, _, _ := api.procGetObjectW.Call(uintptr(hbm), unsafe.Sizeof(bm), uintptr(unsafe.Pointer(&bm)))
+	if ret == 0 {
+		return nil, errors.New("GetObject failed")
+	}
+	width, height := int(bm.bmWidth), int(bm.bmHeight)
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("empty bitmap")
+	}
+
+	hdc, _, _ := api.procCreateCompatibleDC.Call(0)
+	if hdc == 0 {
+		return nil, errors.New("CreateCompatibleDC failed")
+	}
+	defer api.procDeleteDC.Call(hdc)
+
+	bi := bitmapInfo{
+		biWidth:       int32(width),
+		biHeight:      -int32(height), // negative height requests a top-down DIB
+		biPlanes:      1,
+		biBitCount:    32,
+		biCompression: BI_RGB,
+	}
+	bi.biSize = uint32(unsafe.Offsetof(bi.bmiColors))
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	ret, _, _ = api.procGetDIBits.Call(hdc, uintptr(hbm), 0, uintptr(height), uintptr(unsafe.Pointer(&img.Pix[0])), uintptr(unsafe.Pointer(&bi)), DIB_RGB_COLORS)
+	if ret == 0 {
+		return nil, errors.New("GetDIBits failed")
+	}
+
+	// Swap BGRA to RGBA; legacy icons without alpha are treated as opaque
+	hasAlpha := false
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+2] = img.Pix[i+2], img.Pix[i]
+		if img.Pix[i+3] != 0 {
+			hasAlpha = true
+		}
+	}
+	if opaque || !hasAlpha {
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 0xff
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,84 @@
+package windows
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+// ShellExecuteEx constants
+const (
+	SEE_MASK_NOCLOSEPROCESS = 0x00000040
+	SEE_MASK_NOASYNC        = 0x00000100
+	SEE_MASK_FLAG_NO_UI     = 0x00000400
+	SW_SHOWNORMAL           = 1
+)
+
+// shellExecuteInfo mirrors the Win32 SHELLEXECUTEINFOW structure
+type shellExecuteInfo struct {
+	size       uint32
+	mask       uint32
+	hwnd       uintptr
+	verb       *uint16
+	file       *uint16
+	parameters *uint16
+	directory  *uint16
+	show       int32
+	instApp    uintptr
+	idList     uintptr
+	class      *uint16
+	keyClass   uintptr
+	hotKey     uint32
+	iconOrMon  uintptr
+	process    syscall.Handle
+}
+
+// ShellExecute starts file the way Explorer would, so executables, shortcuts
+// (.lnk) and shell: URIs all work. It returns the PID of the started process,
+// or 0 if the shell did not start one itself, e.g. when the target was
+// handed to an already running application.
+func (api *API) ShellExecute(file, args, dir string) (int, error) {
+	if file == "" {
+		return 0, errors.New("no file to execute")
+	}
+
+	filePtr, err := syscall.UTF16PtrFromString(file)
+	if err != nil {
+		return 0, err
+	}
+	info := shellExecuteInfo{
+		mask: SEE_MASK_NOCLOSEPROCESS | SEE_MASK_NOASYNC | SEE_MASK_FLAG_NO_UI,
+		file: filePtr,
+		show: SW_SHOWNORMAL,
+	}
+	info.size = uint32(unsafe.Sizeof(info))
+	if args != "" {
+		if info.parameters, err = syscall.UTF16PtrFromString(args); err != nil {
+			return 0, err
+		}
+	}
+	if dir != "" {
+		if info.directory, err = syscall.UTF16PtrFromString(dir); err != nil {
+			return 0, err
+		}
+	}
+
+	err = api.withCOM(func() error {
+		ret, _, _ := api.procShellExecuteExW.Call(uintptr(unsafe.Pointer(&info)))
+		if ret == 0 {
+			return syscall.GetLastError()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if info.process == 0 {
+		return 0, nil
+	}
+	defer syscall.CloseHandle(info.process)
+
+	pid, _, _ := api.procGetProcessId.Call(uintptr(info.process))
+	return int(pid), nil
+}
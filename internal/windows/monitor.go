@@ -0,0 +1,76 @@
+package windows
+
+import (
+	"syscall"
+	"unsafe"
+
+	"hptools/internal/models"
+)
+
+// Monitor flags
+const (
+	MONITOR_DEFAULTTONEAREST = 0x00000002
+	MONITORINFOF_PRIMARY     = 0x00000001
+)
+
+// monitorInfoEx mirrors the Win32 MONITORINFOEXW structure
+type monitorInfoEx struct {
+	cbSize    uint32
+	rcMonitor models.RECT
+	rcWork    models.RECT
+	dwFlags   uint32
+	szDevice  [32]uint16
+}
+
+// MonitorFromWindow returns the monitor that contains the largest part of the window
+func (api *API) MonitorFromWindow(hwnd syscall.Handle) syscall.Handle {
+	ret, _, _ := api.procMonitorFromWindow.Call(uintptr(hwnd), MONITOR_DEFAULTTONEAREST)
+	return syscall.Handle(ret)
+}
+
+// GetMonitorInfo gets the bounds and work area of a monitor
+func (api *API) GetMonitorInfo(hmon syscall.Handle) (*models.MonitorInfo, error) {
+	var info monitorInfoEx
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	ret, _, _ := api.procGetMonitorInfoW.Call(uintptr(hmon), uintptr(unsafe.Pointer(&info)))
+	if ret == 0 {
+		return nil, syscall.GetLastError()
+	}
+	return &models.MonitorInfo{
+		Handle:   uintptr(hmon),
+		Name:     syscall.UTF16ToString(info.szDevice[:]),
+		Primary:  info.dwFlags&MONITORINFOF_PRIMARY != 0,
+		Bounds:   info.rcMonitor.ToRect(),
+		WorkArea: info.rcWork.ToRect(),
+	}, nil
+}
+
+// EnumMonitors returns all connected monitors in system enumeration order
+func (api *API) EnumMonitors() ([]models.MonitorInfo, error) {
+	api.monitorOnce.Do(func() {
+		api.monitorCallback = syscall.NewCallback(func(hmon syscall.Handle, hdc syscall.Handle, rect *models.RECT, lParam uintptr) uintptr {
+			api.monitorHandles = append(api.monitorHandles, hmon)
+			return 1 // Continue enumeration
+		})
+	})
+
+	api.monitorMu.Lock()
+	api.monitorHandles = nil
+	ret, _, _ := api.procEnumDisplayMonitors.Call(0, 0, api.monitorCallback, 0)
+	handles := api.monitorHandles
+	api.monitorMu.Unlock()
+
+	if ret == 0 {
+		return nil, syscall.GetLastError()
+	}
+
+	monitors := make([]models.MonitorInfo, 0, len(handles))
+	for _, hmon := range handles {
+		info, err := api.GetMonitorInfo(hmon)
+		if err != nil {
+			return nil, err
+		}
+		monitors = append(monitors, *info)
+	}
+	return monitors, nil
+}
@@ -0,0 +1,149 @@
+package windows
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	WM_MOUSEMOVE = 0x0200
+
+	// LLMHF_INJECTED marks mouse events synthesized by SendInput or mouse_event
+	LLMHF_INJECTED = 0x00000001
+
+	VK_MENU = 0x12
+
+	KEYEVENTF_KEYUP = 0x0002
+
+	MOUSEEVENTF_LEFTDOWN  = 0x0002
+	MOUSEEVENTF_LEFTUP    = 0x0004
+	MOUSEEVENTF_RIGHTDOWN = 0x0008
+	MOUSEEVENTF_RIGHTUP   = 0x0010
+
+	SWP_ASYNCWINDOWPOS = 0x4000
+)
+
+// ErrMouseHookInUse is returned when another mouse watcher is running
+var ErrMouseHookInUse = errors.New("mouse hook already in use")
+
+// MouseEvent is a mouse message seen by the low-level mouse hook
+type MouseEvent struct {
+	Message uint32
+	X, Y    int32
+}
+
+// mouseHook holds the single active mouse watcher. The hook callback is
+// created once because Go limits the number of callbacks.
+var mouseHook struct {
+	sync.Mutex
+	once     sync.Once
+	callback uintptr
+	handler  func(MouseEvent) bool
+}
+
+// WatchMouse installs a low-level mouse hook on a dedicated thread and calls
+// handler for every physical mouse event; injected events are ignored. The
+// event is swallowed when handler returns true. handler runs on the hook
+// thread and must return quickly, or Windows removes the hook. It returns a
+// function that removes the hook again.
+func (api *API) WatchMouse(handler func(MouseEvent) bool) (func(), error) {
+	mouseHook.once.Do(func() {
+		mouseHook.callback = syscall.NewCallback(func(code int32, wParam uintptr, info *msllHookStruct) uintptr {
+			if code >= 0 && info.flags&LLMHF_INJECTED == 0 {
+				mouseHook.Lock()
+				handler := mouseHook.handler
+				mouseHook.Unlock()
+				if handler != nil && handler(MouseEvent{Message: uint32(wParam), X: info.ptX, Y: info.ptY}) {
+					return 1
+				}
+			}
+			ret, _, _ := api.procCallNextHookEx.Call(0, uintptr(code), wParam, uintptr(unsafe.Pointer(info)))
+			return ret
+		})
+	})
+
+	mouseHook.Lock()
+	if mouseHook.handler != nil {
+		mouseHook.Unlock()
+		return nil, ErrMouseHookInUse
+	}
+	mouseHook.handler = handler
+	mouseHook.Unlock()
+
+	release := func() {
+		mouseHook.Lock()
+		mouseHook.handler = nil
+		mouseHook.Unlock()
+	}
+
+	started := make(chan error, 1)
+	done := make(chan struct{})
+	var threadID uintptr
+	go func() {
+		defer close(done)
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		threadID, _, _ = api.procGetCurrentThreadId.Call()
+		instance, _, _ := api.procGetModuleHandleW.Call(0)
+		hook, _, err := api.procSetWindowsHookExW.Call(WH_MOUSE_LL, mouseHook.callback, instance, 0)
+		if hook == 0 {
+			started <- err
+			return
+		}
+		defer api.procUnhookWindowsHookEx.Call(hook)
+
+		started <- nil
+		api.pumpMessages()
+	}()
+
+	if err := <-started; err != nil {
+		<-done
+		release()
+		return nil, err
+	}
+
+	return func() {
+		api.procPostThreadMessageW.Call(threadID, WM_QUIT, 0, 0)
+		<-done
+		release()
+	}, nil
+}
+
+// IsAltDown reports whether either Alt key is held
+func (api *API) IsAltDown() bool {
+	state, _, _ := api.procGetAsyncKeyState.Call(VK_MENU)
+	return state&0x8000 != 0
+}
+
+// SuppressAltMenu taps an unassigned virtual key while Alt is held, so that
+// releasing Alt does not activate the menu bar of the focused window
+func (api *API) SuppressAltMenu() {
+	const vkUnassigned = 0xE8
+	api.procKeybdEvent.Call(vkUnassigned, 0, 0, 0)
+	api.procKeybdEvent.Call(vkUnassigned, 0, KEYEVENTF_KEYUP, 0)
+}
+
+// SendMouseButton injects the button press or release of a WM_LBUTTONDOWN,
+// WM_LBUTTONUP, WM_RBUTTONDOWN or WM_RBUTTONUP message at the cursor
+// position. WatchMouse handlers do not see injected events.
+func (api *API) SendMouseButton(message uint32) {
+	flags := map[uint32]uintptr{
+		WM_LBUTTONDOWN: MOUSEEVENTF_LEFTDOWN,
+		WM_LBUTTONUP:   MOUSEEVENTF_LEFTUP,
+		WM_RBUTTONDOWN: MOUSEEVENTF_RIGHTDOWN,
+		WM_RBUTTONUP:   MOUSEEVENTF_RIGHTUP,
+	}[message]
+	if flags != 0 {
+		api.procMouseEvent.Call(flags, 0, 0, 0, 0)
+	}
+}
+
+// IsZoomed checks if a window is maximized
+func (api *API) IsZoomed(hwnd syscall.Handle) bool {
+	ret, _, _ := api.procIsZoomed.Call(uintptr(hwnd))
+	return ret != 0
+}
@@ -0,0 +1,142 @@
+package windows
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"hptools/internal/models"
+)
+
+// Overlay window constants
+const (
+	WS_EX_LAYERED     = 0x00080000
+	WS_EX_TRANSPARENT = 0x00000020
+	WS_EX_TOPMOST     = 0x00000008
+	LWA_ALPHA         = 0x00000002
+	RGN_DIFF          = 4
+	SW_SHOWNOACTIVATE = 4
+
+	DWMWA_EXTENDED_FRAME_BOUNDS = 9
+
+	// overlayColor is the COLORREF (0x00BBGGRR) of the highlight border
+	overlayColor = 0x000080FF
+	overlayAlpha = 220
+)
+
+// wndClassEx mirrors the Win32 WNDCLASSEXW structure
+type wndClassEx struct {
+	size       uint32
+	style      uint32
+	wndProc    uintptr
+	clsExtra   int32
+	wndExtra   int32
+	instance   uintptr
+	icon       uintptr
+	cursor     uintptr
+	background uintptr
+	menuName   *uint16
+	className  *uint16
+	iconSm     uintptr
+}
+
+var overlayClass struct {
+	once sync.Once
+	name *uint16
+	err  error
+}
+
+// registerOverlayClass registers the window class shared by all overlays.
+// The class brush paints the border, so DefWindowProc is the window procedure.
+func (api *API) registerOverlayClass() error {
+	overlayClass.once.Do(func() {
+		name, _ := syscall.UTF16PtrFromString("hptoolsOverlay")
+		instance, _, _ := api.procGetModuleHandleW.Call(0)
+		brush, _, _ := api.procCreateSolidBrush.Call(overlayColor)
+
+		wc := wndClassEx{
+			wndProc:    api.procDefWindowProcW.Addr(),
+			instance:   instance,
+			background: brush,
+			className:  name,
+		}
+		wc.size = uint32(unsafe.Sizeof(wc))
+
+		if ret, _, err := api.procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); ret == 0 {
+			overlayClass.err = err
+			return
+		}
+		overlayClass.name = name
+	})
+	return overlayClass.err
+}
+
+// GetWindowFrameRect returns the visible frame of a window. GetWindowRect
+// includes the invisible resize borders, so the DWM frame bounds are preferred.
+func (api *API) GetWindowFrameRect(hwnd syscall.Handle) (*models.RECT, error) {
+	var rect models.RECT
+	ret, _, _ := api.procDwmGetWindowAttribute.Call(uintptr(hwnd), DWMWA_EXTENDED_FRAME_BOUNDS, uintptr(unsafe.Pointer(&rect)), unsafe.Sizeof(rect))
+	if ret == 0 {
+		return &rect, nil
+	}
+	return api.GetWindowRect(hwnd)
+}
+
+// ShowFrameOverlay draws a click-through topmost border of the given thickness
+// around rect and removes it again after duration. It returns once the overlay
+// is visible.
+func (api *API) ShowFrameOverlay(rect models.RECT, thickness int, duration time.Duration) error {
+	width, height := int(rect.Right-rect.Left), int(rect.Bottom-rect.Top)
+	if width <= 2*thickness || height <= 2*thickness {
+		return errors.New("window is too small to highlight")
+	}
+	if err := api.registerOverlayClass(); err != nil {
+		return err
+	}
+
+	started := make(chan error, 1)
+
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		x, y := int(rect.Left), int(rect.Top)
+		hwnd, _, err := api.procCreateWindowExW.Call(
+			WS_EX_LAYERED|WS_EX_TRANSPARENT|WS_EX_TOPMOST|WS_EX_TOOLWINDOW|WS_EX_NOACTIVATE,
+			uintptr(unsafe.Pointer(overlayClass.name)), 0, WS_POPUP,
+			uintptr(x), uintptr(y), uintptr(width), uintptr(height),
+			0, 0, 0, 0,
+		)
+		if hwnd == 0 {
+			started <- err
+			return
+		}
+		defer api.procDestroyWindow.Call(hwnd)
+
+		api.procSetLayeredWindowAttributes.Call(hwnd, 0, overlayAlpha, LWA_ALPHA)
+
+		// Cut the interior out so only the border remains. The window owns the
+		// combined region afterwards; the inner one is ours to delete.
+		outer, _, _ := api.procCreateRectRgn.Call(0, 0, uintptr(width), uintptr(height))
+		inner, _, _ := api.procCreateRectRgn.Call(uintptr(thickness), uintptr(thickness), uintptr(width-thickness), uintptr(height-thickness))
+		api.procCombineRgn.Call(outer, outer, inner, RGN_DIFF)
+		api.procDeleteObject.Call(inner)
+		api.procSetWindowRgn.Call(hwnd, outer, 1)
+
+		api.procShowWindow.Call(hwnd, SW_SHOWNOACTIVATE)
+
+		threadID, _, _ := api.procGetCurrentThreadId.Call()
+		timer := time.AfterFunc(duration, func() {
+			api.procPostThreadMessageW.Call(threadID, WM_QUIT, 0, 0)
+		})
+		defer timer.Stop()
+
+		started <- nil
+		api.pumpMessages()
+	}()
+
+	return <-started
+}
@@ -0,0 +1,174 @@
+package windows
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Low-level mouse hook constants
+const (
+	WH_MOUSE_LL    = 14
+	WM_LBUTTONDOWN = 0x0201
+	WM_LBUTTONUP   = 0x0202
+	WM_RBUTTONDOWN = 0x0204
+	WM_RBUTTONUP   = 0x0205
+)
+
+var (
+	// ErrPickCancelled is returned when a window pick is aborted with a right click
+	ErrPickCancelled = errors.New("window pick cancelled")
+	// ErrPickTimeout is returned when no window was clicked in time
+	ErrPickTimeout = errors.New("window pick timed out")
+	// ErrPickInProgress is returned when another window pick is still waiting
+	ErrPickInProgress = errors.New("window pick already in progress")
+)
+
+// msllHookStruct mirrors the Win32 MSLLHOOKSTRUCT structure
+type msllHookStruct struct {
+	ptX, ptY  int32
+	mouseData uint32
+	flags     uint32
+	time      uint32
+	extraInfo uintptr
+}
+
+// pickHook holds the state of the single active window pick. The low-level
+// hook callback is created once because Go limits the number of callbacks.
+var pickHook struct {
+	sync.Mutex
+	once     sync.Once
+	callback uintptr
+	active   bool
+	threadID uintptr
+	button   uintptr // button-up message still to be swallowed
+	clicked  bool
+	ptX, ptY int32
+}
+
+// WindowFromPoint returns the window at a screen position
+func (api *API) WindowFromPoint(x, y int32) syscall.Handle {
+	ret, _, _ := api.procWindowFromPoint.Call(pointArgs(x, y)...)
+	return syscall.Handle(ret)
+}
+
+// PickWindow waits for the next left click anywhere on screen and returns the
+// top-level window under the cursor. The click is swallowed so the picked
+// window does not react to it. A right click cancels the pick.
+func (api *API) PickWindow(timeout time.Duration) (syscall.Handle, error) {
+	pickHook.once.Do(func() {
+		pickHook.callback = syscall.NewCallback(func(code int32, wParam uintptr, info *msllHookStruct) uintptr {
+			if code >= 0 && pickMouseEvent(api, wParam, info) {
+				return 1
+			}
+			ret, _, _ := api.procCallNextHookEx.Call(0, uintptr(code), wParam, uintptr(unsafe.Pointer(info)))
+			return ret
+		})
+	})
+
+	pickHook.Lock()
+	if pickHook.active {
+		pickHook.Unlock()
+		return 0, ErrPickInProgress
+	}
+	pickHook.active = true
+	pickHook.clicked = false
+	pickHook.button = 0
+	pickHook.Unlock()
+
+	defer func() {
+		pickHook.Lock()
+		pickHook.active = false
+		pickHook.Unlock()
+	}()
+
+	started := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		threadID, _, _ := api.procGetCurrentThreadId.Call()
+		instance, _, _ := api.procGetModuleHandleW.Call(0)
+		hook, _, err := api.procSetWindowsHookExW.Call(WH_MOUSE_LL, pickHook.callback, instance, 0)
+		if hook == 0 {
+			started <- err
+			return
+		}
+		defer api.procUnhookWindowsHookEx.Call(hook)
+
+		pickHook.Lock()
+		pickHook.threadID = threadID
+		pickHook.Unlock()
+
+		started <- nil
+		api.pumpMessages()
+	}()
+
+	if err := <-started; err != nil {
+		<-done
+		return 0, err
+	}
+
+	timer := time.AfterFunc(timeout, func() {
+		pickHook.Lock()
+		threadID := pickHook.threadID
+		pickHook.Unlock()
+		api.procPostThreadMessageW.Call(threadID, WM_QUIT, 0, 0)
+	})
+	<-done
+	timer.Stop()
+
+	pickHook.Lock()
+	clicked, button, x, y := pickHook.clicked, pickHook.button, pickHook.ptX, pickHook.ptY
+	pickHook.Unlock()
+
+	switch {
+	case !clicked:
+		return 0, ErrPickTimeout
+	case button == WM_RBUTTONUP:
+		return 0, ErrPickCancelled
+	}
+
+	hwnd := api.WindowFromPoint(x, y)
+	if hwnd == 0 {
+		return 0, errors.New("no window under the cursor")
+	}
+	return api.GetRootWindow(hwnd), nil
+}
+
+// pickMouseEvent records the pick click and reports whether the event should
+// be swallowed. The loop ends once the matching button-up has been swallowed.
+func pickMouseEvent(api *API, message uintptr, info *msllHookStruct) bool {
+	pickHook.Lock()
+	defer pickHook.Unlock()
+
+	if !pickHook.active {
+		return false
+	}
+
+	switch message {
+	case WM_LBUTTONDOWN, WM_RBUTTONDOWN:
+		if pickHook.clicked {
+			return true
+		}
+		pickHook.clicked = true
+		pickHook.ptX, pickHook.ptY = info.ptX, info.ptY
+		pickHook.button = WM_LBUTTONUP
+		if message == WM_RBUTTONDOWN {
+			pickHook.button = WM_RBUTTONUP
+		}
+		return true
+	case WM_LBUTTONUP, WM_RBUTTONUP:
+		if !pickHook.clicked || message != pickHook.button {
+			return false
+		}
+		api.procPostThreadMessageW.Call(pickHook.threadID, WM_QUIT, 0, 0)
+		return true
+	}
+	return false
+}
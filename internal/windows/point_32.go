@@ -0,0 +1,9 @@
+//go:build 386
+
+package windows
+
+// pointArgs passes a POINT by value. On 32-bit stacks the structure takes
+// two argument slots, x first.
+func pointArgs(x, y int32) []uintptr {
+	return []uintptr{uintptr(uint32(x)), uintptr(uint32(y))}
+}
@@ -0,0 +1,9 @@
+//go:build amd64 || arm64
+
+package windows
+
+// pointArgs passes a POINT by value. The structure fits one 64-bit register,
+// so it travels as a single argument with x in the low half.
+func pointArgs(x, y int32) []uintptr {
+	return []uintptr{uintptr(uint32(x)) | uintptr(uint32(y))<<32}
+}
@@ -0,0 +1,251 @@
+package windows
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Process access rights and information classes
+const (
+	PROCESS_QUERY_LIMITED_INFORMATION = 0x1000
+
+	processCommandLineInformation = 60
+	tokenElevation                = 20
+	statusInfoLengthMismatch      = 0xC0000004
+)
+
+// Image file machine types returned by IsWow64Process2
+const (
+	IMAGE_FILE_MACHINE_UNKNOWN = 0x0000
+	IMAGE_FILE_MACHINE_I386    = 0x014c
+	IMAGE_FILE_MACHINE_ARMNT   = 0x01c4
+	IMAGE_FILE_MACHINE_AMD64   = 0x8664
+	IMAGE_FILE_MACHINE_ARM64   = 0xaa64
+)
+
+// unicodeString mirrors the NT UNICODE_STRING structure
+type unicodeString struct {
+	Length        uint16
+	MaximumLength uint16
+	Buffer        *uint16
+}
+
+// OpenProcess opens a process handle with limited query rights.
+// The caller must close the handle with syscall.CloseHandle.
+func (api *API) OpenProcess(pid int) (syscall.Handle, error) {
+	return syscall.OpenProcess(PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+}
+
+// QueryFullProcessImageName gets the full executable path of a process
+func (api *API) QueryFullProcessImageName(h syscall.Handle) (string, error) {
+	buf := make([]uint16, syscall.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	ret, _, _ := api.procQueryFullProcessImageNameW.Call(uintptr(h), 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+	if ret == 0 {
+		return "", syscall.GetLastError()
+	}
+	return syscall.UTF16ToString(buf[:size]), nil
+}
+
+// GetProcessStartTime gets the creation time of a process
+func (api *API) GetProcessStartTime(h syscall.Handle) (time.Time, error) {
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, creation.Nanoseconds()), nil
+}
+
+// GetProcessCommandLine gets the command line a process was started with
+func (api *API) GetProcessCommandLine(h syscall.Handle) (string, error) {
+	var size uint32
+	status, _, _ := api.procNtQueryInformationProcess.Call(uintptr(h), processCommandLineInformation, 0, 0, uintptr(unsafe.Pointer(&size)))
+	if status != statusInfoLengthMismatch || size == 0 {
+		return "", fmt.Errorf("NtQueryInformationProcess: status 0x%x", status)
+	}
+
+	// Use a uint64 backing array so the UNICODE_STRING header is aligned
+	buf := make([]uint64, (size+7)/8)
+	status, _, _ = api.procNtQueryInformationProcess.Call(uintptr(h), processCommandLineInformation, uintptr(unsafe.Pointer(&buf[0])), uintptr(size), uintptr(unsafe.Pointer(&size)))
+	if status != 0 {
+		return "", fmt.Errorf("NtQueryInformationProcess: status 0x%x", status)
+	}
+
+	us := (*unicodeString)(unsafe.Pointer(&buf[0]))
+	if us.Buffer == nil || us.Length == 0 {
+		return "", nil
+	}
+	return syscall.UTF16ToString(unsafe.Slice(us.Buffer, us.Length/2)), nil
+}
+
+// GetProcessUser gets the DOMAIN\user account a process runs as
+func (api *API) GetProcessUser(h syscall.Handle) (string, error) {
+	var token syscall.Token
+	if err := syscall.OpenProcessToken(h, syscall.TOKEN_QUERY, &token); err != nil {
+		return "", err
+	}
+	defer token.Close()
+
+	tokenUser, err := token.GetTokenUser()
+	if err != nil {
+		return "", err
+	}
+
+	account, domain, _, err := tokenUser.User.Sid.LookupAccount("")
+	if err != nil {
+		return "", err
+	}
+	if domain == "" {
+		return account, nil
+	}
+	return domain + `\` + account, nil
+}
+
+// IsProcessElevated reports whether a process runs with an elevated token
+func (api *API) IsProcessElevated(h syscall.Handle) (bool, error) {
+	var token syscall.Token
+	if err := syscall.OpenProcessToken(h, syscall.TOKEN_QUERY, &token); err != nil {
+		return false, err
+	}
+	defer token.Close()
+
+	var elevated, size uint32
+	if err := syscall.GetTokenInformation(token, tokenElevation, (*byte)(unsafe.Pointer(&elevated)), uint32(unsafe.Sizeof(elevated)), &size); err != nil {
+		return false, err
+	}
+	return elevated != 0, nil
+}
+
+// GetProcessArchitecture returns the architecture a process runs as (x86, x64, arm, arm64)
+func (api *API) GetProcessArchitecture(h syscall.Handle) (string, error) {
+	var processMachine, nativeMachine uint16
+	ret, _, _ := api.procIsWow64Process2.Call(uintptr(h), uintptr(unsafe.Pointer(&processMachine)), uintptr(unsafe.Pointer(&nativeMachine)))
+	if ret == 0 {
+		return "", syscall.GetLastError()
+	}
+
+	// IMAGE_FILE_MACHINE_UNKNOWN means the process is not running under WOW64
+	machine := processMachine
+	if machine == IMAGE_FILE_MACHINE_UNKNOWN {
+		machine = nativeMachine
+	}
+
+	switch machine {
+	case IMAGE_FILE_MACHINE_I386:
+		return "x86", nil
+	case IMAGE_FILE_MACHINE_ARMNT:
+		return "arm", nil
+	case IMAGE_FILE_MACHINE_AMD64:
+		return "x64", nil
+	case IMAGE_FILE_MACHINE_ARM64:
+		return "arm64", nil
+	}
+	return fmt.Sprintf("0x%04x", machine), nil
+}
+
+// ProcessEntry is a process in the system process snapshot
+type ProcessEntry struct {
+	PID       int
+	ParentPID int
+	ExeFile   string
+}
+
+// GetProcessEntries returns all running processes with their parent PID, keyed by PID
+func (api *API) GetProcessEntries() (map[int]ProcessEntry, error) {
+	snapshot, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(snapshot)
+
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	if err := syscall.Process32First(snapshot, &entry); err != nil {
+		return nil, err
+	}
+
+	entries := make(map[int]ProcessEntry)
+	for {
+		entries[int(entry.ProcessID)] = ProcessEntry{
+			PID:       int(entry.ProcessID),
+			ParentPID: int(entry.ParentProcessID),
+			ExeFile:   syscall.UTF16ToString(entry.ExeFile[:]),
+		}
+		if err := syscall.Process32Next(snapshot, &entry); err != nil {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// GetGuiResources object types
+const (
+	GR_GDIOBJECTS  = 0
+	GR_USEROBJECTS = 1
+)
+
+// processMemoryCountersEx mirrors the Win32 PROCESS_MEMORY_COUNTERS_EX structure
+type processMemoryCountersEx struct {
+	cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+	PrivateUsage               uintptr
+}
+
+// ProcessCounters are resource counters of a process at one point in time
+type ProcessCounters struct {
+	CPUTime      time.Duration
+	WorkingSet   uint64
+	PrivateBytes uint64
+	Handles      uint32
+	GDIObjects   uint32
+	UserObjects  uint32
+}
+
+// GetProcessCounters reads the CPU time, memory, handle and GUI object
+// counters of a process. The handle needs limited query rights.
+func (api *API) GetProcessCounters(h syscall.Handle) (ProcessCounters, error) {
+	var counters ProcessCounters
+
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return counters, err
+	}
+	counters.CPUTime = filetimeDuration(kernel) + filetimeDuration(user)
+
+	var mem processMemoryCountersEx
+	mem.cb = uint32(unsafe.Sizeof(mem))
+	ret, _, _ := api.procK32GetProcessMemoryInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&mem)), uintptr(mem.cb))
+	if ret == 0 {
+		return counters, syscall.GetLastError()
+	}
+	counters.WorkingSet = uint64(mem.WorkingSetSize)
+	counters.PrivateBytes = uint64(mem.PrivateUsage)
+
+	var handles uint32
+	if ret, _, _ := api.procGetProcessHandleCount.Call(uintptr(h), uintptr(unsafe.Pointer(&handles))); ret != 0 {
+		counters.Handles = handles
+	}
+
+	gdi, _, _ := api.procGetGuiResources.Call(uintptr(h), GR_GDIOBJECTS)
+	userObjects, _, _ := api.procGetGuiResources.Call(uintptr(h), GR_USEROBJECTS)
+	counters.GDIObjects = uint32(gdi)
+	counters.UserObjects = uint32(userObjects)
+
+	return counters, nil
+}
+
+// filetimeDuration converts a FILETIME holding a duration in 100ns units.
+// Filetime.Nanoseconds cannot be used, it subtracts the 1601 epoch.
+func filetimeDuration(ft syscall.Filetime) time.Duration {
+	return time.Duration(int64(ft.HighDateTime)<<32|int64(ft.LowDateTime)) * 100
+}
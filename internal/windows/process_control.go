@@ -0,0 +1,122 @@
+package windows
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Process control access rights, priority classes and messages
+const (
+	PROCESS_TERMINATE         = 0x0001
+	PROCESS_SET_INFORMATION   = 0x0200
+	PROCESS_QUERY_INFORMATION = 0x0400
+	PROCESS_SUSPEND_RESUME    = 0x0800
+	SYNCHRONIZE               = 0x00100000
+
+	IDLE_PRIORITY_CLASS         = 0x00000040
+	BELOW_NORMAL_PRIORITY_CLASS = 0x00004000
+	NORMAL_PRIORITY_CLASS       = 0x00000020
+	ABOVE_NORMAL_PRIORITY_CLASS = 0x00008000
+	HIGH_PRIORITY_CLASS         = 0x00000080
+	REALTIME_PRIORITY_CLASS     = 0x00000100
+
+	WM_CLOSE = 0x0010
+
+	statusAccessDenied = 0xC0000022
+)
+
+// OpenProcessWithAccess opens a process handle with the given access rights.
+// The caller must close the handle with syscall.CloseHandle.
+func (api *API) OpenProcessWithAccess(pid int, access uint32) (syscall.Handle, error) {
+	return syscall.OpenProcess(access, false, uint32(pid))
+}
+
+// TerminateProcess ends a process immediately with the given exit code
+func (api *API) TerminateProcess(h syscall.Handle, exitCode uint32) error {
+	return syscall.TerminateProcess(h, exitCode)
+}
+
+// WaitForProcessExit waits until a process has exited or the timeout passes.
+// It reports whether the process exited. The handle needs SYNCHRONIZE access.
+func (api *API) WaitForProcessExit(h syscall.Handle, timeout time.Duration) (bool, error) {
+	event, err := syscall.WaitForSingleObject(h, uint32(timeout.Milliseconds()))
+	switch {
+	case err != nil:
+		return false, err
+	case event == syscall.WAIT_OBJECT_0:
+		return true, nil
+	}
+	return false, nil
+}
+
+// SuspendProcess suspends every thread of a process
+func (api *API) SuspendProcess(h syscall.Handle) error {
+	status, _, _ := api.procNtSuspendProcess.Call(uintptr(h))
+	return ntStatusError("NtSuspendProcess", status)
+}
+
+// ResumeProcess resumes every thread of a suspended process
+func (api *API) ResumeProcess(h syscall.Handle) error {
+	status, _, _ := api.procNtResumeProcess.Call(uintptr(h))
+	return ntStatusError("NtResumeProcess", status)
+}
+
+// ntStatusError converts an NTSTATUS to an error, mapping access denied to
+// syscall.ERROR_ACCESS_DENIED so callers can test for it with errors.Is
+func ntStatusError(op string, status uintptr) error {
+	switch uint32(status) {
+	case 0:
+		return nil
+	case statusAccessDenied:
+		return fmt.Errorf("%s: %w", op, syscall.ERROR_ACCESS_DENIED)
+	}
+	return fmt.Errorf("%s: status 0x%x", op, status)
+}
+
+// GetPriorityClass gets the priority class of a process
+func (api *API) GetPriorityClass(h syscall.Handle) (uint32, error) {
+	ret, _, _ := api.procGetPriorityClass.Call(uintptr(h))
+	if ret == 0 {
+		return 0, syscall.GetLastError()
+	}
+	return uint32(ret), nil
+}
+
+// SetPriorityClass sets the priority class of a process
+func (api *API) SetPriorityClass(h syscall.Handle, class uint32) error {
+	ret, _, _ := api.procSetPriorityClass.Call(uintptr(h), uintptr(class))
+	if ret == 0 {
+		return syscall.GetLastError()
+	}
+	return nil
+}
+
+// GetProcessAffinityMask gets the CPU affinity mask of a process and of the system
+func (api *API) GetProcessAffinityMask(h syscall.Handle) (process, system uint64, err error) {
+	var processMask, systemMask uintptr
+	ret, _, _ := api.procGetProcessAffinityMask.Call(uintptr(h), uintptr(unsafe.Pointer(&processMask)), uintptr(unsafe.Pointer(&systemMask)))
+	if ret == 0 {
+		return 0, 0, syscall.GetLastError()
+	}
+	return uint64(processMask), uint64(systemMask), nil
+}
+
+// SetProcessAffinityMask sets the CPU affinity mask of a process
+func (api *API) SetProcessAffinityMask(h syscall.Handle, mask uint64) error {
+	ret, _, _ := api.procSetProcessAffinityMask.Call(uintptr(h), uintptr(mask))
+	if ret == 0 {
+		return syscall.GetLastError()
+	}
+	return nil
+}
+
+// PostClose asks a window to close by posting WM_CLOSE
+func (api *API) PostClose(hwnd syscall.Handle) error {
+	ret, _, _ := api.procPostMessageW.Call(uintptr(hwnd), WM_CLOSE, 0, 0)
+	if ret == 0 {
+		return syscall.GetLastError()
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package windows
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+// STGM_READ opens a file for reading with IPersistFile.Load
+const STGM_READ = 0x0
+
+var (
+	clsidShellLink = syscall.GUID{
+		Data1: 0x00021401, Data2: 0x0000, Data3: 0x0000,
+		Data4: [8]byte{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46},
+	}
+	iidIShellLinkW = syscall.GUID{
+		Data1: 0x000214f9, Data2: 0x0000, Data3: 0x0000,
+		Data4: [8]byte{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46},
+	}
+	iidIPersistFile = syscall.GUID{
+		Data1: 0x0000010b, Data2: 0x0000, Data3: 0x0000,
+		Data4: [8]byte{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46},
+	}
+)
+
+// shellLink is the COM IShellLinkW interface, declared up to GetPath
+type shellLink struct {
+	vtbl *shellLinkVtbl
+}
+
+type shellLinkVtbl struct {
+	iUnknownVtbl
+	GetPath uintptr
+}
+
+// persistFile is the COM IPersistFile interface, declared up to Load
+type persistFile struct {
+	vtbl *persistFileVtbl
+}
+
+type persistFileVtbl struct {
+	iUnknownVtbl
+	GetClassID uintptr
+	IsDirty    uintptr
+	Load       uintptr
+}
+
+// ShortcutTarget returns the file a .lnk shortcut points to. Shortcuts to
+// shell items without a file system path, such as Store apps, return an error.
+func (api *API) ShortcutTarget(path string) (string, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return "", err
+	}
+
+	var target string
+	err = api.withCOM(func() error {
+		obj, err := api.coCreateInstance(&clsidShellLink, &iidIShellLinkW)
+		if err != nil {
+			return err
+		}
+		link := (*shellLink)(obj)
+		defer syscall.SyscallN(link.vtbl.Release, uintptr(obj))
+
+		var fileObj unsafe.Pointer
+		hr, _, _ := syscall.SyscallN(link.vtbl.QueryInterface, uintptr(obj), uintptr(unsafe.Pointer(&iidIPersistFile)), uintptr(unsafe.Pointer(&fileObj)))
+		if int32(hr) < 0 {
+			return hresultError("QueryInterface(IPersistFile)", hr)
+		}
+		file := (*persistFile)(fileObj)
+		defer syscall.SyscallN(file.vtbl.Release, uintptr(fileObj))
+
+		hr, _, _ = syscall.SyscallN(file.vtbl.Load, uintptr(fileObj), uintptr(unsafe.Pointer(pathPtr)), STGM_READ)
+		if int32(hr) < 0 {
+			return hresultError("IPersistFile.Load", hr)
+		}
+
+		buf := make([]uint16, syscall.MAX_PATH)
+		hr, _, _ = syscall.SyscallN(link.vtbl.GetPath, uintptr(obj), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), 0, 0)
+		if int32(hr) < 0 {
+			return hresultError("IShellLink.GetPath", hr)
+		}
+		target = syscall.UTF16ToString(buf)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if target == "" {
+		return "", errors.New("shortcut has no file system target")
+	}
+	return target, nil
+}
@@ -0,0 +1,199 @@
+package windows
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"hptools/internal/models"
+)
+
+// DWM cloak reasons returned for DWMWA_CLOAKED
+const (
+	DWM_CLOAKED_APP       = 0x1
+	DWM_CLOAKED_SHELL     = 0x2
+	DWM_CLOAKED_INHERITED = 0x4
+)
+
+const virtualDesktopsKey = `Software\Microsoft\Windows\CurrentVersion\Explorer\VirtualDesktops`
+
+var (
+	clsidVirtualDesktopManager = syscall.GUID{
+		Data1: 0xaa509086, Data2: 0x5ca9, Data3: 0x4c25,
+		Data4: [8]byte{0x8f, 0x95, 0x58, 0x9d, 0x3c, 0x07, 0xb4, 0x8a},
+	}
+	iidIVirtualDesktopManager = syscall.GUID{
+		Data1: 0xa5cd92ff, Data2: 0x29be, Data3: 0x454c,
+		Data4: [8]byte{0x8d, 0x04, 0xd8, 0x28, 0x79, 0xfb, 0x3f, 0x1b},
+	}
+)
+
+// virtualDesktopManager is the documented COM IVirtualDesktopManager interface
+type virtualDesktopManager struct {
+	vtbl *virtualDesktopManagerVtbl
+}
+
+type virtualDesktopManagerVtbl struct {
+	iUnknownVtbl
+	IsWindowOnCurrentVirtualDesktop uintptr
+	GetWindowDesktopId              uintptr
+	MoveWindowToDesktop             uintptr
+}
+
+// withDesktopManager creates an IVirtualDesktopManager and passes it to fn
+func (api *API) withDesktopManager(fn func(vdm *virtualDesktopManager) error) error {
+	return api.withCOM(func() error {
+		obj, err := api.coCreateInstance(&clsidVirtualDesktopManager, &iidIVirtualDesktopManager)
+		if err != nil {
+			return err
+		}
+		vdm := (*virtualDesktopManager)(obj)
+		defer syscall.SyscallN(vdm.vtbl.Release, uintptr(obj))
+		return fn(vdm)
+	})
+}
+
+// GetWindowCloak returns the DWM_CLOAKED_* reasons a window is hidden, or 0
+func (api *API) GetWindowCloak(hwnd syscall.Handle) uint32 {
+	var cloaked uint32
+	ret, _, _ := api.procDwmGetWindowAttribute.Call(uintptr(hwnd), DWMWA_CLOAKED, uintptr(unsafe.Pointer(&cloaked)), unsafe.Sizeof(cloaked))
+	if ret != 0 {
+		return 0
+	}
+	return cloaked
+}
+
+// GetWindowDesktopID gets the ID of the virtual desktop a window is on.
+// Windows that are not on any desktop return an empty string.
+func (api *API) GetWindowDesktopID(hwnd syscall.Handle) (string, error) {
+	var id syscall.GUID
+	err := api.withDesktopManager(func(vdm *virtualDesktopManager) error {
+		hr, _, _ := syscall.SyscallN(vdm.vtbl.GetWindowDesktopId, uintptr(unsafe.Pointer(vdm)), uintptr(hwnd), uintptr(unsafe.Pointer(&id)))
+		if int32(hr) < 0 {
+			return hresultError("GetWindowDesktopId", hr)
+		}
+		return nil
+	})
+	if err != nil || id == (syscall.GUID{}) {
+		return "", err
+	}
+	return guidString(id), nil
+}
+
+// GetVirtualDesktops lists virtual desktops in task view order. There is no
+// documented API for this, so the list is read from Explorer's registry state.
+// Explorer only records desktops once a second one has been created, so a
+// missing list is reported as the single current desktop.
+func (api *API) GetVirtualDesktops() ([]models.VirtualDesktop, error) {
+	data, err := regQueryBinary(virtualDesktopsKey, "VirtualDesktopIDs")
+	if err != nil && !errors.Is(err, syscall.ERROR_FILE_NOT_FOUND) {
+		return nil, fmt.Errorf("reading desktop IDs: %w", err)
+	}
+
+	current := api.currentDesktopID()
+	if len(data) < 16 {
+		return []models.VirtualDesktop{{ID: current, Name: "Desktop 1", Current: true}}, nil
+	}
+
+	desktops := make([]models.VirtualDesktop, 0, len(data)/16)
+	for i := 0; i+16 <= len(data); i += 16 {
+		id := guidString(*(*syscall.GUID)(unsafe.Pointer(&data[i])))
+		name, err := regQueryString(virtualDesktopsKey+`\Desktops\`+id, "Name")
+		if err != nil || name == "" {
+			name = fmt.Sprintf("Desktop %d", len(desktops)+1)
+		}
+		desktops = append(desktops, models.VirtualDesktop{
+			ID:      id,
+			Index:   len(desktops),
+			Name:    name,
+			Current: id == current,
+		})
+	}
+	return desktops, nil
+}
+
+// currentDesktopID reads the current desktop; Windows 11 stores it in the
+// VirtualDesktops key, Windows 10 per session under SessionInfo
+func (api *API) currentDesktopID() string {
+	data, err := regQueryBinary(virtualDesktopsKey, "CurrentVirtualDesktop")
+	if err != nil {
+		var session uint32
+		ret, _, _ := api.procProcessIdToSessionId.Call(uintptr(os.Getpid()), uintptr(unsafe.Pointer(&session)))
+		if ret == 0 {
+			return ""
+		}
+		key := fmt.Sprintf(`Software\Microsoft\Windows\CurrentVersion\Explorer\SessionInfo\%d\VirtualDesktops`, session)
+		if data, err = regQueryBinary(key, "CurrentVirtualDesktop"); err != nil {
+			return ""
+		}
+	}
+	if len(data) < 16 {
+		return ""
+	}
+	return guidString(*(*syscall.GUID)(unsafe.Pointer(&data[0])))
+}
+
+// regQueryBinary reads a REG_BINARY value from HKEY_CURRENT_USER
+func regQueryBinary(path, name string) ([]byte, error) {
+	data, typ, err := regQueryValue(path, name)
+	if err != nil {
+		return nil, err
+	}
+	if typ != syscall.REG_BINARY {
+		return nil, fmt.Errorf("registry value %s is not binary", name)
+	}
+	return data, nil
+}
+
+// regQueryString reads a REG_SZ value from HKEY_CURRENT_USER
+func regQueryString(path, name string) (string, error) {
+	data, typ, err := regQueryValue(path, name)
+	if err != nil {
+		return "", err
+	}
+	if typ != syscall.REG_SZ || len(data) < 2 {
+		return "", fmt.Errorf("registry value %s is not a string", name)
+	}
+	return syscall.UTF16ToString(unsafe.Slice((*uint16)(unsafe.Pointer(&data[0])), len(data)/2)), nil
+}
+
+// regQueryValue reads a raw registry value from HKEY_CURRENT_USER
+func regQueryValue(path, name string) ([]byte, uint32, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	namePtr, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var key syscall.Handle
+	if err := syscall.RegOpenKeyEx(syscall.HKEY_CURRENT_USER, pathPtr, 0, syscall.KEY_READ, &key); err != nil {
+		return nil, 0, err
+	}
+	defer syscall.RegCloseKey(key)
+
+	var typ, size uint32
+	if err := syscall.RegQueryValueEx(key, namePtr, nil, &typ, nil, &size); err != nil {
+		return nil, 0, err
+	}
+	if size == 0 {
+		return nil, typ, nil
+	}
+	data := make([]byte, size)
+	if err := syscall.RegQueryValueEx(key, namePtr, nil, &typ, &data[0], &size); err != nil {
+		return nil, 0, err
+	}
+	return data[:size], typ, nil
+}
+
+// guidString formats a GUID in registry form, e.g. {AA509086-5CA9-4C25-8F95-589D3C07B48A}
+func guidString(g syscall.GUID) string {
+	return fmt.Sprintf("{%08X-%04X-%04X-%02X%02X-%02X%02X%02X%02X%02X%02X}",
+		g.Data1, g.Data2, g.Data3,
+		g.Data4[0], g.Data4[1], g.Data4[2], g.Data4[3],
+		g.Data4[4], g.Data4[5], g.Data4[6], g.Data4[7])
+}
@@ -0,0 +1,147 @@
+package windows
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// WinEvent constants
+const (
+	EVENT_SYSTEM_FOREGROUND     = 0x0003
+	EVENT_SYSTEM_MINIMIZESTART  = 0x0016
+	EVENT_SYSTEM_MINIMIZEEND    = 0x0017
+	EVENT_OBJECT_CREATE         = 0x8000
+	EVENT_OBJECT_DESTROY        = 0x8001
+	EVENT_OBJECT_SHOW           = 0x8002
+	EVENT_OBJECT_HIDE           = 0x8003
+	EVENT_OBJECT_LOCATIONCHANGE = 0x800B
+	EVENT_OBJECT_NAMECHANGE     = 0x800C
+
+	WINEVENT_OUTOFCONTEXT   = 0x0000
+	WINEVENT_SKIPOWNPROCESS = 0x0002
+
+	OBJID_WINDOW = 0
+	CHILDID_SELF = 0
+	GA_ROOT      = 2
+	WM_QUIT      = 0x0012
+)
+
+// WinEvent is an accessibility event reported by SetWinEventHook
+type WinEvent struct {
+	Event    uint32
+	Hwnd     syscall.Handle
+	ObjectID int32
+	ChildID  int32
+}
+
+// msg mirrors the Win32 MSG structure
+type msg struct {
+	hwnd     syscall.Handle
+	message  uint32
+	wParam   uintptr
+	lParam   uintptr
+	time     uint32
+	ptX, ptY int32
+	lPrivate uint32
+}
+
+// pumpMessages runs the message loop of the calling thread until WM_QUIT
+func (api *API) pumpMessages() {
+	var m msg
+	for {
+		ret, _, _ := api.procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+		if int32(ret) <= 0 {
+			return // WM_QUIT or error
+		}
+		api.procTranslateMessage.Call(uintptr(unsafe.Pointer(&m)))
+		api.procDispatchMessageW.Call(uintptr(unsafe.Pointer(&m)))
+	}
+}
+
+// winEventHooks routes hook callbacks to the handler of the watching API.
+// The callback is shared because Go limits the number of callbacks.
+var winEventHooks = struct {
+	sync.Mutex
+	once     sync.Once
+	callback uintptr
+	handlers map[uintptr]func(WinEvent)
+}{handlers: make(map[uintptr]func(WinEvent))}
+
+// WatchWinEvents installs out-of-context event hooks for the given
+// [min, max] event ranges and calls handler for every event. Hooks need a
+// message loop, so they run on a dedicated OS thread. handler runs on that
+// thread and must return quickly. The returned function removes the hooks.
+func (api *API) WatchWinEvents(ranges [][2]uint32, handler func(WinEvent)) (func(), error) {
+	winEventHooks.once.Do(func() {
+		winEventHooks.callback = syscall.NewCallback(func(hook uintptr, event uint32, hwnd syscall.Handle, idObject, idChild int32, thread, eventTime uint32) uintptr {
+			winEventHooks.Lock()
+			fn := winEventHooks.handlers[hook]
+			winEventHooks.Unlock()
+			if fn != nil {
+				fn(WinEvent{Event: event, Hwnd: hwnd, ObjectID: idObject, ChildID: idChild})
+			}
+			return 0
+		})
+	})
+
+	started := make(chan error, 1)
+	done := make(chan struct{})
+	var threadID uintptr
+
+	go func() {
+		defer close(done)
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		threadID, _, _ = api.procGetCurrentThreadId.Call()
+
+		var hooks []uintptr
+		defer func() {
+			winEventHooks.Lock()
+			for _, hook := range hooks {
+				api.procUnhookWinEvent.Call(hook)
+				delete(winEventHooks.handlers, hook)
+			}
+			winEventHooks.Unlock()
+		}()
+
+		// Register handlers under the lock so no event arrives before they exist
+		winEventHooks.Lock()
+		for _, r := range ranges {
+			hook, _, _ := api.procSetWinEventHook.Call(uintptr(r[0]), uintptr(r[1]), 0, winEventHooks.callback, 0, 0, WINEVENT_OUTOFCONTEXT|WINEVENT_SKIPOWNPROCESS)
+			if hook == 0 {
+				winEventHooks.Unlock()
+				started <- errors.New("SetWinEventHook failed")
+				return
+			}
+			hooks = append(hooks, hook)
+			winEventHooks.handlers[hook] = handler
+		}
+		winEventHooks.Unlock()
+		started <- nil
+
+		api.pumpMessages()
+	}()
+
+	if err := <-started; err != nil {
+		<-done
+		return nil, err
+	}
+
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			api.procPostThreadMessageW.Call(threadID, WM_QUIT, 0, 0)
+			<-done
+		})
+	}, nil
+}
+
+// GetRootWindow returns the top-level ancestor of a window (GA_ROOT)
+func (api *API) GetRootWindow(hwnd syscall.Handle) syscall.Handle {
+	ret, _, _ := api.procGetAncestor.Call(uintptr(hwnd), GA_ROOT)
+	return syscall.Handle(ret)
+}
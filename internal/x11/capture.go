@@ -0,0 +1,182 @@
+package x11
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"math/bits"
+
+	"github.com/jezek/xgb/composite"
+	"github.com/jezek/xgb/xproto"
+)
+
+// allPlanes is the plane mask that reads every bit of a pixel
+const allPlanes = 0xffffffff
+
+// pixelFormat describes how a ZPixmap image stores its pixels
+type pixelFormat struct {
+	bitsPerPixel int
+	scanlinePad  int
+	order        binary.ByteOrder
+	red          uint32
+	green        uint32
+	blue         uint32
+}
+
+// CaptureWindow captures the contents of a window. With the Composite
+// extension the window is read from its off-screen storage, so parts covered
+// by other windows are captured too; without it only the visible parts are
+// correct. Only TrueColor visuals with 24 or 32 bits per pixel are supported;
+// the alpha channel of 32-bit visuals is ignored.
+func (c *Conn) CaptureWindow(window uint32) (*image.NRGBA, error) {
+	geometry, err := xproto.GetGeometry(c.conn, xproto.Drawable(window)).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("getting geometry of window %#x: %w", window, err)
+	}
+	if geometry.Width == 0 || geometry.Height == 0 {
+		return nil, errors.New("window has no area")
+	}
+
+	drawable := xproto.Drawable(window)
+	if pixmap, release, err := c.windowPixmap(xproto.Window(window)); err == nil {
+		defer release()
+		drawable = xproto.Drawable(pixmap)
+	}
+
+	reply, err := xproto.GetImage(c.conn, xproto.ImageFormatZPixmap, drawable, 0, 0, geometry.Width, geometry.Height, allPlanes).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("reading window %#x: %w", window, err)
+	}
+
+	format, err := c.pixelFormat(reply.Depth, reply.Visual)
+	if err != nil {
+		return nil, err
+	}
+	return toNRGBA(reply.Data, int(geometry.Width), int(geometry.Height), format)
+}
+
+// windowPixmap redirects a window to off-screen storage with the Composite
+// extension and names that storage as a pixmap. release frees the pixmap and
+// ends the redirection.
+func (c *Conn) windowPixmap(window xproto.Window) (xproto.Pixmap, func(), error) {
+	c.mu.Lock()
+	if c.composite == nil {
+		err := composite.Init(c.conn)
+		if err == nil {
+			_, err = composite.QueryVersion(c.conn, 0, 2).Reply()
+		}
+		c.composite = &err
+	}
+	err := *c.composite
+	c.mu.Unlock()
+	if err != nil {
+		return 0, nil, fmt.Errorf("composite extension unavailable: %w", err)
+	}
+
+	// A compositing manager may already redirect the window manually; the
+	// automatic redirection is then refused, and its storage is used
+	redirected := composite.RedirectWindowChecked(c.conn, window, composite.RedirectAutomatic).Check() == nil
+
+	id, err := c.conn.NewId()
+	if err != nil {
+		if redirected {
+			composite.UnredirectWindow(c.conn, window, composite.RedirectAutomatic)
+		}
+		return 0, nil, err
+	}
+	pixmap := xproto.Pixmap(id)
+	if err := composite.NameWindowPixmapChecked(c.conn, window, pixmap).Check(); err != nil {
+		if redirected {
+			composite.UnredirectWindow(c.conn, window, composite.RedirectAutomatic)
+		}
+		return 0, nil, fmt.Errorf("naming window pixmap: %w", err)
+	}
+
+	return pixmap, func() {
+		xproto.FreePixmap(c.conn, pixmap)
+		if redirected {
+			composite.UnredirectWindow(c.conn, window, composite.RedirectAutomatic)
+		}
+	}, nil
+}
+
+// pixelFormat looks up the pixmap format of a depth and the color masks of a
+// visual in the connection setup
+func (c *Conn) pixelFormat(depth byte, visual xproto.Visualid) (pixelFormat, error) {
+	setup := xproto.Setup(c.conn)
+
+	format := pixelFormat{order: binary.LittleEndian}
+	if setup.ImageByteOrder != xproto.ImageOrderLSBFirst {
+		format.order = binary.BigEndian
+	}
+	for _, f := range setup.PixmapFormats {
+		if f.Depth == depth {
+			format.bitsPerPixel = int(f.BitsPerPixel)
+			format.scanlinePad = int(f.ScanlinePad)
+		}
+	}
+
+	for _, screen := range setup.Roots {
+		for _, d := range screen.AllowedDepths {
+			for _, v := range d.Visuals {
+				if v.VisualId == visual {
+					if v.Class != xproto.VisualClassTrueColor {
+						return pixelFormat{}, fmt.Errorf("unsupported visual class %d", v.Class)
+					}
+					format.red, format.green, format.blue = v.RedMask, v.GreenMask, v.BlueMask
+					return format, nil
+				}
+			}
+		}
+	}
+	return pixelFormat{}, fmt.Errorf("visual %#x not found", visual)
+}
+
+// toNRGBA converts ZPixmap data to an opaque image
+func toNRGBA(data []byte, width, height int, format pixelFormat) (*image.NRGBA, error) {
+	if format.bitsPerPixel != 24 && format.bitsPerPixel != 32 {
+		return nil, fmt.Errorf("unsupported pixel size of %d bits", format.bitsPerPixel)
+	}
+	bytesPerPixel := format.bitsPerPixel / 8
+	pad := max(format.scanlinePad/8, 1)
+	stride := (width*bytesPerPixel + pad - 1) / pad * pad
+	if len(data) < stride*(height-1)+width*bytesPerPixel {
+		return nil, fmt.Errorf("image data is %d bytes, want %d", len(data), stride*height)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		row := data[y*stride:]
+		for x := 0; x < width; x++ {
+			var pixel uint32
+			for i, b := range row[x*bytesPerPixel : (x+1)*bytesPerPixel] {
+				if format.order == binary.LittleEndian {
+					pixel |= uint32(b) << (8 * i)
+				} else {
+					pixel = pixel<<8 | uint32(b)
+				}
+			}
+
+			d := img.Pix[y*img.Stride+x*4:]
+			d[0] = channel(pixel, format.red)
+			d[1] = channel(pixel, format.green)
+			d[2] = channel(pixel, format.blue)
+			d[3] = 0xff
+		}
+	}
+	return img, nil
+}
+
+// channel extracts the 8 most significant bits of a color channel
+func channel(pixel, mask uint32) byte {
+	if mask == 0 {
+		return 0
+	}
+	value := (pixel & mask) >> bits.TrailingZeros32(mask)
+	width := bits.OnesCount32(mask)
+	if width >= 8 {
+		return byte(value >> (width - 8))
+	}
+	return byte(value << (8 - width))
+}
@@ -0,0 +1,70 @@
+package x11
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/randr"
+	"github.com/jezek/xgb/xproto"
+)
+
+// displayChangeMask selects the RandR notifications raised when a monitor is
+// connected, disconnected, rotated or moved
+const displayChangeMask = randr.NotifyMaskScreenChange | randr.NotifyMaskCrtcChange | randr.NotifyMaskOutputChange
+
+// WatchDisplayChanges calls onChange from a background goroutine whenever the
+// RandR extension reports a screen, CRTC or output change. A dock or undock
+// raises a burst of notifications. The watch uses its own connection to the
+// X server named by $DISPLAY, which stop closes.
+func WatchDisplayChanges(onChange func()) (stop func(), err error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to X server: %w", err)
+	}
+	if err := selectDisplayChanges(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			event, xerr := conn.WaitForEvent()
+			if event == nil && xerr == nil {
+				// The connection was closed
+				return
+			}
+			switch event.(type) {
+			case randr.ScreenChangeNotifyEvent, randr.NotifyEvent:
+				onChange()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			conn.Close()
+			<-done
+		})
+	}, nil
+}
+
+// selectDisplayChanges asks for RandR notifications on the root window
+func selectDisplayChanges(conn *xgb.Conn) error {
+	if err := randr.Init(conn); err != nil {
+		return fmt.Errorf("RandR extension unavailable: %w", err)
+	}
+	// Output and CRTC notifications need RandR 1.2
+	if _, err := randr.QueryVersion(conn, 1, 2).Reply(); err != nil {
+		return fmt.Errorf("querying RandR version: %w", err)
+	}
+
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+	if err := randr.SelectInputChecked(conn, root, displayChangeMask).Check(); err != nil {
+		return fmt.Errorf("selecting RandR notifications: %w", err)
+	}
+	return nil
+}
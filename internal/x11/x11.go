@@ -0,0 +1,85 @@
+// Package x11 talks to the X server for what the i3 IPC protocol does not
+// provide: the process that owns a window, window captures and monitor
+// change notifications. It uses the pure Go X protocol bindings, so it needs
+// no C libraries.
+package x11
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// Conn is a connection to the X server named by $DISPLAY
+type Conn struct {
+	conn *xgb.Conn
+	root xproto.Window
+
+	mu    sync.Mutex
+	atoms map[string]xproto.Atom
+	// composite is the result of initializing the Composite extension, nil
+	// until the first capture
+	composite *error
+}
+
+// Open connects to the X server named by $DISPLAY
+func Open() (*Conn, error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to X server: %w", err)
+	}
+	return &Conn{
+		conn:  conn,
+		root:  xproto.Setup(conn).DefaultScreen(conn).Root,
+		atoms: make(map[string]xproto.Atom),
+	}, nil
+}
+
+// Close closes the connection
+func (c *Conn) Close() {
+	c.conn.Close()
+}
+
+// WindowPID returns the process that owns a window from its _NET_WM_PID
+// property, which the client sets itself. Clients on another host and some
+// older toolkits do not set it.
+func (c *Conn) WindowPID(window uint32) (int, error) {
+	atom, err := c.atom("_NET_WM_PID")
+	if err != nil {
+		return 0, err
+	}
+
+	reply, err := xproto.GetProperty(c.conn, false, xproto.Window(window), atom, xproto.AtomCardinal, 0, 1).Reply()
+	if err != nil {
+		return 0, fmt.Errorf("reading _NET_WM_PID of window %#x: %w", window, err)
+	}
+	return decodeCardinal(reply.Format, reply.Value)
+}
+
+// atom returns the atom for a name, interning it on first use
+func (c *Conn) atom(name string) (xproto.Atom, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if atom, ok := c.atoms[name]; ok {
+		return atom, nil
+	}
+	reply, err := xproto.InternAtom(c.conn, false, uint16(len(name)), name).Reply()
+	if err != nil {
+		return 0, fmt.Errorf("interning atom %s: %w", name, err)
+	}
+	c.atoms[name] = reply.Atom
+	return reply.Atom, nil
+}
+
+// decodeCardinal decodes a single 32-bit CARDINAL property value. xgb
+// connections are little-endian, which is the order Get32 reads.
+func decodeCardinal(format byte, value []byte) (int, error) {
+	if format != 32 || len(value) < 4 {
+		return 0, errors.New("property is not set")
+	}
+	return int(xgb.Get32(value)), nil
+}
@@ -0,0 +1,87 @@
+package x11
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestDecodeCardinal(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  byte
+		value   []byte
+		want    int
+		wantErr bool
+	}{
+		{"pid", 32, []byte{0x39, 0x30, 0x00, 0x00}, 12345, false},
+		{"large pid", 32, []byte{0x00, 0x00, 0x40, 0x00}, 4194304, false},
+		{"not set", 0, nil, 0, true},
+		{"wrong format", 8, []byte{0x39, 0x30, 0x00, 0x00}, 0, true},
+		{"short value", 32, []byte{0x39, 0x30}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCardinal(tt.format, tt.value)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("decodeCardinal() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("decodeCardinal() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToNRGBA(t *testing.T) {
+	rgb888 := pixelFormat{bitsPerPixel: 32, scanlinePad: 32, order: binary.LittleEndian, red: 0xff0000, green: 0x00ff00, blue: 0x0000ff}
+	packed := rgb888
+	packed.bitsPerPixel = 24
+	bigEndian := rgb888
+	bigEndian.order = binary.BigEndian
+	rgb565 := pixelFormat{bitsPerPixel: 32, scanlinePad: 32, order: binary.LittleEndian, red: 0xf800, green: 0x07e0, blue: 0x001f}
+
+	tests := []struct {
+		name    string
+		format  pixelFormat
+		width   int
+		height  int
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{"BGRX little-endian", rgb888, 2, 1,
+			[]byte{0x30, 0x20, 0x10, 0x00, 0xff, 0x80, 0x00, 0x7f},
+			[]byte{0x10, 0x20, 0x30, 0xff, 0x00, 0x80, 0xff, 0xff}, false},
+		{"XRGB big-endian", bigEndian, 1, 1,
+			[]byte{0x00, 0x10, 0x20, 0x30},
+			[]byte{0x10, 0x20, 0x30, 0xff}, false},
+		{"24-bit pixels with padded rows", packed, 1, 2,
+			[]byte{0x30, 0x20, 0x10, 0x00, 0x03, 0x02, 0x01, 0x00},
+			[]byte{0x10, 0x20, 0x30, 0xff, 0x01, 0x02, 0x03, 0xff}, false},
+		{"5-6-5 masks keep the high bits", rgb565, 1, 1,
+			[]byte{0xff, 0xff, 0x00, 0x00},
+			[]byte{0xf8, 0xfc, 0xf8, 0xff}, false},
+		{"short data", rgb888, 2, 2, make([]byte, 12), nil, true},
+		{"unsupported depth", pixelFormat{bitsPerPixel: 16, scanlinePad: 32}, 1, 1, make([]byte, 4), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := toNRGBA(tt.data, tt.width, tt.height, tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("toNRGBA() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("toNRGBA(): %v", err)
+			}
+			if !bytes.Equal(img.Pix, tt.want) {
+				t.Errorf("toNRGBA() pixels = % x, want % x", img.Pix, tt.want)
+			}
+		})
+	}
+}
@@ -2,12 +2,20 @@ package main
 
 import (
 	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 
 	"hptools/internal/config"
 	"hptools/internal/logging"
+	"hptools/internal/metrics"
+	"hptools/internal/models"
 	"hptools/internal/services"
 	"hptools/internal/ui"
 	"hptools/internal/windows"
@@ -17,8 +25,22 @@ import (
 var assets embed.FS
 
 func main() {
+	workspace := flag.String("workspace", "", "run the named workspace, print the result as JSON and exit")
+	flag.Parse()
+
+	// Initialize Windows API
+	api := windows.NewAPI()
+
+	// Release builds use the GUI subsystem and have no console of their own
+	if *workspace != "" {
+		if err := api.AttachParentConsole(); err != nil {
+			log.Printf("Warning: Failed to attach to the parent console: %v", err)
+		}
+	}
+
 	// Load configuration
-	cfg, err := config.Load(config.GetConfigPath())
+	configPath := config.GetConfigPath()
+	cfg, err := config.Load(configPath)
 	if err != nil {
 		log.Printf("Warning: Failed to load config, using defaults: %v", err)
 		cfg = config.Default()
@@ -30,15 +52,56 @@ func main() {
 
 	appLogger.Info("Starting HP Tools", "version", "1.0.0")
 
-	// Initialize Windows API
-	api := windows.NewAPI()
-
 	// Create services
 	windowService := services.NewWindowService(api, logging.WithComponent(logger, "window_service"))
-	wailsService := services.NewWailsWindowService(windowService)
+	if err := windowService.SetFilterProfiles(cfg.Filters.Profiles, cfg.Filters.DefaultProfile); err != nil {
+		appLogger.Warn("Invalid process filters, using built-in profiles", "error", err)
+	}
+	if err := windowService.SetBoundsPolicy(cfg.Placement.BoundsPolicy); err != nil {
+		appLogger.Warn("Invalid bounds policy, rejecting offscreen rects", "error", err)
+	}
+	if err := windowService.SetSnapOptions(cfg.Placement.Snap); err != nil {
+		appLogger.Warn("Invalid snap options, snapping disabled", "error", err)
+	}
+	launcher := services.NewLauncher(api, windowService, logging.WithComponent(logger, "launcher"))
+	workspaceService := services.NewWorkspaceService(windowService, launcher, cfg.Workspaces, logging.WithComponent(logger, "workspace_service"))
+
+	// Run a workspace from the command line without starting the UI
+	if *workspace != "" {
+		os.Exit(runWorkspace(workspaceService, *workspace, appLogger))
+	}
+
+	windowWatcher := services.NewWindowWatcher(api, logging.WithComponent(logger, "window_watcher"))
+	if err := windowWatcher.Start(); err != nil {
+		appLogger.Warn("Window change events unavailable, thumbnails expire by age only", "error", err)
+	}
+	defer windowWatcher.Stop()
+	thumbnailService := services.NewThumbnailService(api, windowWatcher, logging.WithComponent(logger, "thumbnail_service"))
+	sampler := metrics.NewSampler(metrics.NewWindowsCollector(api), time.Duration(cfg.Metrics.IntervalMS)*time.Millisecond, logging.WithComponent(logger, "metrics"))
+
+	// Trigger notifications are posted to the frontend, which is created below
+	var app *application.App
+	triggerLogger := logging.WithComponent(logger, "triggers")
+	triggerService := services.NewTriggerService(api, windowService, windowWatcher, func(n models.TriggerNotification) {
+		app.Event.Emit(services.TriggerEventName, n)
+		if err := services.ShowTriggerToast(cfg.App.Name, n); err != nil {
+			triggerLogger.Warn("Failed to show notification", "trigger", n.Trigger, "error", err)
+		}
+	}, triggerLogger)
+	if err := triggerService.SetTriggers(cfg.Triggers); err != nil {
+		appLogger.Warn("Invalid window triggers, none are active", "error", err)
+	}
+
+	wailsService := services.NewWailsWindowService(windowService, thumbnailService, sampler, launcher, workspaceService, triggerService)
+	layoutService := services.NewLayoutService(windowService, launcher, cfg.Layouts, logging.WithComponent(logger, "layout_service"))
+	displayWatcher := services.NewDisplayWatcher(api, layoutService, cfg.DisplayLayouts, logging.WithComponent(logger, "display_watcher"))
+	if err := displayWatcher.Start(); err != nil {
+		appLogger.Warn("Display changes unavailable, layouts will not follow monitor setups", "error", err)
+	}
+	defer displayWatcher.Stop()
 
 	// Create Wails application
-	app := application.New(application.Options{
+	app = application.New(application.Options{
 		Name:        cfg.App.Name,
 		Description: cfg.App.Description,
 		Services: []application.Service{
@@ -52,27 +115,107 @@ func main() {
 		},
 	})
 
-	// Create main window
-	win := app.Window.NewWithOptions(application.WebviewWindowOptions{
-		Title:     cfg.Window.Title,
-		Width:     cfg.Window.Width,
-		Height:    cfg.Window.Height,
-		MinWidth:  cfg.Window.MinWidth,
-		MinHeight: cfg.Window.MinHeight,
-		MaxWidth:  cfg.Window.MaxWidth,
-		Mac: application.MacWindow{
-			InvisibleTitleBarHeight: cfg.Window.Mac.InvisibleTitleBarHeight,
-			Backdrop:                cfg.Window.Mac.Backdrop,
-			TitleBar:                cfg.Window.Mac.TitleBar,
-		},
-		BackgroundColour: cfg.Window.BackgroundColour,
-		URL:              "/",
-	})
+	// Act on window title and class changes once the application exists
+	triggerService.Start()
+	defer triggerService.Stop()
+
+	focusFollower := services.NewFocusFollower(api, logging.WithComponent(logger, "focus_follower"))
+	if err := focusFollower.SetOptions(cfg.FocusFollowsMouse); err != nil {
+		appLogger.Warn("Invalid focus follows mouse settings, disabled", "error", err)
+	}
+	defer focusFollower.Stop()
+	windowDragger := services.NewWindowDragger(api, logging.WithComponent(logger, "window_dragger"))
+	if err := windowDragger.SetOptions(cfg.WindowDrag); err != nil {
+		appLogger.Warn("Window dragging unavailable", "error", err)
+	}
+	defer windowDragger.Stop()
+	tiler := services.NewTiler(api, windowWatcher, logging.WithComponent(logger, "tiler"))
+	if err := tiler.SetOptions(cfg.Tiling); err != nil {
+		appLogger.Warn("Invalid tiling settings, tiling disabled", "error", err)
+	}
+	defer tiler.Stop()
+	scriptEngine := services.NewScriptEngine(api, windowService, windowWatcher, time.Duration(cfg.Scripts.TimeoutMS)*time.Millisecond, logging.WithComponent(logger, "scripts"))
+	if err := scriptEngine.Load(scriptsDir(cfg, configPath)); err != nil {
+		appLogger.Warn("Some scripts failed to load", "error", err)
+	}
+	scriptEngine.Start()
+	defer scriptEngine.Stop()
+	rules := services.NewRulesPauser(triggerService, focusFollower, windowDragger, tiler, scriptEngine)
+	if err := rules.SetPaused(cfg.PauseRules); err != nil {
+		appLogger.Warn("Failed to pause rules", "error", err)
+	}
+
+	// Create main window where it was last closed, if that is still on screen
+	statePath := config.GetStatePath(configPath)
+	windowState, err := config.LoadWindowState(statePath)
+	if err != nil {
+		appLogger.Warn("Failed to load window state, using defaults", "error", err)
+	}
+	monitors, err := windowService.GetMonitors()
+	if err != nil {
+		appLogger.Warn("Failed to enumerate monitors, centering window", "error", err)
+	}
+	win := app.Window.NewWithOptions(ui.MainWindowOptions(cfg.Window, windowState, monitors))
 
 	// Setup system tray via helper (encapsulates menu & behavior)
-	cleanupTray := ui.SetupSystray(app, win, cfg.Systray)
+	cleanupTray := ui.SetupSystray(app, win, cfg.Systray, ui.SystrayDeps{
+		Windows:    windowService,
+		Layouts:    layoutService,
+		Workspaces: workspaceService,
+		Tiler:      tiler,
+		Scripts:    scriptEngine,
+		Rules:      rules,
+		PauseRules: func(paused bool) error {
+			// Keep the setting so it survives reloads and restarts
+			saved, err := config.Load(configPath)
+			if err != nil {
+				return err
+			}
+			saved.PauseRules = paused
+			return errors.Join(rules.SetPaused(paused), config.Save(saved, configPath))
+		},
+		ReloadConfig: func() error {
+			newCfg, err := config.Load(configPath)
+			if err != nil {
+				return err
+			}
+			// Apply every setting even when an earlier one is invalid
+			errs := []error{
+				windowService.SetFilterProfiles(newCfg.Filters.Profiles, newCfg.Filters.DefaultProfile),
+				windowService.SetBoundsPolicy(newCfg.Placement.BoundsPolicy),
+				windowService.SetSnapOptions(newCfg.Placement.Snap),
+				triggerService.SetTriggers(newCfg.Triggers),
+				focusFollower.SetOptions(newCfg.FocusFollowsMouse),
+				windowDragger.SetOptions(newCfg.WindowDrag),
+				tiler.SetOptions(newCfg.Tiling),
+				scriptEngine.Load(scriptsDir(newCfg, configPath)),
+				rules.SetPaused(newCfg.PauseRules),
+			}
+			layoutService.SetLayouts(newCfg.Layouts)
+			workspaceService.SetWorkspaces(newCfg.Workspaces)
+			displayWatcher.SetDisplayLayouts(newCfg.DisplayLayouts)
+			if err := errors.Join(errs...); err != nil {
+				return err
+			}
+			appLogger.Info("Configuration reloaded", "layouts", len(newCfg.Layouts))
+			return nil
+		},
+		Logger: logging.WithComponent(logger, "systray"),
+	})
 	defer cleanupTray()
 
+	// Stream process metrics to the frontend
+	if cfg.Metrics.IntervalMS > 0 {
+		sampler.OnSample(func(latest []models.ProcessMetrics) {
+			app.Event.Emit(metrics.EventName, latest)
+		})
+		sampler.Start()
+		defer sampler.Stop()
+	}
+
+	stopTrackingWindow := ui.TrackWindowState(win, statePath, windowService.GetMonitors, logging.WithComponent(logger, "window_state"))
+	defer stopTrackingWindow()
+
 	appLogger.Info("Application initialized, starting...")
 
 	// Run the application
@@ -83,3 +226,35 @@ func main() {
 
 	appLogger.Info("Application stopped")
 }
+
+// scriptsDir returns the directory scripts are loaded from, or "" to load
+// none when scripts are disabled
+func scriptsDir(cfg *config.Config, configPath string) string {
+	if !cfg.Scripts.Enabled {
+		return ""
+	}
+	return config.GetScriptsDir(configPath)
+}
+
+// runWorkspace runs a workspace for the -workspace flag and returns the exit
+// code: 0 if every app was reused, launched or skipped, 1 otherwise
+func runWorkspace(workspaces services.WorkspaceManager, name string, logger *slog.Logger) int {
+	result, err := workspaces.RunWorkspace(name)
+	if err != nil {
+		logger.Error("Failed to run workspace", "workspace", name, "error", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		logger.Error("Failed to write workspace result", "error", err)
+	}
+
+	for _, status := range result.Apps {
+		if status.State == models.WorkspaceAppFailed {
+			return 1
+		}
+	}
+	return 0
+}